@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// ErrAccountIDAccessDenied is matched by errors from RegistryArn and SchemaArn when the
+// caller is not allowed to call sts:GetCallerIdentity, for example because a service
+// control policy denies it
+var ErrAccountIDAccessDenied = errors.New("access to sts:GetCallerIdentity was denied")
+
+// errCodeAccessDenied is the error code STS returns when a policy denies a call
+const errCodeAccessDenied = "AccessDenied"
+
+// WithSTSAPI resolves the caller's AWS account ID for RegistryArn and SchemaArn with api.
+// By default an STS client is created from the configuration of the Glue client.
+func WithSTSAPI(api stsiface.STSAPI) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.sts = api
+	}
+}
+
+// RegistryArn returns the ARN of the client's registry, built from the region of the Glue
+// client and the caller's AWS account ID. The account ID is looked up with STS
+// GetCallerIdentity on first use and cached. The region is only known for Glue clients
+// created by the AWS SDK, including those of the session-based constructors.
+func (c *GlueSchemaRegistryClient) RegistryArn() (string, error) {
+	return c.RegistryArnWithContext(context.Background())
+}
+
+// RegistryArnWithContext is like RegistryArn but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) RegistryArnWithContext(ctx context.Context) (string, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return "", err
+	}
+	region, accountID, err := c.regionAndAccount(ctx)
+	if err != nil {
+		return "", err
+	}
+	return RegistryARN(region, accountID, registryName), nil
+}
+
+// SchemaArn returns the ARN of a schema in the client's registry, resolved as for RegistryArn
+func (c *GlueSchemaRegistryClient) SchemaArn(schemaName string) (string, error) {
+	return c.SchemaArnWithContext(context.Background(), schemaName)
+}
+
+// SchemaArnWithContext is like SchemaArn but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) SchemaArnWithContext(ctx context.Context, schemaName string) (string, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return "", err
+	}
+	region, accountID, err := c.regionAndAccount(ctx)
+	if err != nil {
+		return "", err
+	}
+	return SchemaARN(region, accountID, registryName, schemaName), nil
+}
+
+// regionAndAccount returns the region of the Glue client and the caller's account ID,
+// calling STS only until an account ID has been resolved
+func (c *GlueSchemaRegistryClient) regionAndAccount(ctx context.Context) (string, string, error) {
+	if _, err := c.api(); err != nil {
+		return "", "", err
+	}
+	glueClient, ok := c.glueClient.(*glue.Glue)
+	if !ok || aws.StringValue(glueClient.Config.Region) == "" {
+		return "", "", &SchemaRegistryException{
+			Message: "Failed to build ARN",
+			Err:     fmt.Errorf("the region of the Glue client %T is unknown", c.glueClient),
+		}
+	}
+	region := aws.StringValue(glueClient.Config.Region)
+
+	c.mu.RLock()
+	accountID := c.accountID
+	c.mu.RUnlock()
+	if accountID != "" {
+		return region, accountID, nil
+	}
+
+	api := c.sts
+	if api == nil {
+		// The Glue client's config carries its credentials; its endpoint, if any, is Glue's
+		config := glueClient.Config.Copy().WithEndpoint("")
+		sess, err := session.NewSession(config)
+		if err != nil {
+			return "", "", &SchemaRegistryException{Message: "Failed to resolve AWS account ID", Err: err}
+		}
+		api = sts.New(sess)
+	}
+	identity, err := api.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		code := errorCode(err)
+		if code == errCodeAccessDenied {
+			err = fmt.Errorf("%w: %w", ErrAccountIDAccessDenied, err)
+		}
+		return "", "", &SchemaRegistryException{
+			Message:   "Failed to resolve AWS account ID",
+			Err:       err,
+			ErrorCode: code,
+		}
+	}
+
+	accountID = aws.StringValue(identity.Account)
+	c.mu.Lock()
+	c.accountID = accountID
+	c.mu.Unlock()
+	return region, accountID, nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// DefaultBatchConcurrency is the number of concurrent Glue calls made by batch methods
+// unless WithBatchConcurrency is used
+const DefaultBatchConcurrency = 8
+
+// WithBatchConcurrency limits the number of concurrent Glue calls made by batch methods
+// such as GetSchemaVersions. Values less than one are treated as one.
+func WithBatchConcurrency(n int) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.batchConcurrency = n
+	}
+}
+
+// GetSchemaVersions fetches several versions of a schema concurrently. Fetched versions are
+// returned keyed by version number, and every version that could not be fetched has one
+// error in the returned slice, in the order the versions were requested. Duplicate version
+// numbers are fetched once.
+func (c *GlueSchemaRegistryClient) GetSchemaVersions(schemaName string, versions []int64) (map[int64]*glue.GetSchemaVersionOutput, []error) {
+	return c.GetSchemaVersionsWithContext(context.Background(), schemaName, versions)
+}
+
+// GetSchemaVersionsWithContext is like GetSchemaVersions but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaVersionsWithContext(ctx context.Context, schemaName string, versions []int64) (map[int64]*glue.GetSchemaVersionOutput, []error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return map[int64]*glue.GetSchemaVersionOutput{}, []error{err}
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	var unique []int64
+	seen := make(map[int64]bool)
+	for _, v := range versions {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+
+	workers := c.batchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+
+	outputs := make([]*glue.GetSchemaVersionOutput, len(unique))
+	errs := make([]error, len(unique))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				outputs[i], errs[i] = c.GetSchemaVersionWithContext(ctx, schemaName, unique[i])
+			}
+		}()
+	}
+	for i := range unique {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	results := make(map[int64]*glue.GetSchemaVersionOutput, len(unique))
+	var failures []error
+	for i, v := range unique {
+		if errs[i] != nil {
+			failures = append(failures, errs[i])
+			continue
+		}
+		results[v] = outputs[i]
+	}
+	return results, failures
+}
@@ -1,109 +1,459 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 )
 
 // Compatibility represents schema compatibility modes
 type Compatibility string
 
 const (
-	CompatibilityBackward     Compatibility = "BACKWARD"
+	CompatibilityBackward    Compatibility = "BACKWARD"
 	CompatibilityBackwardAll Compatibility = "BACKWARD_ALL"
 	CompatibilityDisabled    Compatibility = "DISABLED"
-	CompatibilityForward      Compatibility = "FORWARD"
-	CompatibilityForwardAll   Compatibility = "FORWARD_ALL"
+	CompatibilityForward     Compatibility = "FORWARD"
+	CompatibilityForwardAll  Compatibility = "FORWARD_ALL"
 	CompatibilityFull        Compatibility = "FULL"
-	CompatibilityFullAll    Compatibility = "FULL_ALL"
-	CompatibilityNone         Compatibility = "NONE"
+	CompatibilityFullAll     Compatibility = "FULL_ALL"
+	CompatibilityNone        Compatibility = "NONE"
 )
 
+// DataFormat represents the data formats a schema can be registered with
+type DataFormat string
+
+const (
+	DataFormatAvro     DataFormat = "AVRO"
+	DataFormatJSON     DataFormat = "JSON"
+	DataFormatProtobuf DataFormat = "PROTOBUF"
+)
+
+// validate reports an error matching ErrInvalidDataFormat if d is not one of the DataFormat constants
+func (d DataFormat) validate() error {
+	switch d {
+	case DataFormatAvro, DataFormatJSON, DataFormatProtobuf:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, expected AVRO, JSON or PROTOBUF", ErrInvalidDataFormat, string(d))
+	}
+}
+
 // SchemaRegistryException is a custom exception for Glue Schema Registry operations
 type SchemaRegistryException struct {
 	Message string
 	Err     error
+	// ErrorCode is the code of the AWS error behind a failed Glue call, such as
+	// "EntityNotFoundException" or "ThrottlingException". It is empty when the failure
+	// did not come from AWS.
+	ErrorCode string
 }
 
+// Error formats the exception with the function installed by SetErrorFormatter,
+// DefaultErrorFormatter unless changed
 func (e *SchemaRegistryException) Error() string {
-	if e.Err != nil {
-		return fmt.Sprintf("%s: %v", e.Message, e.Err)
-	}
-	return e.Message
+	return (*errorFormatter.Load())(e)
 }
 
-// GlueSchemaRegistryClient is a wrapper client for AWS Glue Schema Registry
+// Unwrap returns the underlying error, typically an awserr.Error from the AWS SDK
+func (e *SchemaRegistryException) Unwrap() error {
+	return e.Err
+}
+
+// GlueSchemaRegistryClient is a wrapper client for AWS Glue Schema Registry.
+//
+// A GlueSchemaRegistryClient is safe for concurrent use by multiple goroutines once
+// constructed, including its caches and lazy session creation, and should be shared
+// rather than created per request. Options must not be applied after construction.
+// Callbacks and recorders passed as options, such as WithLogger and WithMetrics, may be
+// called from several goroutines at once.
 type GlueSchemaRegistryClient struct {
-	glueClient   *glue.Glue
+	glueClient   glueiface.GlueAPI
 	registryName string
+
+	connect     func() (glueiface.GlueAPI, error)
+	connectOnce sync.Once
+	connectErr  error
+	lazySession bool
+
+	registryResolver   RegistryResolver
+	onSchemaRegistered SchemaRegisteredFunc
+	onQuotaExceeded    QuotaExceededFunc
+	maxVersions        int
+	batchConcurrency   int
+	retry              *awsclient.DefaultRetryer
+	logger             Logger
+	metrics            MetricsRecorder
+	logf               func(format string, args ...interface{})
+
+	normalizeDefinitions bool
+	sts                  stsiface.STSAPI
+	clock                Clock
+
+	cacheVersions bool
+	cacheSize     int
+	latestTTL     time.Duration
+
+	mu          sync.RWMutex
+	dataFormats map[string]string
+	versions    *versionCache
+	accountID   string
 }
 
-// NewGlueSchemaRegistryClient creates a new GlueSchemaRegistryClient with default AWS credentials
-func NewGlueSchemaRegistryClient(region, registryName string) (*GlueSchemaRegistryClient, error) {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(region),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+// NewGlueSchemaRegistryClient creates a new GlueSchemaRegistryClient with default AWS credentials.
+// The AWS session is created before returning unless WithLazySession is used.
+// Use NewGlueSchemaRegistryClientWithConfig for profiles, assumed roles or custom endpoints.
+func NewGlueSchemaRegistryClient(region, registryName string, opts ...Option) (*GlueSchemaRegistryClient, error) {
+	return NewGlueSchemaRegistryClientWithConfig(ClientConfig{Region: region, RegistryName: registryName}, opts...)
+}
+
+// NewGlueSchemaRegistryClientWithAPI creates a new GlueSchemaRegistryClient backed by the given Glue API,
+// for example a client built from a custom session or a test double
+func NewGlueSchemaRegistryClientWithAPI(glueClient glueiface.GlueAPI, registryName string, opts ...Option) *GlueSchemaRegistryClient {
+	return newClient(glueClient, registryName, opts)
+}
+
+func newClient(glueClient glueiface.GlueAPI, registryName string, opts []Option) *GlueSchemaRegistryClient {
+	c := &GlueSchemaRegistryClient{
+		glueClient:       glueClient,
+		registryName:     registryName,
+		dataFormats:      make(map[string]string),
+		logf:             log.Printf,
+		batchConcurrency: DefaultBatchConcurrency,
+		clock:            systemClock{},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	if c.cacheVersions {
+		c.versions = newVersionCache(c.cacheSize)
+	}
+	return c
+}
 
-	return &GlueSchemaRegistryClient{
-		glueClient:   glue.New(sess),
-		registryName: registryName,
-	}, nil
+// GlueClient returns the underlying Glue API client so that operations not wrapped by
+// GlueSchemaRegistryClient can be called with the same session and configuration.
+// Errors returned by calls made through it are the raw AWS SDK errors and are not
+// wrapped in SchemaRegistryException. With WithLazySession, calling GlueClient creates
+// the session, and nil is returned if that fails.
+func (c *GlueSchemaRegistryClient) GlueClient() glueiface.GlueAPI {
+	if _, err := c.api(); err != nil {
+		return nil
+	}
+	return c.glueClient
+}
+
+// CreateSchema creates a new schema in the registry. A dataFormat other than the
+// DataFormat constants is rejected, with an error matching ErrInvalidDataFormat, without
+// calling Glue.
+func (c *GlueSchemaRegistryClient) CreateSchema(schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*glue.CreateSchemaOutput, error) {
+	return c.CreateSchemaWithContext(context.Background(), schemaName, dataFormat, schemaDefinition, compatibility)
+}
+
+// CreateSchemaWithContext is like CreateSchema but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) CreateSchemaWithContext(ctx context.Context, schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*glue.CreateSchemaOutput, error) {
+	return c.createSchema(ctx, schemaName, dataFormat, schemaDefinition, compatibility, "")
 }
 
-// CreateSchema creates a new schema in the registry
-func (c *GlueSchemaRegistryClient) CreateSchema(schemaName, dataFormat, schemaDefinition string, compatibility Compatibility) (*glue.CreateSchemaOutput, error) {
+// createSchema creates a schema like CreateSchemaWithContext, with description if it is
+// not empty
+func (c *GlueSchemaRegistryClient) createSchema(ctx context.Context, schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility, description string) (*glue.CreateSchemaOutput, error) {
+	if err := dataFormat.validate(); err != nil {
+		return nil, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to create schema: %s", schemaName),
+			Err:     err,
+		}
+	}
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
 	input := &glue.CreateSchemaInput{
 		RegistryId: &glue.RegistryId{
-			RegistryName: aws.String(c.registryName),
+			RegistryName: aws.String(registryName),
 		},
-		SchemaName:      aws.String(schemaName),
-		DataFormat:      aws.String(dataFormat),
-		SchemaDefinition: aws.String(schemaDefinition),
-		Compatibility:   aws.String(string(compatibility)),
+		SchemaName:       aws.String(schemaName),
+		DataFormat:       aws.String(string(dataFormat)),
+		SchemaDefinition: aws.String(c.definition(schemaDefinition)),
+		Compatibility:    aws.String(string(compatibility)),
 	}
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+
+	result, err := api.CreateSchemaWithContext(ctx, input)
+	if err != nil && c.retryAfterQuotaExceeded(ctx, schemaName, err) {
+		result, err = api.CreateSchemaWithContext(ctx, input)
+	}
+	if err != nil {
+		return nil, wrapQuotaExceeded(&SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to create schema: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		})
+	}
+
+	c.evictLatest(registryName, schemaName)
+	c.notifySchemaRegistered(schemaName, result.SchemaVersionId, result.LatestSchemaVersion)
+	return result, nil
+}
+
+// InitialVersion identifies a newly created schema and its first version
+type InitialVersion struct {
+	SchemaArn       string
+	SchemaVersionId string
+	VersionNumber   int64
+}
 
-	result, err := c.glueClient.CreateSchema(input)
+// CreateSchemaWithInitialVersion creates a schema like CreateSchema and returns the ARN of
+// the schema and the ID and number of its first version, for example to write the version
+// ID into the wire-format header
+func (c *GlueSchemaRegistryClient) CreateSchemaWithInitialVersion(schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*InitialVersion, error) {
+	return c.CreateSchemaWithInitialVersionWithContext(context.Background(), schemaName, dataFormat, schemaDefinition, compatibility)
+}
+
+// CreateSchemaWithInitialVersionWithContext is like CreateSchemaWithInitialVersion but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) CreateSchemaWithInitialVersionWithContext(ctx context.Context, schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*InitialVersion, error) {
+	result, err := c.CreateSchemaWithContext(ctx, schemaName, dataFormat, schemaDefinition, compatibility)
 	if err != nil {
+		return nil, err
+	}
+	if result.SchemaVersionId == nil {
 		return nil, &SchemaRegistryException{
 			Message: fmt.Sprintf("Failed to create schema: %s", schemaName),
-			Err:     err,
+			Err:     fmt.Errorf("response has no schema version ID"),
+		}
+	}
+	return &InitialVersion{
+		SchemaArn:       aws.StringValue(result.SchemaArn),
+		SchemaVersionId: aws.StringValue(result.SchemaVersionId),
+		VersionNumber:   aws.Int64Value(result.LatestSchemaVersion),
+	}, nil
+}
+
+// DeleteSchema deletes a schema and all of its versions. Deletion is asynchronous: the
+// returned status is DELETING until Glue finishes. A schema that does not exist is
+// reported with an error matching ErrSchemaNotFound.
+func (c *GlueSchemaRegistryClient) DeleteSchema(schemaName string) (*glue.DeleteSchemaOutput, error) {
+	return c.DeleteSchemaWithContext(context.Background(), schemaName)
+}
+
+// DeleteSchemaWithContext is like DeleteSchema but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) DeleteSchemaWithContext(ctx context.Context, schemaName string) (*glue.DeleteSchemaOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.DeleteSchemaInput{
+		SchemaId: &glue.SchemaId{
+			RegistryName: aws.String(registryName),
+			SchemaName:   aws.String(schemaName),
+		},
+	}
+
+	result, err := api.DeleteSchemaWithContext(ctx, input)
+	if err != nil {
+		if isEntityNotFound(err) {
+			return nil, &SchemaRegistryException{
+				Message:   fmt.Sprintf("Schema not found: %s", schemaName),
+				Err:       fmt.Errorf("%w: %w", ErrSchemaNotFound, err),
+				ErrorCode: errorCode(err),
+			}
+		}
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to delete schema: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
 		}
 	}
 
+	c.mu.Lock()
+	delete(c.dataFormats, registryName+"/"+schemaName)
+	c.mu.Unlock()
+	c.evictVersions(registryName, schemaName)
+
+	return result, nil
+}
+
+// DeleteSchemaVersions hard-deletes versions of a schema given as a single version ("5") or
+// a range ("5-8"). Glue can delete some of the versions and not others; each version it
+// could not delete is reported in the SchemaVersionErrors of the result, and an error is
+// only returned if the call as a whole fails. Glue never deletes the first version of a
+// schema except together with the schema, and refuses to delete a checkpoint version.
+func (c *GlueSchemaRegistryClient) DeleteSchemaVersions(schemaName, versions string) (*glue.DeleteSchemaVersionsOutput, error) {
+	return c.DeleteSchemaVersionsWithContext(context.Background(), schemaName, versions)
+}
+
+// DeleteSchemaVersionsWithContext is like DeleteSchemaVersions but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) DeleteSchemaVersionsWithContext(ctx context.Context, schemaName, versions string) (*glue.DeleteSchemaVersionsOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.DeleteSchemaVersionsInput{
+		SchemaId: &glue.SchemaId{
+			RegistryName: aws.String(registryName),
+			SchemaName:   aws.String(schemaName),
+		},
+		Versions: aws.String(versions),
+	}
+
+	result, err := api.DeleteSchemaVersionsWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to delete schema versions: %s (versions %s)", schemaName, versions),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+	c.evictVersions(registryName, schemaName)
+
 	return result, nil
 }
 
 // GetSchema gets a schema by name
 func (c *GlueSchemaRegistryClient) GetSchema(schemaName string) (*glue.GetSchemaOutput, error) {
+	return c.GetSchemaWithContext(context.Background(), schemaName)
+}
+
+// GetSchemaWithContext is like GetSchema but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaWithContext(ctx context.Context, schemaName string) (*glue.GetSchemaOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema, ok := c.cachedSchema(registryName, schemaName); ok {
+		return schema, nil
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
 	input := &glue.GetSchemaInput{
 		SchemaId: &glue.SchemaId{
-			RegistryName: aws.String(c.registryName),
+			RegistryName: aws.String(registryName),
 			SchemaName:   aws.String(schemaName),
 		},
 	}
 
-	result, err := c.glueClient.GetSchema(input)
+	result, err := api.GetSchemaWithContext(ctx, input)
 	if err != nil {
 		return nil, &SchemaRegistryException{
-			Message: fmt.Sprintf("Failed to get schema: %s", schemaName),
-			Err:     err,
+			Message:   fmt.Sprintf("Failed to get schema: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
 		}
 	}
+	c.cacheSchema(registryName, schemaName, result)
 
 	return result, nil
 }
 
-// GetSchemaVersion gets a specific version of a schema
+// SchemaExists reports whether a schema exists in the registry. A missing schema gives
+// false and a nil error; any other failure, such as throttling or access denied, is
+// returned as an error.
+func (c *GlueSchemaRegistryClient) SchemaExists(schemaName string) (bool, error) {
+	return c.SchemaExistsWithContext(context.Background(), schemaName)
+}
+
+// SchemaExistsWithContext is like SchemaExists but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) SchemaExistsWithContext(ctx context.Context, schemaName string) (bool, error) {
+	_, err := c.GetSchemaWithContext(ctx, schemaName)
+	if err != nil {
+		if isEntityNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetDataFormat returns the data format (AVRO, JSON or PROTOBUF) of a schema.
+// A schema's data format cannot change after creation, so the result is cached per registry and schema name.
+func (c *GlueSchemaRegistryClient) GetDataFormat(schemaName string) (string, error) {
+	return c.GetDataFormatWithContext(context.Background(), schemaName)
+}
+
+// GetDataFormatWithContext is like GetDataFormat but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetDataFormatWithContext(ctx context.Context, schemaName string) (string, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	key := registryName + "/" + schemaName
+	c.mu.RLock()
+	dataFormat, ok := c.dataFormats[key]
+	c.mu.RUnlock()
+	if ok {
+		return dataFormat, nil
+	}
+
+	schema, err := c.GetSchemaWithContext(withRegistry(ctx, registryName), schemaName)
+	if err != nil {
+		return "", err
+	}
+	if schema.DataFormat == nil {
+		return "", &SchemaRegistryException{
+			Message: fmt.Sprintf("Schema has no data format: %s", schemaName),
+		}
+	}
+
+	c.mu.Lock()
+	c.dataFormats[key] = *schema.DataFormat
+	c.mu.Unlock()
+
+	return *schema.DataFormat, nil
+}
+
+// GetSchemaVersion gets a specific version of a schema. With WithSchemaVersionCache, a
+// version that has been fetched before is returned without calling Glue.
 func (c *GlueSchemaRegistryClient) GetSchemaVersion(schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error) {
+	return c.GetSchemaVersionWithContext(context.Background(), schemaName, versionNumber)
+}
+
+// GetSchemaVersionWithContext is like GetSchemaVersion but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaVersionWithContext(ctx context.Context, schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := versionKey{registryName: registryName, schemaName: schemaName, version: versionNumber}
+	if version, ok := c.cachedVersion(key); ok {
+		return version, nil
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
 	input := &glue.GetSchemaVersionInput{
 		SchemaId: &glue.SchemaId{
-			RegistryName: aws.String(c.registryName),
+			RegistryName: aws.String(registryName),
 			SchemaName:   aws.String(schemaName),
 		},
 		SchemaVersionNumber: &glue.SchemaVersionNumber{
@@ -111,47 +461,278 @@ func (c *GlueSchemaRegistryClient) GetSchemaVersion(schemaName string, versionNu
 		},
 	}
 
-	result, err := c.glueClient.GetSchemaVersion(input)
+	result, err := api.GetSchemaVersionWithContext(ctx, input)
 	if err != nil {
 		return nil, &SchemaRegistryException{
-			Message: fmt.Sprintf("Failed to get schema version: %s (version %d)", schemaName, versionNumber),
-			Err:     err,
+			Message:   fmt.Sprintf("Failed to get schema version: %s (version %d)", schemaName, versionNumber),
+			Err:       err,
+			ErrorCode: errorCode(err),
 		}
 	}
+	c.cacheVersion(key, result)
 
 	return result, nil
 }
 
-// ListSchemas lists all schemas in the registry
+// GetSchemaVersionById gets a schema version by its ID, such as one read from the
+// wire-format header of a payload, whichever schema and registry it belongs to
+func (c *GlueSchemaRegistryClient) GetSchemaVersionById(schemaVersionId string) (*glue.GetSchemaVersionOutput, error) {
+	return c.GetSchemaVersionByIdWithContext(context.Background(), schemaVersionId)
+}
+
+// GetSchemaVersionByIdWithContext is like GetSchemaVersionById but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaVersionByIdWithContext(ctx context.Context, schemaVersionId string) (*glue.GetSchemaVersionOutput, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.GetSchemaVersionInput{
+		SchemaVersionId: aws.String(schemaVersionId),
+	}
+
+	result, err := api.GetSchemaVersionWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to get schema version: %s", schemaVersionId),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return result, nil
+}
+
+// GetLatestSchemaDefinition returns the definition, version number and version ID of the
+// latest version of a schema, which is what the serializers need, in a single Glue call
+func (c *GlueSchemaRegistryClient) GetLatestSchemaDefinition(schemaName string) (definition string, versionNumber int64, schemaVersionID string, err error) {
+	return c.GetLatestSchemaDefinitionWithContext(context.Background(), schemaName)
+}
+
+// GetLatestSchemaDefinitionWithContext is like GetLatestSchemaDefinition but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetLatestSchemaDefinitionWithContext(ctx context.Context, schemaName string) (definition string, versionNumber int64, schemaVersionID string, err error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return "", 0, "", err
+	}
+	key := versionKey{registryName: registryName, schemaName: schemaName, version: latestVersion}
+	if version, ok := c.cachedVersion(key); ok {
+		return aws.StringValue(version.SchemaDefinition), aws.Int64Value(version.VersionNumber), aws.StringValue(version.SchemaVersionId), nil
+	}
+	api, err := c.api()
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	input := &glue.GetSchemaVersionInput{
+		SchemaId: &glue.SchemaId{
+			RegistryName: aws.String(registryName),
+			SchemaName:   aws.String(schemaName),
+		},
+		SchemaVersionNumber: &glue.SchemaVersionNumber{
+			LatestVersion: aws.Bool(true),
+		},
+	}
+
+	result, err := api.GetSchemaVersionWithContext(ctx, input)
+	if err != nil {
+		return "", 0, "", &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to get latest schema version: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+	if result.SchemaDefinition == nil || result.VersionNumber == nil {
+		return "", 0, "", &SchemaRegistryException{
+			Message: fmt.Sprintf("Schema has no latest version: %s", schemaName),
+			Err:     ErrNoLatestVersion,
+		}
+	}
+	c.cacheVersion(key, result)
+
+	return aws.StringValue(result.SchemaDefinition), aws.Int64Value(result.VersionNumber), aws.StringValue(result.SchemaVersionId), nil
+}
+
+// GetSchemaByDefinition finds the version of a schema registered with exactly this
+// definition, returning its SchemaVersionId and status without listing the versions
+func (c *GlueSchemaRegistryClient) GetSchemaByDefinition(schemaName, schemaDefinition string) (*glue.GetSchemaByDefinitionOutput, error) {
+	return c.GetSchemaByDefinitionWithContext(context.Background(), schemaName, schemaDefinition)
+}
+
+// GetSchemaByDefinitionWithContext is like GetSchemaByDefinition but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaByDefinitionWithContext(ctx context.Context, schemaName, schemaDefinition string) (*glue.GetSchemaByDefinitionOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.GetSchemaByDefinitionInput{
+		SchemaId: &glue.SchemaId{
+			RegistryName: aws.String(registryName),
+			SchemaName:   aws.String(schemaName),
+		},
+		SchemaDefinition: aws.String(c.definition(schemaDefinition)),
+	}
+
+	result, err := api.GetSchemaByDefinitionWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to get schema by definition: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return result, nil
+}
+
+// ListSchemas lists all schemas in the registry, following pagination
 func (c *GlueSchemaRegistryClient) ListSchemas() ([]*glue.SchemaListItem, error) {
+	return c.ListSchemasWithContext(context.Background())
+}
+
+// ListSchemasWithContext is like ListSchemas but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) ListSchemasWithContext(ctx context.Context) ([]*glue.SchemaListItem, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []*glue.SchemaListItem
+	var token *string
+	for {
+		page, next, err := listSchemasPage(ctx, api, registryName, 0, token)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, page...)
+		if aws.StringValue(next) == "" {
+			return schemas, nil
+		}
+		token = next
+	}
+}
+
+// ListSchemasPaginated returns a single page of at most pageSize schemas starting at token,
+// along with the token for the next page. A nil token starts from the first page, a
+// pageSize of zero uses the Glue default, and a nil next token means there are no more pages.
+func (c *GlueSchemaRegistryClient) ListSchemasPaginated(pageSize int64, token *string) ([]*glue.SchemaListItem, *string, error) {
+	return c.ListSchemasPaginatedWithContext(context.Background(), pageSize, token)
+}
+
+// ListSchemasPaginatedWithContext is like ListSchemasPaginated but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) ListSchemasPaginatedWithContext(ctx context.Context, pageSize int64, token *string) ([]*glue.SchemaListItem, *string, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, nil, err
+	}
+	return listSchemasPage(ctx, api, registryName, pageSize, token)
+}
+
+func listSchemasPage(ctx context.Context, api glueiface.GlueAPI, registryName string, pageSize int64, token *string) ([]*glue.SchemaListItem, *string, error) {
 	input := &glue.ListSchemasInput{
 		RegistryId: &glue.RegistryId{
-			RegistryName: aws.String(c.registryName),
+			RegistryName: aws.String(registryName),
 		},
+		NextToken: token,
+	}
+	if pageSize > 0 {
+		input.MaxResults = aws.Int64(pageSize)
 	}
 
-	result, err := c.glueClient.ListSchemas(input)
+	result, err := api.ListSchemasWithContext(ctx, input)
 	if err != nil {
-		return nil, &SchemaRegistryException{
-			Message: "Failed to list schemas",
-			Err:     err,
+		return nil, nil, &SchemaRegistryException{
+			Message:   "Failed to list schemas",
+			Err:       err,
+			ErrorCode: errorCode(err),
 		}
 	}
+	if aws.StringValue(result.NextToken) == "" {
+		return result.Schemas, nil, nil
+	}
+	return result.Schemas, result.NextToken, nil
+}
+
+// ListSchemaVersions lists every version of a schema, following pagination
+func (c *GlueSchemaRegistryClient) ListSchemaVersions(schemaName string) ([]*glue.SchemaVersionListItem, error) {
+	return c.ListSchemaVersionsWithContext(context.Background(), schemaName)
+}
+
+// ListSchemaVersionsWithContext is like ListSchemaVersions but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) ListSchemaVersionsWithContext(ctx context.Context, schemaName string) ([]*glue.SchemaVersionListItem, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.ListSchemaVersionsInput{
+		SchemaId: &glue.SchemaId{
+			RegistryName: aws.String(registryName),
+			SchemaName:   aws.String(schemaName),
+		},
+	}
 
-	return result.Schemas, nil
+	var versions []*glue.SchemaVersionListItem
+	for {
+		result, err := api.ListSchemaVersionsWithContext(ctx, input)
+		if err != nil {
+			return nil, &SchemaRegistryException{
+				Message:   fmt.Sprintf("Failed to list schema versions: %s", schemaName),
+				Err:       err,
+				ErrorCode: errorCode(err),
+			}
+		}
+		versions = append(versions, result.Schemas...)
+		if aws.StringValue(result.NextToken) == "" {
+			return versions, nil
+		}
+		input.NextToken = result.NextToken
+	}
 }
 
 // UpdateSchemaCompatibility updates schema compatibility mode
 func (c *GlueSchemaRegistryClient) UpdateSchemaCompatibility(schemaName string, compatibility Compatibility) (*glue.UpdateSchemaOutput, error) {
+	return c.UpdateSchemaCompatibilityWithContext(context.Background(), schemaName, compatibility)
+}
+
+// UpdateSchemaCompatibilityWithContext is like UpdateSchemaCompatibility but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) UpdateSchemaCompatibilityWithContext(ctx context.Context, schemaName string, compatibility Compatibility) (*glue.UpdateSchemaOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
 	// First get the schema to preserve description
-	schema, err := c.GetSchema(schemaName)
+	schema, err := c.GetSchemaWithContext(ctx, schemaName)
 	if err != nil {
 		return nil, err
 	}
 
 	input := &glue.UpdateSchemaInput{
 		SchemaId: &glue.SchemaId{
-			RegistryName: aws.String(c.registryName),
+			RegistryName: aws.String(registryName),
 			SchemaName:   aws.String(schemaName),
 		},
 		Compatibility: aws.String(string(compatibility)),
@@ -161,40 +742,165 @@ func (c *GlueSchemaRegistryClient) UpdateSchemaCompatibility(schemaName string,
 		input.Description = schema.Description
 	}
 
-	result, err := c.glueClient.UpdateSchema(input)
+	result, err := api.UpdateSchemaWithContext(ctx, input)
 	if err != nil {
 		return nil, &SchemaRegistryException{
-			Message: fmt.Sprintf("Failed to update schema compatibility: %s", schemaName),
-			Err:     err,
+			Message:   fmt.Sprintf("Failed to update schema compatibility: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
 		}
 	}
+	c.evictLatest(registryName, schemaName)
 
 	return result, nil
 }
 
 // RegisterSchemaVersion registers a new version of a schema
 func (c *GlueSchemaRegistryClient) RegisterSchemaVersion(schemaName, schemaDefinition string) (*glue.RegisterSchemaVersionOutput, error) {
+	return c.RegisterSchemaVersionWithContext(context.Background(), schemaName, schemaDefinition)
+}
+
+// RegisterSchemaVersionWithContext is like RegisterSchemaVersion but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionWithContext(ctx context.Context, schemaName, schemaDefinition string) (*glue.RegisterSchemaVersionOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
 	input := &glue.RegisterSchemaVersionInput{
 		SchemaId: &glue.SchemaId{
-			RegistryName: aws.String(c.registryName),
+			RegistryName: aws.String(registryName),
 			SchemaName:   aws.String(schemaName),
 		},
-		SchemaDefinition: aws.String(schemaDefinition),
+		SchemaDefinition: aws.String(c.definition(schemaDefinition)),
+	}
+
+	result, err := api.RegisterSchemaVersionWithContext(ctx, input)
+	if err != nil && c.retryAfterQuotaExceeded(ctx, schemaName, err) {
+		result, err = api.RegisterSchemaVersionWithContext(ctx, input)
+	}
+	if err != nil {
+		return nil, wrapQuotaExceeded(&SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to register schema version: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		})
+	}
+
+	c.evictLatest(registryName, schemaName)
+	c.notifySchemaRegistered(schemaName, result.SchemaVersionId, result.VersionNumber)
+	if c.maxVersions > 0 {
+		c.pruneVersions(withRegistry(ctx, registryName), schemaName)
+	}
+	return result, nil
+}
+
+// notifySchemaRegistered invokes the WithOnSchemaRegistered callback, if one is set
+func (c *GlueSchemaRegistryClient) notifySchemaRegistered(schemaName string, versionID *string, versionNumber *int64) {
+	if c.onSchemaRegistered != nil {
+		c.onSchemaRegistered(schemaName, aws.StringValue(versionID), aws.Int64Value(versionNumber))
 	}
+}
+
+// RegisteredVersion is the result of RegisterSchemaVersionDetailed
+type RegisteredVersion struct {
+	VersionNumber   int64
+	SchemaVersionId string
+	Status          string
+	// Created is false when the definition was already registered and Glue returned the
+	// existing version instead of creating one
+	Created bool
+}
+
+// RegisterSchemaVersionDetailed registers a version of a schema like RegisterSchemaVersion
+// and also reports whether a new version was created. The schema's next version number is
+// read first, which costs a GetSchema call; a version registered concurrently with the
+// same definition by another client is reported as created.
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionDetailed(schemaName, schemaDefinition string) (*RegisteredVersion, error) {
+	return c.RegisterSchemaVersionDetailedWithContext(context.Background(), schemaName, schemaDefinition)
+}
+
+// RegisterSchemaVersionDetailedWithContext is like RegisterSchemaVersionDetailed but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionDetailedWithContext(ctx context.Context, schemaName, schemaDefinition string) (*RegisteredVersion, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schema, err := c.GetSchemaWithContext(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.RegisterSchemaVersionWithContext(ctx, schemaName, schemaDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	versionNumber := aws.Int64Value(result.VersionNumber)
+	created := versionNumber > aws.Int64Value(schema.LatestSchemaVersion)
+	if schema.NextSchemaVersion != nil {
+		created = versionNumber >= *schema.NextSchemaVersion
+	}
+	return &RegisteredVersion{
+		VersionNumber:   versionNumber,
+		SchemaVersionId: aws.StringValue(result.SchemaVersionId),
+		Status:          aws.StringValue(result.Status),
+		Created:         created,
+	}, nil
+}
 
-	result, err := c.glueClient.RegisterSchemaVersion(input)
+// SchemaChangePolicy decides whether a schema may move from oldDefinition to newDefinition.
+// It returns nil to allow the change or an error describing why it is rejected.
+type SchemaChangePolicy func(oldDefinition, newDefinition string) error
+
+// RegisterSchemaVersionIf registers a new version of a schema only if policy accepts the
+// change from the current latest version. A rejection is returned as a SchemaRegistryException
+// wrapping the policy's error, and nothing is registered.
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionIf(schemaName, schemaDefinition string, policy SchemaChangePolicy) (*glue.RegisterSchemaVersionOutput, error) {
+	return c.RegisterSchemaVersionIfWithContext(context.Background(), schemaName, schemaDefinition, policy)
+}
+
+// RegisterSchemaVersionIfWithContext is like RegisterSchemaVersionIf but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionIfWithContext(ctx context.Context, schemaName, schemaDefinition string, policy SchemaChangePolicy) (*glue.RegisterSchemaVersionOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schema, err := c.GetSchemaWithContext(ctx, schemaName)
 	if err != nil {
+		return nil, err
+	}
+	if schema.LatestSchemaVersion == nil {
 		return nil, &SchemaRegistryException{
-			Message: fmt.Sprintf("Failed to register schema version: %s", schemaName),
-			Err:     err,
+			Message: fmt.Sprintf("Schema has no latest version: %s", schemaName),
+			Err:     ErrNoLatestVersion,
 		}
 	}
 
-	return result, nil
+	latest, err := c.GetSchemaVersionWithContext(ctx, schemaName, *schema.LatestSchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy(aws.StringValue(latest.SchemaDefinition), schemaDefinition); err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Schema version rejected by policy: %s", schemaName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return c.RegisterSchemaVersionWithContext(ctx, schemaName, schemaDefinition)
 }
 
 // Close closes the underlying Glue client (no-op for AWS SDK)
 func (c *GlueSchemaRegistryClient) Close() {
 	// AWS SDK doesn't require explicit closing
 }
-
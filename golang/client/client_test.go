@@ -1,13 +1,38 @@
 package client_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
 	"github.com/aws-glue-schema-registry/golang/testconfig"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 )
 
 func TestGetSchema(t *testing.T) {
+	testconfig.RequireAWSCredentials(t)
 	cfg := testconfig.LoadConfig()
 	c, err := client.NewGlueSchemaRegistryClient(cfg.AWSRegion, cfg.RegistryName)
 	if err != nil {
@@ -34,3 +59,2395 @@ func TestGetSchema(t *testing.T) {
 	}
 }
 
+func TestGlueClient(t *testing.T) {
+	c, err := client.NewGlueSchemaRegistryClient("us-east-1", "test-registry")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	if c.GlueClient() == nil {
+		t.Fatal("GlueClient returned nil")
+	}
+}
+
+func TestGetDataFormatIsCached(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		dataFormat, err := c.GetDataFormat("SalesforceAudit")
+		if err != nil {
+			t.Fatalf("Failed to get data format: %v", err)
+		}
+		if dataFormat != "AVRO" {
+			t.Errorf("Expected DataFormat to be AVRO, got %s", dataFormat)
+		}
+	}
+
+	if calls := fake.Calls("GetSchema"); calls != 1 {
+		t.Errorf("Expected 1 GetSchema call, got %d", calls)
+	}
+}
+
+// rejectFieldRemoval is a SchemaChangePolicy that refuses to drop any top-level Avro record field
+func rejectFieldRemoval(oldDefinition, newDefinition string) error {
+	fieldNames := func(definition string) (map[string]bool, error) {
+		var record struct {
+			Fields []struct {
+				Name string `json:"name"`
+			} `json:"fields"`
+		}
+		if err := json.Unmarshal([]byte(definition), &record); err != nil {
+			return nil, err
+		}
+		names := make(map[string]bool)
+		for _, f := range record.Fields {
+			names[f.Name] = true
+		}
+		return names, nil
+	}
+
+	oldFields, err := fieldNames(oldDefinition)
+	if err != nil {
+		return err
+	}
+	newFields, err := fieldNames(newDefinition)
+	if err != nil {
+		return err
+	}
+	for name := range oldFields {
+		if !newFields[name] {
+			return fmt.Errorf("field %q was removed", name)
+		}
+	}
+	return nil
+}
+
+func TestRegisterSchemaVersionIf(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	withSource := `{"type": "record", "name": "SalesforceAudit", "namespace": "com.aws.glue.schema.registry", "fields": [
+		{"name": "eventId", "type": "string"},
+		{"name": "eventName", "type": "string"},
+		{"name": "timestamp", "type": "long"},
+		{"name": "eventDetails", "type": "string"},
+		{"name": "source", "type": "string", "default": "salesforce"}
+	]}`
+	result, err := c.RegisterSchemaVersionIf("SalesforceAudit", withSource, rejectFieldRemoval)
+	if err != nil {
+		t.Fatalf("Expected additive change to be registered, got %v", err)
+	}
+	if aws.Int64Value(result.VersionNumber) != 2 {
+		t.Errorf("Expected version 2, got %d", aws.Int64Value(result.VersionNumber))
+	}
+
+	withoutDetails := `{"type": "record", "name": "SalesforceAudit", "namespace": "com.aws.glue.schema.registry", "fields": [
+		{"name": "eventId", "type": "string"},
+		{"name": "eventName", "type": "string"},
+		{"name": "timestamp", "type": "long"},
+		{"name": "source", "type": "string", "default": "salesforce"}
+	]}`
+	_, err = c.RegisterSchemaVersionIf("SalesforceAudit", withoutDetails, rejectFieldRemoval)
+	var registryErr *client.SchemaRegistryException
+	if !errors.As(err, &registryErr) || !strings.Contains(err.Error(), `field "eventDetails" was removed`) {
+		t.Fatalf("Expected policy rejection, got %v", err)
+	}
+	if calls := fake.Calls("RegisterSchemaVersion"); calls != 1 {
+		t.Errorf("Expected 1 RegisterSchemaVersion call, got %d", calls)
+	}
+}
+
+func TestOnSchemaRegistered(t *testing.T) {
+	type registration struct {
+		schemaName, versionID string
+		versionNumber         int64
+	}
+	var registrations []registration
+
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry",
+		client.WithOnSchemaRegistered(func(schemaName, versionID string, versionNumber int64) {
+			registrations = append(registrations, registration{schemaName, versionID, versionNumber})
+		}))
+	defer c.Close()
+
+	created, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+	registered, err := c.RegisterSchemaVersion("SalesforceAudit", withSource)
+	if err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if _, err := c.RegisterSchemaVersion("Missing", withSource); err == nil {
+		t.Fatal("Expected error registering a version for a missing schema")
+	}
+
+	expected := []registration{
+		{"SalesforceAudit", aws.StringValue(created.SchemaVersionId), 1},
+		{"SalesforceAudit", aws.StringValue(registered.SchemaVersionId), 2},
+	}
+	if len(registrations) != len(expected) {
+		t.Fatalf("Expected %d callbacks, got %d: %v", len(expected), len(registrations), registrations)
+	}
+	for i := range expected {
+		if registrations[i] != expected[i] {
+			t.Errorf("Callback %d mismatch: expected %+v, got %+v", i, expected[i], registrations[i])
+		}
+	}
+}
+
+type environmentKey struct{}
+
+func TestForRegistry(t *testing.T) {
+	fake := gluetest.New()
+	metrics := &recordingClientMetrics{}
+	dev := client.NewGlueSchemaRegistryClientWithAPI(fake, "dev-registry", client.WithMetrics(metrics))
+	defer dev.Close()
+	staging := dev.ForRegistry("staging-registry")
+	defer staging.Close()
+
+	if staging.GlueClient() != dev.GlueClient() {
+		t.Error("Expected the registry view to share the Glue client")
+	}
+	if _, err := staging.CreateSchema("SalesforceAudit", client.DataFormatJSON, gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema in staging: %v", err)
+	}
+	if _, err := dev.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema in dev: %v", err)
+	}
+
+	for c, expected := range map[*client.GlueSchemaRegistryClient]string{dev: "dev-registry", staging: "staging-registry"} {
+		schema, err := c.GetSchema("SalesforceAudit")
+		if err != nil {
+			t.Fatalf("Failed to get schema: %v", err)
+		}
+		if registryName := aws.StringValue(schema.RegistryName); registryName != expected {
+			t.Errorf("Registry mismatch: expected %s, got %s", expected, registryName)
+		}
+	}
+	if dataFormat, err := staging.GetDataFormat("SalesforceAudit"); err != nil || dataFormat != "JSON" {
+		t.Errorf("Data format mismatch: expected JSON, got %s (error %v)", dataFormat, err)
+	}
+	if calls := len(metrics.calls); calls != 5 {
+		t.Errorf("Expected the registry view to keep the metrics option, got %d recorded Glue calls", calls)
+	}
+}
+
+func TestRegistryResolver(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "default-registry",
+		client.WithRegistryResolver(client.RegistryResolverFunc(func(ctx context.Context) (string, error) {
+			switch env, _ := ctx.Value(environmentKey{}).(string); env {
+			case "":
+				return "", nil
+			case "staging", "prod":
+				return env + "-registry", nil
+			default:
+				return "", fmt.Errorf("unknown environment %q", env)
+			}
+		})))
+	defer c.Close()
+
+	staging := context.WithValue(context.Background(), environmentKey{}, "staging")
+	prod := context.WithValue(context.Background(), environmentKey{}, "prod")
+
+	if _, err := c.CreateSchemaWithContext(staging, "SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema in staging: %v", err)
+	}
+	if _, err := c.CreateSchemaWithContext(prod, "SalesforceAudit", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema in prod: %v", err)
+	}
+
+	for ctx, expected := range map[context.Context]string{staging: "AVRO", prod: "JSON"} {
+		dataFormat, err := c.GetDataFormatWithContext(ctx, "SalesforceAudit")
+		if err != nil {
+			t.Fatalf("Failed to get data format: %v", err)
+		}
+		if dataFormat != expected {
+			t.Errorf("Data format mismatch: expected %s, got %s", expected, dataFormat)
+		}
+	}
+
+	schema, err := c.GetSchemaWithContext(prod, "SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if registryName := aws.StringValue(schema.RegistryName); registryName != "prod-registry" {
+		t.Errorf("Registry mismatch: expected prod-registry, got %s", registryName)
+	}
+
+	// Without an environment the resolver defers to the static registry, which is empty
+	if _, err := c.GetSchema("SalesforceAudit"); err == nil {
+		t.Error("Expected error getting schema from the default registry")
+	}
+
+	unknown := context.WithValue(context.Background(), environmentKey{}, "dev")
+	_, err = c.GetSchemaWithContext(unknown, "SalesforceAudit")
+	var registryErr *client.SchemaRegistryException
+	if !errors.As(err, &registryErr) || !strings.Contains(err.Error(), "Failed to resolve registry") {
+		t.Errorf("Expected resolver error, got %v", err)
+	}
+}
+
+func TestGetSchemaVersionCompressed(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	compressed, err := c.GetSchemaVersionCompressed("SalesforceAudit", 1)
+	if err != nil {
+		t.Fatalf("Failed to get compressed schema version: %v", err)
+	}
+	if len(compressed) >= len(gluetest.SalesforceAuditAvroSchema) {
+		t.Errorf("Expected compressed definition smaller than %d bytes, got %d", len(gluetest.SalesforceAuditAvroSchema), len(compressed))
+	}
+
+	definition, err := client.DecompressSchemaDefinition(compressed)
+	if err != nil {
+		t.Fatalf("Failed to decompress schema definition: %v", err)
+	}
+	if definition != gluetest.SalesforceAuditAvroSchema {
+		t.Errorf("Definition mismatch: expected %s, got %s", gluetest.SalesforceAuditAvroSchema, definition)
+	}
+
+	if _, err := client.DecompressSchemaDefinition([]byte(definition)); err == nil {
+		t.Error("Expected error decompressing uncompressed data")
+	}
+}
+
+func TestMaxVersionsPrunesOldestVersions(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithMaxVersions(2))
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	for _, field := range []string{"source", "region", "tenant"} {
+		definition := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+			fmt.Sprintf(`"fields": [{"name": %q, "type": "string", "default": ""},`, field), 1)
+		if _, err := c.RegisterSchemaVersion("SalesforceAudit", definition); err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+	}
+
+	versions, err := c.ListSchemaVersions("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to list schema versions: %v", err)
+	}
+	var numbers []int64
+	for _, v := range versions {
+		numbers = append(numbers, aws.Int64Value(v.VersionNumber))
+	}
+	// The first version can only be removed with the schema, so it is kept alongside the newest
+	if fmt.Sprint(numbers) != "[1 4]" {
+		t.Errorf("Versions mismatch: expected [1 4], got %v", numbers)
+	}
+	if calls := fake.Calls("DeleteSchemaVersions"); calls != 2 {
+		t.Errorf("Expected 2 DeleteSchemaVersions calls, got %d", calls)
+	}
+}
+
+func TestDeleteSchemaVersions(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	for _, field := range []string{"source", "region", "tenant"} {
+		definition := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+			fmt.Sprintf(`"fields": [{"name": %q, "type": "string", "default": ""},`, field), 1)
+		if _, err := c.RegisterSchemaVersion("SalesforceAudit", definition); err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+	}
+
+	// Glue deletes versions 2 and 3 but reports that the first version cannot be deleted
+	result, err := c.DeleteSchemaVersions("SalesforceAudit", "1-3")
+	if err != nil {
+		t.Fatalf("Failed to delete schema versions: %v", err)
+	}
+	if len(result.SchemaVersionErrors) != 1 || aws.Int64Value(result.SchemaVersionErrors[0].VersionNumber) != 1 {
+		t.Errorf("Expected a single error for version 1, got %v", result.SchemaVersionErrors)
+	}
+
+	versions, err := c.ListSchemaVersions("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to list schema versions: %v", err)
+	}
+	var numbers []int64
+	for _, v := range versions {
+		numbers = append(numbers, aws.Int64Value(v.VersionNumber))
+	}
+	if fmt.Sprint(numbers) != "[1 4]" {
+		t.Errorf("Versions mismatch: expected [1 4], got %v", numbers)
+	}
+
+	if _, err := c.DeleteSchemaVersions("SalesforceAudit", "latest"); err == nil {
+		t.Error("Expected error for an invalid version range")
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// Reformatting the registered definition does not count as drift
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(gluetest.SalesforceAuditAvroSchema), &definition); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	reformatted, err := json.MarshalIndent(definition, "", "\t")
+	if err != nil {
+		t.Fatalf("Failed to format schema: %v", err)
+	}
+	result, err := c.DetectDrift("SalesforceAudit", string(reformatted), "AVRO")
+	if err != nil {
+		t.Fatalf("Failed to detect drift: %v", err)
+	}
+	if !result.InSync || len(result.Changes) != 0 {
+		t.Errorf("Expected in-sync result, got %+v", result)
+	}
+
+	local := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"type": "long"`, `"type": "int"`, 1)
+	local = strings.Replace(local, `"fields": [`, `"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+	result, err = c.DetectDrift("SalesforceAudit", local, "AVRO")
+	if err != nil {
+		t.Fatalf("Failed to detect drift: %v", err)
+	}
+	if result.InSync {
+		t.Fatal("Expected drift to be detected")
+	}
+	var changes []string
+	for _, change := range result.Changes {
+		changes = append(changes, fmt.Sprintf("%s %s", change.Kind, change.Path))
+	}
+	if got := strings.Join(changes, ", "); got != "added source, changed timestamp" {
+		t.Errorf("Changes mismatch: expected added source, changed timestamp, got %s", got)
+	}
+}
+
+func TestCompareSchemaVersions(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"type": "long"`, `"type": "int"`, 1)
+	v2 = strings.Replace(v2, `"fields": [`, `"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", v2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	d, err := c.CompareSchemaVersions("SalesforceAudit", 1, 2)
+	if err != nil {
+		t.Fatalf("Failed to compare schema versions: %v", err)
+	}
+	if len(d.Added) != 1 || d.Added[0].Path != "source" {
+		t.Errorf("Added mismatch: expected [source], got %+v", d.Added)
+	}
+	if len(d.TypeChanges) != 1 || d.TypeChanges[0].Old != `"long"` || d.TypeChanges[0].New != `"int"` {
+		t.Errorf("TypeChanges mismatch: expected long to int, got %+v", d.TypeChanges)
+	}
+	if d, err := c.CompareSchemaVersions("SalesforceAudit", 2, 2); err != nil || !d.Empty() {
+		t.Errorf("Expected no differences comparing a version with itself, got %v (error %v)", d, err)
+	}
+	if _, err := c.CompareSchemaVersions("SalesforceAudit", 1, 3); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error for version 3, got %v", err)
+	}
+
+	if _, err := c.CreateSchema("SalesforceAuditJSON", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	jsonV2 := strings.Replace(gluetest.SalesforceAuditJSONSchema, `"eventDetails": {"type": "string"}`, `"eventDetails": {"type": ["string", "null"]}`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAuditJSON", jsonV2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	d, err = c.CompareSchemaVersions("SalesforceAuditJSON", 1, 2)
+	if err != nil {
+		t.Fatalf("Failed to compare schema versions: %v", err)
+	}
+	if len(d.TypeChanges) != 1 || d.TypeChanges[0].Path != "eventDetails" || d.TypeChanges[0].New != `["string","null"]` {
+		t.Errorf("TypeChanges mismatch: expected eventDetails to [\"string\",\"null\"], got %+v", d.TypeChanges)
+	}
+}
+
+func TestGetSchemaVersions(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithBatchConcurrency(2))
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	definitions := map[int64]string{1: gluetest.SalesforceAuditAvroSchema}
+	for i, field := range []string{"source", "region"} {
+		version := int64(i + 2)
+		definitions[version] = strings.Replace(definitions[version-1], `"fields": [`,
+			fmt.Sprintf(`"fields": [{"name": %q, "type": "string", "default": ""},`, field), 1)
+		if _, err := c.RegisterSchemaVersion("SalesforceAudit", definitions[version]); err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+	}
+
+	results, errs := c.GetSchemaVersions("SalesforceAudit", []int64{3, 1, 9, 2, 1})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "version 9") {
+		t.Errorf("Expected a single error for version 9, got %v", errs)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 versions, got %d", len(results))
+	}
+	for version, definition := range definitions {
+		if got := aws.StringValue(results[version].SchemaDefinition); got != definition {
+			t.Errorf("Definition mismatch for version %d: expected %s, got %s", version, definition, got)
+		}
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 4 {
+		t.Errorf("Expected 4 GetSchemaVersion calls, got %d", calls)
+	}
+}
+
+func TestCreateSchemaRejectsUnknownDataFormat(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	for _, dataFormat := range []client.DataFormat{"avro", "PROTO", ""} {
+		_, err := c.CreateSchema("SalesforceAudit", dataFormat, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+		if !errors.Is(err, client.ErrInvalidDataFormat) {
+			t.Errorf("Expected ErrInvalidDataFormat for %q, got %v", dataFormat, err)
+		}
+	}
+	if calls := fake.Calls("CreateSchema"); calls != 0 {
+		t.Errorf("Expected no CreateSchema calls, got %d", calls)
+	}
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Errorf("Failed to create schema with DataFormatAvro: %v", err)
+	}
+}
+
+func TestCreateSchemaWithInitialVersion(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	initial, err := c.CreateSchemaWithInitialVersion("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	schema, err := c.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if initial.SchemaArn != aws.StringValue(schema.SchemaArn) {
+		t.Errorf("SchemaArn mismatch: expected %s, got %s", aws.StringValue(schema.SchemaArn), initial.SchemaArn)
+	}
+	version, err := c.GetSchemaVersion("SalesforceAudit", 1)
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if initial.SchemaVersionId != aws.StringValue(version.SchemaVersionId) {
+		t.Errorf("SchemaVersionId mismatch: expected %s, got %s", aws.StringValue(version.SchemaVersionId), initial.SchemaVersionId)
+	}
+	if initial.VersionNumber != 1 {
+		t.Errorf("VersionNumber mismatch: expected 1, got %d", initial.VersionNumber)
+	}
+
+	if _, err := c.CreateSchemaWithInitialVersion("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); !client.IsAlreadyExists(err) {
+		t.Errorf("Expected an already exists error, got %v", err)
+	}
+}
+
+func TestGetLatestSchemaDefinition(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+	registered, err := c.RegisterSchemaVersion("SalesforceAudit", v2)
+	if err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	definition, versionNumber, schemaVersionID, err := c.GetLatestSchemaDefinition("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get latest schema definition: %v", err)
+	}
+	if definition != v2 {
+		t.Errorf("Definition mismatch: expected %s, got %s", v2, definition)
+	}
+	if versionNumber != 2 {
+		t.Errorf("VersionNumber mismatch: expected 2, got %d", versionNumber)
+	}
+	if schemaVersionID != aws.StringValue(registered.SchemaVersionId) {
+		t.Errorf("SchemaVersionId mismatch: expected %s, got %s", aws.StringValue(registered.SchemaVersionId), schemaVersionID)
+	}
+	if calls := fake.Calls("GetSchema"); calls != 0 {
+		t.Errorf("Expected no GetSchema calls, got %d", calls)
+	}
+
+	if _, _, _, err := c.GetLatestSchemaDefinition("Missing"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error for a missing schema, got %v", err)
+	}
+}
+
+func TestGetSchemaVersionById(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	created, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", v2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	version, err := c.GetSchemaVersionById(aws.StringValue(created.SchemaVersionId))
+	if err != nil {
+		t.Fatalf("Failed to get schema version by ID: %v", err)
+	}
+	if aws.Int64Value(version.VersionNumber) != 1 {
+		t.Errorf("VersionNumber mismatch: expected 1, got %d", aws.Int64Value(version.VersionNumber))
+	}
+	if aws.StringValue(version.SchemaDefinition) != gluetest.SalesforceAuditAvroSchema {
+		t.Errorf("Definition mismatch: expected %s, got %s", gluetest.SalesforceAuditAvroSchema, aws.StringValue(version.SchemaDefinition))
+	}
+
+	if _, err := c.GetSchemaVersionById("00000000-0000-0000-0000-000000000000"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error for an unknown version ID, got %v", err)
+	}
+}
+
+// throttlingGlue fails the first failures GetSchema calls with a throttling error
+type throttlingGlue struct {
+	*gluetest.Glue
+
+	mu       sync.Mutex
+	failures int
+}
+
+func (g *throttlingGlue) GetSchemaWithContext(ctx aws.Context, input *glue.GetSchemaInput, opts ...request.Option) (*glue.GetSchemaOutput, error) {
+	g.mu.Lock()
+	throttle := g.failures > 0
+	g.failures--
+	g.mu.Unlock()
+	if throttle {
+		return nil, awserr.New("ThrottlingException", "Rate exceeded", nil)
+	}
+	return g.Glue.GetSchemaWithContext(ctx, input, opts...)
+}
+
+func TestListSchemasWithLatestVersion(t *testing.T) {
+	fake := &throttlingGlue{Glue: gluetest.New(), failures: 3}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry",
+		client.WithBatchConcurrency(3), client.WithRetryBaseDelay(time.Millisecond), client.WithMaxRetries(5))
+	defer c.Close()
+
+	var expected []client.SchemaSummary
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("Audit%d", i)
+		created, err := c.CreateSchema(name, client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone)
+		if err != nil {
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+		latest := int64(1)
+		if i%2 == 1 {
+			v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+			if _, err := c.RegisterSchemaVersion(name, v2); err != nil {
+				t.Fatalf("Failed to register schema version: %v", err)
+			}
+			latest = 2
+		}
+		expected = append(expected, client.SchemaSummary{
+			SchemaName:          name,
+			SchemaArn:           aws.StringValue(created.SchemaArn),
+			DataFormat:          "AVRO",
+			LatestSchemaVersion: latest,
+		})
+	}
+
+	summaries, err := c.ListSchemasWithLatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to list schemas with latest version: %v", err)
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("Summaries mismatch:\nexpected %+v\ngot      %+v", expected, summaries)
+	}
+
+	fake.SetError("GetSchema", awserr.New("AccessDeniedException", "not authorized", nil))
+	if _, err := c.ListSchemasWithLatestVersion(); err == nil {
+		t.Error("Expected access denied to fail the listing")
+	}
+}
+
+func TestExportRegistry(t *testing.T) {
+	fake := &throttlingGlue{Glue: gluetest.New(), failures: 2}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry",
+		client.WithBatchConcurrency(2), client.WithRetryBaseDelay(time.Millisecond), client.WithMaxRetries(5))
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", v2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if _, err := c.CreateSchema("SalesforceAuditJSON", client.DataFormatJSON, gluetest.SalesforceAuditJSONSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "export")
+	if err := c.ExportRegistry(dir); err != nil {
+		t.Fatalf("Failed to export registry: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, client.ExportManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest client.ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	expected := client.ExportManifest{RegistryName: "test-registry", Schemas: []client.ExportedSchema{
+		{SchemaName: "SalesforceAudit", DataFormat: "AVRO", Compatibility: "BACKWARD", Versions: []client.ExportedVersion{
+			{VersionNumber: 1, Status: "AVAILABLE", File: "SalesforceAudit/1.avsc"},
+			{VersionNumber: 2, Status: "AVAILABLE", File: "SalesforceAudit/2.avsc"},
+		}},
+		{SchemaName: "SalesforceAuditJSON", DataFormat: "JSON", Compatibility: "NONE", Versions: []client.ExportedVersion{
+			{VersionNumber: 1, Status: "AVAILABLE", File: "SalesforceAuditJSON/1.json"},
+		}},
+	}}
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("Manifest mismatch:\nexpected %+v\ngot      %+v", expected, manifest)
+	}
+	for file, definition := range map[string]string{
+		"SalesforceAudit/1.avsc":     gluetest.SalesforceAuditAvroSchema,
+		"SalesforceAudit/2.avsc":     v2,
+		"SalesforceAuditJSON/1.json": gluetest.SalesforceAuditJSONSchema,
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if string(data) != definition {
+			t.Errorf("Definition mismatch for %s: expected %s, got %s", file, definition, data)
+		}
+	}
+
+	fake.SetError("ListSchemaVersions", awserr.New("AccessDeniedException", "not authorized", nil))
+	if err := c.ExportRegistry(t.TempDir()); err == nil {
+		t.Error("Expected access denied to fail the export")
+	}
+}
+
+// deletedVersionGlue reports one version number of every schema as deleted, as Glue does
+// for a version deleted between ListSchemaVersions and GetSchemaVersion
+type deletedVersionGlue struct {
+	*gluetest.Glue
+	versionNumber int64
+}
+
+func (g *deletedVersionGlue) GetSchemaVersionWithContext(ctx aws.Context, input *glue.GetSchemaVersionInput, opts ...request.Option) (*glue.GetSchemaVersionOutput, error) {
+	if input.SchemaVersionNumber != nil && aws.Int64Value(input.SchemaVersionNumber.VersionNumber) == g.versionNumber {
+		return nil, awserr.New(glue.ErrCodeEntityNotFoundException, "Schema version is not found", nil)
+	}
+	return g.Glue.GetSchemaVersionWithContext(ctx, input, opts...)
+}
+
+func TestExportRegistryDeletedVersion(t *testing.T) {
+	fake := &deletedVersionGlue{Glue: gluetest.New(), versionNumber: 1}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", v2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := c.ExportRegistry(dir); err != nil {
+		t.Fatalf("Failed to export registry: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, client.ExportManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest client.ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	// Only the deleted version is left out, not the whole schema
+	expected := []client.ExportedSchema{
+		{SchemaName: "SalesforceAudit", DataFormat: "AVRO", Compatibility: "BACKWARD", Versions: []client.ExportedVersion{
+			{VersionNumber: 2, Status: "AVAILABLE", File: "SalesforceAudit/2.avsc"},
+		}},
+	}
+	if !reflect.DeepEqual(manifest.Schemas, expected) {
+		t.Errorf("Schemas mismatch:\nexpected %+v\ngot      %+v", expected, manifest.Schemas)
+	}
+}
+
+func TestImportRegistry(t *testing.T) {
+	source := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer source.Close()
+	if _, err := source.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityDisabled); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := source.CreateSchema("SalesforceAuditJSON", client.DataFormatJSON, gluetest.SalesforceAuditJSONSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	jsonV2 := strings.Replace(gluetest.SalesforceAuditJSONSchema, `"additionalProperties": false`, `"additionalProperties": true`, 1)
+	if _, err := source.RegisterSchemaVersion("SalesforceAuditJSON", jsonV2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	dir := t.TempDir()
+	if err := source.ExportRegistry(dir); err != nil {
+		t.Fatalf("Failed to export registry: %v", err)
+	}
+
+	target := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "dr-registry")
+	defer target.Close()
+	summary, err := target.ImportRegistry(dir)
+	if err != nil {
+		t.Fatalf("Failed to import registry: %v", err)
+	}
+	if expected := (client.ImportSummary{SchemasCreated: 2, VersionsCreated: 3}); summary != expected {
+		t.Errorf("Summary mismatch: expected %+v, got %+v", expected, summary)
+	}
+	schema, err := target.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if mode := aws.StringValue(schema.Compatibility); mode != "DISABLED" {
+		t.Errorf("Compatibility mismatch: expected DISABLED, got %s", mode)
+	}
+	version, err := target.GetSchemaVersion("SalesforceAuditJSON", 2)
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if definition := aws.StringValue(version.SchemaDefinition); definition != jsonV2 {
+		t.Errorf("Definition mismatch: expected %s, got %s", jsonV2, definition)
+	}
+
+	// Importing again finds everything in place
+	summary, err = target.ImportRegistry(dir)
+	if err != nil {
+		t.Fatalf("Failed to import registry again: %v", err)
+	}
+	if expected := (client.ImportSummary{SchemasSkipped: 2, VersionsSkipped: 3}); summary != expected {
+		t.Errorf("Summary mismatch on second import: expected %+v, got %+v", expected, summary)
+	}
+
+	if _, err := target.ImportRegistry(t.TempDir()); err == nil {
+		t.Error("Expected error importing a directory without a manifest")
+	}
+}
+
+// noLatestGlue reports schemas without a latest version and latest versions without a
+// definition, as Glue does when the only version of a schema failed validation
+type noLatestGlue struct {
+	*gluetest.Glue
+}
+
+func TestImportRegistryMetadata(t *testing.T) {
+	sourceFake := gluetest.New()
+	source := client.NewGlueSchemaRegistryClientWithAPI(sourceFake, "test-registry")
+	defer source.Close()
+	registry, err := source.CreateRegistry("Audit events")
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	tags := map[string]string{"team": "audit", "env": "prod"}
+	if err := source.TagResource(aws.StringValue(registry.RegistryArn), tags); err != nil {
+		t.Fatalf("Failed to tag registry: %v", err)
+	}
+	if _, err := source.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := sourceFake.UpdateSchema(&glue.UpdateSchemaInput{
+		SchemaId:    &glue.SchemaId{RegistryName: aws.String("test-registry"), SchemaName: aws.String("SalesforceAudit")},
+		Description: aws.String("Salesforce audit events"),
+	}); err != nil {
+		t.Fatalf("Failed to describe schema: %v", err)
+	}
+	dir := t.TempDir()
+	if err := source.ExportRegistry(dir); err != nil {
+		t.Fatalf("Failed to export registry: %v", err)
+	}
+
+	target := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer target.Close()
+	summary, err := target.ImportRegistry(dir)
+	if err != nil {
+		t.Fatalf("Failed to import registry: %v", err)
+	}
+	if expected := (client.ImportSummary{RegistryCreated: true, SchemasCreated: 1, VersionsCreated: 1}); summary != expected {
+		t.Errorf("Summary mismatch: expected %+v, got %+v", expected, summary)
+	}
+	restored, err := target.GetRegistry()
+	if err != nil {
+		t.Fatalf("Failed to get registry: %v", err)
+	}
+	if description := aws.StringValue(restored.Description); description != "Audit events" {
+		t.Errorf("Description mismatch: expected Audit events, got %s", description)
+	}
+	restoredTags, err := target.GetTags(aws.StringValue(restored.RegistryArn))
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if !reflect.DeepEqual(restoredTags, tags) {
+		t.Errorf("Tags mismatch: expected %v, got %v", tags, restoredTags)
+	}
+	schema, err := target.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if description := aws.StringValue(schema.Description); description != "Salesforce audit events" {
+		t.Errorf("Schema description mismatch: expected Salesforce audit events, got %s", description)
+	}
+
+	// An existing registry is not created again
+	if summary, err = target.ImportRegistry(dir); err != nil {
+		t.Fatalf("Failed to import registry again: %v", err)
+	}
+	if summary.RegistryCreated {
+		t.Error("Expected the existing registry not to be created again")
+	}
+}
+
+func (g noLatestGlue) GetSchemaWithContext(ctx aws.Context, input *glue.GetSchemaInput, opts ...request.Option) (*glue.GetSchemaOutput, error) {
+	schema, err := g.Glue.GetSchemaWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	schema.LatestSchemaVersion = nil
+	return schema, nil
+}
+
+func (g noLatestGlue) GetSchemaVersionWithContext(ctx aws.Context, input *glue.GetSchemaVersionInput, opts ...request.Option) (*glue.GetSchemaVersionOutput, error) {
+	if input.SchemaVersionNumber != nil && aws.BoolValue(input.SchemaVersionNumber.LatestVersion) {
+		return &glue.GetSchemaVersionOutput{}, nil
+	}
+	return g.Glue.GetSchemaVersionWithContext(ctx, input, opts...)
+}
+
+func TestNoLatestVersion(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(noLatestGlue{gluetest.New()}, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	if _, _, _, err := c.GetLatestSchemaDefinition("SalesforceAudit"); !errors.Is(err, client.ErrNoLatestVersion) {
+		t.Errorf("Expected ErrNoLatestVersion from GetLatestSchemaDefinition, got %v", err)
+	}
+	if _, err := c.DetectDrift("SalesforceAudit", gluetest.SalesforceAuditAvroSchema, "AVRO"); !errors.Is(err, client.ErrNoLatestVersion) {
+		t.Errorf("Expected ErrNoLatestVersion from DetectDrift, got %v", err)
+	}
+	allowAll := func(oldDefinition, newDefinition string) error { return nil }
+	if _, err := c.RegisterSchemaVersionIf("SalesforceAudit", gluetest.SalesforceAuditAvroSchema, allowAll); !errors.Is(err, client.ErrNoLatestVersion) {
+		t.Errorf("Expected ErrNoLatestVersion from RegisterSchemaVersionIf, got %v", err)
+	}
+}
+
+func TestRegisterSchemaVersionDetailed(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	created, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	unchanged, err := c.RegisterSchemaVersionDetailed("SalesforceAudit", gluetest.SalesforceAuditAvroSchema)
+	if err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	expected := client.RegisteredVersion{
+		VersionNumber:   1,
+		SchemaVersionId: aws.StringValue(created.SchemaVersionId),
+		Status:          glue.SchemaVersionStatusAvailable,
+		Created:         false,
+	}
+	if *unchanged != expected {
+		t.Errorf("Result mismatch for an unchanged definition: expected %+v, got %+v", expected, *unchanged)
+	}
+
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+	registered, err := c.RegisterSchemaVersionDetailed("SalesforceAudit", v2)
+	if err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if !registered.Created || registered.VersionNumber != 2 {
+		t.Errorf("Expected version 2 to be created, got %+v", *registered)
+	}
+
+	if _, err := c.RegisterSchemaVersionDetailed("Missing", v2); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+// statusGlue reports each status in statuses, in turn, for the versions it returns, and
+// afterwards the versions' own status
+type statusGlue struct {
+	*gluetest.Glue
+	mu       sync.Mutex
+	statuses []string
+}
+
+func (g *statusGlue) GetSchemaVersionWithContext(ctx aws.Context, input *glue.GetSchemaVersionInput, opts ...request.Option) (*glue.GetSchemaVersionOutput, error) {
+	version, err := g.Glue.GetSchemaVersionWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.statuses) > 0 {
+		version.Status = aws.String(g.statuses[0])
+		g.statuses = g.statuses[1:]
+	}
+	return version, nil
+}
+
+func TestWaitForSchemaVersionAvailable(t *testing.T) {
+	defer client.SetWaitPollDelay(time.Millisecond, 4*time.Millisecond)()
+	pending := glue.SchemaVersionStatusPending
+	fake := &statusGlue{Glue: gluetest.New(), statuses: []string{pending, pending, pending}}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithSchemaVersionCache(true))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	if err := c.WaitForSchemaVersionAvailable("SalesforceAudit", 1, time.Second); err != nil {
+		t.Fatalf("Failed to wait for schema version: %v", err)
+	}
+	// Pending versions are not cached, so every check reaches Glue
+	if n := fake.Calls("GetSchemaVersion"); n != 4 {
+		t.Errorf("GetSchemaVersion calls mismatch: expected 4, got %d", n)
+	}
+
+	if err := c.WaitForSchemaVersionAvailable("SalesforceAudit", 2, time.Second); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error for a missing version, got %v", err)
+	}
+	fake.statuses = []string{pending, glue.SchemaVersionStatusFailure}
+	c.InvalidateSchemaCache("SalesforceAudit")
+	if err := c.WaitForSchemaVersionAvailable("SalesforceAudit", 1, time.Second); !errors.Is(err, client.ErrSchemaVersionFailed) {
+		t.Errorf("Expected ErrSchemaVersionFailed, got %v", err)
+	}
+
+	fake.statuses = []string{pending, pending, pending, pending, pending, pending, pending, pending, pending, pending}
+	c.InvalidateSchemaCache("SalesforceAudit")
+	err := c.WaitForSchemaVersionAvailable("SalesforceAudit", 1, 5*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, client.ErrSchemaVersionFailed) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+
+	// Cancelling stops a wait that has no deadline
+	fake.statuses = make([]string, 1000)
+	for i := range fake.statuses {
+		fake.statuses[i] = pending
+	}
+	c.InvalidateSchemaCache("SalesforceAudit")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := c.WaitForSchemaVersionAvailableWithContext(ctx, "SalesforceAudit", 1); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := c.WaitForSchemaVersionAvailableWithContext(ctx, "SalesforceAudit", 1); err != context.Canceled {
+		t.Errorf("Expected context.Canceled from a cancelled context, got %v", err)
+	}
+}
+
+// fakeClock fires every timer at once, advancing its time by the timer's duration
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.delays = append(f.delays, d)
+	fired := make(chan time.Time, 1)
+	fired <- f.now
+	return fired, func() bool { return false }
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1704067200, 0)}
+	pending := glue.SchemaVersionStatusPending
+	fake := &statusGlue{Glue: gluetest.New(), statuses: []string{pending, pending, pending, pending}}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithClock(clock))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// The default poll delays of 100ms doubling, without waiting for them
+	if err := c.WaitForSchemaVersionAvailableWithContext(context.Background(), "SalesforceAudit", 1); err != nil {
+		t.Fatalf("Failed to wait for schema version: %v", err)
+	}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond}
+	if !reflect.DeepEqual(clock.delays, expected) {
+		t.Errorf("Poll delays mismatch: expected %v, got %v", expected, clock.delays)
+	}
+
+	clock.delays = nil
+	throttled := &throttlingGlue{Glue: gluetest.New(), failures: 2}
+	c = client.NewGlueSchemaRegistryClientWithAPI(throttled, "test-registry",
+		client.WithClock(clock), client.WithRetryBaseDelay(time.Second), client.WithMaxRetries(5))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.ListSchemasWithLatestVersion(); err != nil {
+		t.Fatalf("Failed to list schemas with latest version: %v", err)
+	}
+	// Each backoff is the base delay doubled per attempt plus up to as much jitter
+	if len(clock.delays) != 2 || clock.delays[0] < time.Second || clock.delays[0] >= 2*time.Second ||
+		clock.delays[1] < 2*time.Second || clock.delays[1] >= 4*time.Second {
+		t.Errorf("Backoff delays mismatch: expected [1s, 2s) then [2s, 4s), got %v", clock.delays)
+	}
+}
+
+func TestBackoffCappedAtMaxThrottleDelay(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1704067200, 0)}
+	throttled := &throttlingGlue{Glue: gluetest.New(), failures: 70}
+	c := client.NewGlueSchemaRegistryClientWithAPI(throttled, "test-registry",
+		client.WithClock(clock), client.WithRetryBaseDelay(time.Second), client.WithMaxRetries(70))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.ListSchemasWithLatestVersion(); err != nil {
+		t.Fatalf("Failed to list schemas with latest version: %v", err)
+	}
+
+	// Doubling past the maximum would otherwise wait for hours and then overflow
+	if len(clock.delays) != 70 {
+		t.Fatalf("Backoff count mismatch: expected 70, got %d", len(clock.delays))
+	}
+	for i, d := range clock.delays {
+		if d <= 0 || d > awsclient.DefaultRetryerMaxThrottleDelay {
+			t.Errorf("Backoff %d mismatch: expected (0, %v], got %v", i, awsclient.DefaultRetryerMaxThrottleDelay, d)
+		}
+	}
+}
+
+// idleClock never fires its timers and counts those stopped
+type idleClock struct {
+	mu      sync.Mutex
+	stopped int
+}
+
+func (*idleClock) Now() time.Time {
+	return time.Now()
+}
+
+func (k *idleClock) NewTimer(time.Duration) (<-chan time.Time, func() bool) {
+	return make(chan time.Time), func() bool {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		k.stopped++
+		return true
+	}
+}
+
+func TestClockTimersStoppedWhenAbandoned(t *testing.T) {
+	clock := &idleClock{}
+	pending := glue.SchemaVersionStatusPending
+	fake := &statusGlue{Glue: gluetest.New(), statuses: []string{pending}}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithClock(clock))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if err := c.WaitForSchemaVersionAvailable("SalesforceAudit", 1, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a timeout, got %v", err)
+	}
+
+	throttled := &throttlingGlue{Glue: gluetest.New(), failures: 1}
+	c = client.NewGlueSchemaRegistryClientWithAPI(throttled, "test-registry", client.WithClock(clock))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListSchemasWithLatestVersionWithContext(ctx); err == nil {
+		t.Fatal("Expected the throttled call to fail once the context is done")
+	}
+	if clock.stopped != 2 {
+		t.Errorf("Stopped timers mismatch: expected 2, got %d", clock.stopped)
+	}
+}
+
+func TestSchemaVersionCacheLimits(t *testing.T) {
+	fake := gluetest.New()
+	clock := &fakeClock{now: time.Unix(1704067200, 0)}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithClock(clock),
+		client.WithSchemaVersionCache(true), client.WithSchemaVersionCacheSize(2), client.WithLatestVersionCacheTTL(time.Minute))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	for i := 2; i <= 3; i++ {
+		definition := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", fmt.Sprintf("Salesforce audit events, v%d", i), 1)
+		if _, err := c.RegisterSchemaVersion("SalesforceAudit", definition); err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+	}
+
+	// Version 1 is the least recently used once versions 2 and 3 are cached
+	for _, version := range []int64{1, 2, 3, 3, 1} {
+		if _, err := c.GetSchemaVersion("SalesforceAudit", version); err != nil {
+			t.Fatalf("Failed to get schema version %d: %v", version, err)
+		}
+	}
+	if stats, expected := c.SchemaCacheStats(), (client.CacheStats{Hits: 1, Misses: 4, Evictions: 2}); stats != expected {
+		t.Errorf("Stats mismatch: expected %+v, got %+v", expected, stats)
+	}
+	if n := fake.Calls("GetSchemaVersion"); n != 4 {
+		t.Errorf("GetSchemaVersion calls mismatch: expected 4, got %d", n)
+	}
+
+	latest := func() int64 {
+		t.Helper()
+		_, versionNumber, _, err := c.GetLatestSchemaDefinition("SalesforceAudit")
+		if err != nil {
+			t.Fatalf("Failed to get latest schema definition: %v", err)
+		}
+		return versionNumber
+	}
+	calls := fake.Calls("GetSchemaVersion")
+	if latest() != 3 || latest() != 3 {
+		t.Error("Latest version mismatch: expected 3")
+	}
+	if n := fake.Calls("GetSchemaVersion") - calls; n != 1 {
+		t.Errorf("GetSchemaVersion calls mismatch for the latest version: expected 1, got %d", n)
+	}
+
+	// A version registered elsewhere shows once the cached latest expires
+	other := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer other.Close()
+	v4 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v4", 1)
+	if _, err := other.RegisterSchemaVersion("SalesforceAudit", v4); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if version := latest(); version != 3 {
+		t.Errorf("Latest version mismatch before the TTL: expected 3, got %d", version)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if version := latest(); version != 4 {
+		t.Errorf("Latest version mismatch after the TTL: expected 4, got %d", version)
+	}
+	// A version registered through the client replaces its cached latest at once
+	v5 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v5", 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", v5); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if version := latest(); version != 5 {
+		t.Errorf("Latest version mismatch after registering: expected 5, got %d", version)
+	}
+
+	uncached := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer uncached.Close()
+	if stats := uncached.SchemaCacheStats(); stats != (client.CacheStats{}) {
+		t.Errorf("Expected zero stats without a cache, got %+v", stats)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	planned, err := c.CreateSchemaDryRun("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, "")
+	if err != nil {
+		t.Fatalf("Failed to dry-run create schema: %v", err)
+	}
+	if planned.Action != client.DryRunCreateSchema || planned.VersionNumber != 1 {
+		t.Errorf("Expected version 1 to be created, got %+v", *planned)
+	}
+	if _, err := c.CreateSchemaDryRun("SalesforceAudit", client.DataFormatAvro, `{"type": "record"}`, ""); !errors.Is(err, client.ErrInvalidSchemaDefinition) {
+		t.Errorf("Expected ErrInvalidSchemaDefinition, got %v", err)
+	}
+	if n := fake.Calls("CreateSchema"); n != 0 {
+		t.Errorf("CreateSchema calls mismatch: expected 0, got %d", n)
+	}
+
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.CreateSchemaDryRun("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, ""); !client.IsAlreadyExists(err) {
+		t.Errorf("Expected an already exists error, got %v", err)
+	}
+
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+	withRegion := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "region", "type": "string"},`, 1)
+	tests := []struct {
+		definition string
+		action     client.DryRunAction
+		version    int64
+	}{
+		{gluetest.SalesforceAuditAvroSchema, client.DryRunNoChange, 1},
+		{withSource, client.DryRunRegisterVersion, 2},
+		{withRegion, client.DryRunRejected, 2},
+	}
+	for _, tt := range tests {
+		planned, err := c.RegisterSchemaVersionDryRun("SalesforceAudit", tt.definition)
+		if err != nil {
+			t.Fatalf("Failed to dry-run register schema version: %v", err)
+		}
+		if planned.Action != tt.action || planned.VersionNumber != tt.version {
+			t.Errorf("Result mismatch: expected %s of version %d, got %+v", tt.action, tt.version, *planned)
+		}
+	}
+	rejected, err := c.RegisterSchemaVersionDryRun("SalesforceAudit", withRegion)
+	if err != nil {
+		t.Fatalf("Failed to dry-run register schema version: %v", err)
+	}
+	if len(rejected.BreakingChanges[1]) != 1 || !strings.Contains(rejected.Description, "region") {
+		t.Errorf("Expected one breaking change for the region field, got %+v", *rejected)
+	}
+	if n := fake.Calls("RegisterSchemaVersion"); n != 0 {
+		t.Errorf("RegisterSchemaVersion calls mismatch: expected 0, got %d", n)
+	}
+}
+
+func TestSchemaExists(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	if exists, err := c.SchemaExists("SalesforceAudit"); exists || err != nil {
+		t.Errorf("Expected a missing schema to give false and no error, got %v (error %v)", exists, err)
+	}
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if exists, err := c.SchemaExists("SalesforceAudit"); !exists || err != nil {
+		t.Errorf("Expected an existing schema to give true and no error, got %v (error %v)", exists, err)
+	}
+
+	fake.SetError("GetSchema", awserr.New("AccessDeniedException", "not authorized", nil))
+	if _, err := c.SchemaExists("SalesforceAudit"); err == nil {
+		t.Error("Expected access denied to be returned as an error")
+	}
+}
+
+func TestNormalizedDefinitions(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry", client.WithNormalizedDefinitions())
+	defer c.Close()
+
+	created, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// The same schema with other whitespace and key order
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(gluetest.SalesforceAuditAvroSchema), &parsed); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	reformatted, err := json.MarshalIndent(parsed, "", "\t")
+	if err != nil {
+		t.Fatalf("Failed to format schema: %v", err)
+	}
+
+	registered, err := c.RegisterSchemaVersion("SalesforceAudit", string(reformatted))
+	if err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if versionNumber := aws.Int64Value(registered.VersionNumber); versionNumber != 1 {
+		t.Errorf("Expected the reformatted definition to match version 1, got version %d", versionNumber)
+	}
+	found, err := c.GetSchemaByDefinition("SalesforceAudit", string(reformatted))
+	if err != nil {
+		t.Fatalf("Failed to get schema by definition: %v", err)
+	}
+	if got, expected := aws.StringValue(found.SchemaVersionId), aws.StringValue(created.SchemaVersionId); got != expected {
+		t.Errorf("SchemaVersionId mismatch: expected %s, got %s", expected, got)
+	}
+
+	proto := "syntax = \"proto3\";\n\nmessage Audit {\n  string event_id = 1;\n}\n"
+	if _, err := c.CreateSchema("AuditProto", client.DataFormatProtobuf, proto, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Protobuf schema: %v", err)
+	}
+	version, err := c.GetSchemaVersion("AuditProto", 1)
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if definition := aws.StringValue(version.SchemaDefinition); definition != proto {
+		t.Errorf("Expected the Protobuf definition unchanged, got %q", definition)
+	}
+}
+
+func TestGetSchemaByDefinition(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "source", "type": "string", "default": ""},`, 1)
+	registered, err := c.RegisterSchemaVersion("SalesforceAudit", withSource)
+	if err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	result, err := c.GetSchemaByDefinition("SalesforceAudit", withSource)
+	if err != nil {
+		t.Fatalf("Failed to get schema by definition: %v", err)
+	}
+	if got, expected := aws.StringValue(result.SchemaVersionId), aws.StringValue(registered.SchemaVersionId); got != expected {
+		t.Errorf("SchemaVersionId mismatch: expected %s, got %s", expected, got)
+	}
+	if got := aws.StringValue(result.Status); got != glue.SchemaVersionStatusAvailable {
+		t.Errorf("Status mismatch: expected %s, got %s", glue.SchemaVersionStatusAvailable, got)
+	}
+
+	var exception *client.SchemaRegistryException
+	if _, err := c.GetSchemaByDefinition("SalesforceAudit", `{"type": "string"}`); !errors.As(err, &exception) {
+		t.Errorf("Expected SchemaRegistryException for an unregistered definition, got %v", err)
+	}
+}
+
+func TestSchemaRegistryExceptionUnwrap(t *testing.T) {
+	fake := gluetest.New()
+	throttled := awserr.New("ThrottlingException", "Rate exceeded", nil)
+	fake.SetError("GetSchema", throttled)
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	_, err := c.GetSchema("SalesforceAudit")
+	var registryErr *client.SchemaRegistryException
+	if !errors.As(err, &registryErr) {
+		t.Fatalf("Expected SchemaRegistryException, got %T", err)
+	}
+	if unwrapped := errors.Unwrap(err); unwrapped != throttled {
+		t.Errorf("Unwrap mismatch: expected %v, got %v", throttled, unwrapped)
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != "ThrottlingException" {
+		t.Errorf("Expected awserr.Error with code ThrottlingException, got %v", err)
+	}
+}
+
+func TestSchemaRegistryExceptionErrorCode(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	_, notFound := c.GetSchema("Missing")
+	_, deleted := c.DeleteSchema("Missing")
+	fake.SetError("GetSchema", awserr.New("ThrottlingException", "Rate exceeded", nil))
+	_, throttled := c.GetSchema("SalesforceAudit")
+
+	tests := []struct {
+		name             string
+		err              error
+		expectCode       string
+		expectNotFound   bool
+		expectThrottling bool
+	}{
+		{name: "NotFound", err: notFound, expectCode: glue.ErrCodeEntityNotFoundException, expectNotFound: true},
+		{name: "SchemaNotFound", err: deleted, expectCode: glue.ErrCodeEntityNotFoundException, expectNotFound: true},
+		{name: "Throttling", err: throttled, expectCode: "ThrottlingException", expectThrottling: true},
+		{name: "NotAnAWSError", err: errors.New("boom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var registryErr *client.SchemaRegistryException
+			if errors.As(tt.err, &registryErr) && registryErr.ErrorCode != tt.expectCode {
+				t.Errorf("ErrorCode mismatch: expected %s, got %s", tt.expectCode, registryErr.ErrorCode)
+			}
+			if got := client.IsNotFound(tt.err); got != tt.expectNotFound {
+				t.Errorf("IsNotFound mismatch: expected %v, got %v", tt.expectNotFound, got)
+			}
+			if got := client.IsThrottling(tt.err); got != tt.expectThrottling {
+				t.Errorf("IsThrottling mismatch: expected %v, got %v", tt.expectThrottling, got)
+			}
+		})
+	}
+}
+
+func TestSetErrorFormatter(t *testing.T) {
+	client.SetErrorFormatter(func(e *client.SchemaRegistryException) string {
+		out, _ := json.Marshal(map[string]string{"message": e.Message, "cause": fmt.Sprint(e.Err)})
+		return string(out)
+	})
+	defer client.SetErrorFormatter(nil)
+
+	err := &client.SchemaRegistryException{Message: "Failed to get schema: SalesforceAudit", Err: errors.New("boom")}
+	if expected := `{"cause":"boom","message":"Failed to get schema: SalesforceAudit"}`; err.Error() != expected {
+		t.Errorf("Error mismatch: expected %s, got %s", expected, err.Error())
+	}
+
+	client.SetErrorFormatter(nil)
+	if expected := "Failed to get schema: SalesforceAudit: boom"; err.Error() != expected {
+		t.Errorf("Error mismatch: expected %s, got %s", expected, err.Error())
+	}
+}
+
+func TestAuditCompatibility(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	withField := func(field string) string {
+		return strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`, `"fields": [`+field+`,`, 1)
+	}
+	histories := map[string][]string{
+		"Compatible": {gluetest.SalesforceAuditAvroSchema, withField(`{"name": "source", "type": "string", "default": ""}`)},
+		"Breaking":   {gluetest.SalesforceAuditAvroSchema, withField(`{"name": "source", "type": "string"}`)},
+		"History": {
+			gluetest.SalesforceAuditAvroSchema,
+			withField(`{"name": "source", "type": "string", "default": ""}`),
+			withField(`{"name": "source", "type": "string"}`),
+		},
+	}
+	for schemaName, definitions := range histories {
+		if _, err := c.CreateSchema(schemaName, "AVRO", definitions[0], client.CompatibilityNone); err != nil {
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+		for _, definition := range definitions[1:] {
+			if _, err := c.RegisterSchemaVersion(schemaName, definition); err != nil {
+				t.Fatalf("Failed to register schema version: %v", err)
+			}
+		}
+	}
+	if _, err := c.CreateSchema("Json", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	summarize := func(reports []client.SchemaCompatibilityReport) string {
+		var out []string
+		for _, r := range reports {
+			switch {
+			case r.Err != nil:
+				out = append(out, r.SchemaName+"=error")
+			case r.Compatible:
+				out = append(out, r.SchemaName+"=ok")
+			default:
+				var versions []string
+				for version, changes := range r.Changes {
+					versions = append(versions, fmt.Sprintf("v%d:%s", version, changes[0].Path))
+				}
+				out = append(out, r.SchemaName+"="+strings.Join(versions, ","))
+			}
+		}
+		return strings.Join(out, " ")
+	}
+
+	for mode, expected := range map[client.Compatibility]string{
+		client.CompatibilityBackward:    "Breaking=v1:source Compatible=ok History=ok Json=error",
+		client.CompatibilityBackwardAll: "Breaking=v1:source Compatible=ok History=v1:source Json=error",
+		client.CompatibilityNone:        "Breaking=ok Compatible=ok History=ok Json=error",
+	} {
+		reports, err := c.AuditCompatibility(mode)
+		if err != nil {
+			t.Fatalf("Failed to audit compatibility: %v", err)
+		}
+		if got := summarize(reports); got != expected {
+			t.Errorf("Report mismatch for %s: expected %s, got %s", mode, expected, got)
+		}
+	}
+}
+
+func TestRegisterSchemaByFingerprint(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	first, err := c.RegisterSchemaByFingerprint("SalesforceAudit", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if first.Reused {
+		t.Error("Expected first registration to create the schema")
+	}
+
+	// Same content under a different name and without documentation
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(gluetest.SalesforceAuditAvroSchema), &definition); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	delete(definition, "doc")
+	undocumented, err := json.Marshal(definition)
+	if err != nil {
+		t.Fatalf("Failed to encode schema: %v", err)
+	}
+
+	second, err := c.RegisterSchemaByFingerprint("SalesforceAuditV2", string(undocumented), client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if !second.Reused || second.SchemaName != "SalesforceAudit" || second.SchemaVersionId != first.SchemaVersionId {
+		t.Errorf("Expected reuse of %+v, got %+v", *first, *second)
+	}
+	if calls := fake.Calls("CreateSchema"); calls != 1 {
+		t.Errorf("Expected 1 CreateSchema call, got %d", calls)
+	}
+
+	third, err := c.RegisterSchemaByFingerprint("Other", `{"type": "record", "name": "Other", "fields": []}`, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if third.Reused || third.Fingerprint == first.Fingerprint {
+		t.Errorf("Expected a new schema for different content, got %+v", *third)
+	}
+}
+
+// deletingGlue reports a schema as not found to metadata queries, as Glue does while the
+// schema is being deleted
+type deletingGlue struct {
+	*gluetest.Glue
+	deleting string
+}
+
+func (g deletingGlue) QuerySchemaVersionMetadataWithContext(ctx aws.Context, input *glue.QuerySchemaVersionMetadataInput, opts ...request.Option) (*glue.QuerySchemaVersionMetadataOutput, error) {
+	if input.SchemaId != nil && aws.StringValue(input.SchemaId.SchemaName) == g.deleting {
+		return nil, awserr.New(glue.ErrCodeEntityNotFoundException, "Schema is being deleted", nil)
+	}
+	return g.Glue.QuerySchemaVersionMetadataWithContext(ctx, input, opts...)
+}
+
+func TestRegisterSchemaByFingerprintSkipsDeletingSchemas(t *testing.T) {
+	fake := deletingGlue{Glue: gluetest.New(), deleting: "Deleting"}
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("Deleting", client.DataFormatAvro, `{"type": "record", "name": "Deleting", "fields": []}`, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	registration, err := c.RegisterSchemaByFingerprint("SalesforceAudit", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if registration.Reused || registration.SchemaName != "SalesforceAudit" {
+		t.Errorf("Expected SalesforceAudit to be created, got %+v", *registration)
+	}
+}
+
+func TestSchemaVersionMetadata(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	created, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	versionID := aws.StringValue(created.SchemaVersionId)
+
+	tags := map[string]string{"git-commit": "3f2c1ab", "environment": "staging"}
+	for key, value := range tags {
+		if _, err := c.PutSchemaVersionMetadata(versionID, key, value); err != nil {
+			t.Fatalf("Failed to put schema version metadata: %v", err)
+		}
+	}
+
+	metadata, err := c.QuerySchemaVersionMetadata(versionID)
+	if err != nil {
+		t.Fatalf("Failed to query schema version metadata: %v", err)
+	}
+	if len(metadata) != len(tags) {
+		t.Errorf("Metadata count mismatch: expected %d, got %d", len(tags), len(metadata))
+	}
+	for key, value := range tags {
+		if info, ok := metadata[key]; !ok || aws.StringValue(info.MetadataValue) != value {
+			t.Errorf("Metadata mismatch for %s: expected %s, got %v", key, value, info)
+		}
+	}
+
+	if _, err := c.QuerySchemaVersionMetadata("00000000-0000-4000-8000-999999999999"); err == nil {
+		t.Error("Expected error querying an unknown schema version")
+	}
+}
+
+func TestGetSchemaVersionByMetadata(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry", client.WithBatchConcurrency(2))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	versionIDs := make(map[int64]string)
+	for n := int64(1); n <= 4; n++ {
+		definition := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", fmt.Sprintf("Salesforce audit events, v%d", n), 1)
+		registered, err := c.RegisterSchemaVersion("SalesforceAudit", definition)
+		if err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+		versionIDs[aws.Int64Value(registered.VersionNumber)] = aws.StringValue(registered.SchemaVersionId)
+	}
+	for n, env := range map[int64]string{2: "staging", 3: "prod", 4: "staging"} {
+		if _, err := c.PutSchemaVersionMetadata(versionIDs[n], "env", env); err != nil {
+			t.Fatalf("Failed to put schema version metadata: %v", err)
+		}
+	}
+
+	prod, err := c.GetSchemaVersionByMetadata("SalesforceAudit", "env", "prod")
+	if err != nil {
+		t.Fatalf("Failed to get schema version by metadata: %v", err)
+	}
+	if aws.Int64Value(prod.VersionNumber) != 3 || aws.StringValue(prod.SchemaVersionId) != versionIDs[3] {
+		t.Errorf("Version mismatch: expected 3 (%s), got %d (%s)", versionIDs[3], aws.Int64Value(prod.VersionNumber), aws.StringValue(prod.SchemaVersionId))
+	}
+
+	_, err = c.GetSchemaVersionByMetadata("SalesforceAudit", "env", "staging")
+	if !errors.Is(err, client.ErrAmbiguousMetadata) || !strings.Contains(err.Error(), "[2 4]") {
+		t.Errorf("Expected ErrAmbiguousMetadata naming versions 2 and 4, got %v", err)
+	}
+	if _, err := c.GetSchemaVersionByMetadata("SalesforceAudit", "env", "dev"); !errors.Is(err, client.ErrNoVersionWithMetadata) {
+		t.Errorf("Expected ErrNoVersionWithMetadata, got %v", err)
+	}
+	if _, err := c.GetSchemaVersionByMetadata("Missing", "env", "prod"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestRegistryQuotaExceeded(t *testing.T) {
+	fake := gluetest.New()
+	limit := awserr.New("ResourceNumberLimitExceededException", "Schema versions limit exceeded", nil)
+
+	var hookCalls int
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry",
+		client.WithOnQuotaExceeded(func(ctx context.Context, schemaName string, err error) bool {
+			hookCalls++
+			// Simulate freeing capacity on the first call only
+			if hookCalls == 1 {
+				fake.SetError("RegisterSchemaVersion", nil)
+				return true
+			}
+			return false
+		}))
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+
+	fake.SetError("RegisterSchemaVersion", limit)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withSource); err != nil {
+		t.Fatalf("Expected the retry after cleanup to succeed, got %v", err)
+	}
+
+	fake.SetError("CreateSchema", limit)
+	_, err := c.CreateSchema("Other", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone)
+	if !errors.Is(err, client.ErrRegistryQuotaExceeded) {
+		t.Fatalf("Expected ErrRegistryQuotaExceeded, got %v", err)
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != "ResourceNumberLimitExceededException" {
+		t.Errorf("Expected the AWS error to remain available, got %v", err)
+	}
+	if hookCalls != 2 {
+		t.Errorf("Expected 2 hook calls, got %d", hookCalls)
+	}
+
+	fake.SetError("CreateSchema", errors.New("boom"))
+	if _, err := c.CreateSchema("Other", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); errors.Is(err, client.ErrRegistryQuotaExceeded) {
+		t.Errorf("Expected other errors not to match ErrRegistryQuotaExceeded, got %v", err)
+	}
+}
+
+func TestSafeCompatibilityTransitions(t *testing.T) {
+	tests := map[client.Compatibility]string{
+		client.CompatibilityNone:        "[BACKWARD BACKWARD_ALL FORWARD FORWARD_ALL FULL FULL_ALL]",
+		client.CompatibilityBackward:    "[BACKWARD_ALL FULL FULL_ALL]",
+		client.CompatibilityForwardAll:  "[FULL_ALL]",
+		client.CompatibilityFull:        "[FULL_ALL]",
+		client.CompatibilityFullAll:     "[]",
+		client.CompatibilityDisabled:    "[]",
+		client.Compatibility("UNKNOWN"): "[]",
+	}
+	for current, expected := range tests {
+		if got := fmt.Sprint(client.SafeCompatibilityTransitions(current)); got != expected {
+			t.Errorf("Transitions mismatch from %s: expected %s, got %s", current, expected, got)
+		}
+	}
+}
+
+func TestLazySession(t *testing.T) {
+	fake := gluetest.New()
+	var sessions int
+	restore := client.SetNewGlueAPI(func(region string) (glueiface.GlueAPI, error) {
+		sessions++
+		return fake, nil
+	})
+	defer restore()
+
+	c, err := client.NewGlueSchemaRegistryClient("us-east-1", "test-registry", client.WithLazySession())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+	if sessions != 0 {
+		t.Fatalf("Expected no session before first use, got %d", sessions)
+	}
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.GetSchema("SalesforceAudit"); err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if sessions != 1 {
+		t.Errorf("Expected 1 session after use, got %d", sessions)
+	}
+}
+
+func TestLazySessionError(t *testing.T) {
+	restore := client.SetNewGlueAPI(func(region string) (glueiface.GlueAPI, error) {
+		return nil, errors.New("no region configured")
+	})
+	defer restore()
+
+	if _, err := client.NewGlueSchemaRegistryClient("", "test-registry"); err == nil {
+		t.Fatal("Expected eager construction to fail")
+	}
+
+	c, err := client.NewGlueSchemaRegistryClient("", "test-registry", client.WithLazySession())
+	if err != nil {
+		t.Fatalf("Expected lazy construction to succeed, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err := c.GetSchema("SalesforceAudit")
+		var registryErr *client.SchemaRegistryException
+		if !errors.As(err, &registryErr) || !strings.Contains(err.Error(), "no region configured") {
+			t.Errorf("Expected session error on call %d, got %v", i+1, err)
+		}
+	}
+}
+
+func TestDeleteSchema(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.GetDataFormat("SalesforceAudit"); err != nil {
+		t.Fatalf("Failed to get data format: %v", err)
+	}
+
+	result, err := c.DeleteSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to delete schema: %v", err)
+	}
+	if status := aws.StringValue(result.Status); status != "DELETING" {
+		t.Errorf("Status mismatch: expected DELETING, got %s", status)
+	}
+	if _, err := c.GetDataFormat("SalesforceAudit"); err == nil {
+		t.Error("Expected deleted schema to be evicted from the data format cache")
+	}
+
+	_, err = c.DeleteSchema("SalesforceAudit")
+	if !errors.Is(err, client.ErrSchemaNotFound) || !strings.HasPrefix(err.Error(), "Schema not found: SalesforceAudit") {
+		t.Fatalf("Expected ErrSchemaNotFound, got %v", err)
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != "EntityNotFoundException" {
+		t.Errorf("Expected EntityNotFoundException to remain available, got %v", err)
+	}
+}
+
+func TestRegistryLifecycle(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	if _, err := c.GetRegistry(); err == nil {
+		t.Error("Expected error getting a registry that does not exist")
+	}
+	created, err := c.CreateRegistry("Audit events")
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	if got := aws.StringValue(created.RegistryName); got != "test-registry" {
+		t.Errorf("RegistryName mismatch: expected test-registry, got %s", got)
+	}
+	if _, err := c.CreateRegistry("Audit events"); err == nil {
+		t.Error("Expected error creating a registry twice")
+	}
+
+	registry, err := c.GetRegistry()
+	if err != nil {
+		t.Fatalf("Failed to get registry: %v", err)
+	}
+	if got := aws.StringValue(registry.Description); got != "Audit events" {
+		t.Errorf("Description mismatch: expected Audit events, got %s", got)
+	}
+	if _, err := c.UpdateRegistry("Audit and login events"); err != nil {
+		t.Fatalf("Failed to update registry: %v", err)
+	}
+	if registry, err = c.GetRegistry(); err != nil || aws.StringValue(registry.Description) != "Audit and login events" {
+		t.Errorf("Expected updated description, got %v (error %v)", registry, err)
+	}
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	deleted, err := c.DeleteRegistry()
+	if err != nil {
+		t.Fatalf("Failed to delete registry: %v", err)
+	}
+	if got := aws.StringValue(deleted.Status); got != glue.RegistryStatusDeleting {
+		t.Errorf("Status mismatch: expected %s, got %s", glue.RegistryStatusDeleting, got)
+	}
+	if _, err := c.GetSchema("SalesforceAudit"); err == nil {
+		t.Error("Expected the registry's schemas to be deleted")
+	}
+	if _, err := c.UpdateRegistry("Audit events"); !errors.Is(err, client.ErrRegistryNotFound) {
+		t.Errorf("Expected ErrRegistryNotFound updating a deleted registry, got %v", err)
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	fake.SetLatency("GetSchema", time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetSchemaWithContext(ctx, "SalesforceAudit")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the call to stop at the deadline, took %v", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.UpdateSchemaCompatibilityWithContext(cancelled, "SalesforceAudit", client.CompatibilityFull); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if calls := fake.Calls("UpdateSchema"); calls != 0 {
+		t.Errorf("Expected no UpdateSchema calls after cancellation, got %d", calls)
+	}
+}
+
+func TestResourceTags(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	arn := client.RegistryARN("us-east-1", "123456789012", "test-registry")
+	if arn != "arn:aws:glue:us-east-1:123456789012:registry/test-registry" {
+		t.Errorf("RegistryARN mismatch: got %s", arn)
+	}
+	if got := client.RegistryARN("cn-north-1", "123456789012", "test-registry"); !strings.HasPrefix(got, "arn:aws-cn:") {
+		t.Errorf("Expected aws-cn partition for cn-north-1, got %s", got)
+	}
+
+	if err := c.TagResource(arn, map[string]string{"team": "audit", "cost-center": "1234"}); err != nil {
+		t.Fatalf("Failed to tag resource: %v", err)
+	}
+	if err := c.UntagResource(arn, []string{"cost-center", "missing"}); err != nil {
+		t.Fatalf("Failed to untag resource: %v", err)
+	}
+	tags, err := c.GetTags(arn)
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags) != 1 || tags["team"] != "audit" {
+		t.Errorf("Tags mismatch: expected map[team:audit], got %v", tags)
+	}
+}
+
+// identitySTS answers GetCallerIdentity with a fixed account ID, or with err if it is set
+type identitySTS struct {
+	stsiface.STSAPI
+	err   error
+	calls int
+}
+
+func (s *identitySTS) GetCallerIdentityWithContext(ctx aws.Context, input *sts.GetCallerIdentityInput, opts ...request.Option) (*sts.GetCallerIdentityOutput, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil
+}
+
+func TestRegistryAndSchemaArn(t *testing.T) {
+	api, err := client.BuildGlueAPI(client.ClientConfig{
+		Region:    "eu-west-1",
+		AWSConfig: aws.NewConfig().WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build Glue API: %v", err)
+	}
+	identity := &identitySTS{}
+	c := client.NewGlueSchemaRegistryClientWithAPI(api, "test-registry", client.WithSTSAPI(identity))
+	defer c.Close()
+
+	registryArn, err := c.RegistryArn()
+	if err != nil {
+		t.Fatalf("Failed to build registry ARN: %v", err)
+	}
+	if expected := "arn:aws:glue:eu-west-1:123456789012:registry/test-registry"; registryArn != expected {
+		t.Errorf("Registry ARN mismatch: expected %s, got %s", expected, registryArn)
+	}
+	schemaArn, err := c.SchemaArn("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to build schema ARN: %v", err)
+	}
+	if expected := "arn:aws:glue:eu-west-1:123456789012:schema/test-registry/SalesforceAudit"; schemaArn != expected {
+		t.Errorf("Schema ARN mismatch: expected %s, got %s", expected, schemaArn)
+	}
+	if identity.calls != 1 {
+		t.Errorf("GetCallerIdentity calls mismatch: expected 1, got %d", identity.calls)
+	}
+
+	denied := &identitySTS{err: awserr.New("AccessDenied", "User is not authorized to perform: sts:GetCallerIdentity", nil)}
+	c = client.NewGlueSchemaRegistryClientWithAPI(api, "test-registry", client.WithSTSAPI(denied))
+	defer c.Close()
+	if _, err := c.RegistryArn(); !errors.Is(err, client.ErrAccountIDAccessDenied) {
+		t.Errorf("Expected ErrAccountIDAccessDenied, got %v", err)
+	}
+
+	// The region of a Glue API that is not an SDK client is unknown
+	c = client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry", client.WithSTSAPI(identity))
+	defer c.Close()
+	if _, err := c.SchemaArn("SalesforceAudit"); err == nil {
+		t.Error("Expected an error for a Glue API without a region")
+	}
+}
+
+// recordingLogger keeps every line logged through it
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) record(level, msg string, keysAndValues []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := level + " " + msg
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] == "latency" {
+			continue
+		}
+		line += fmt.Sprintf(" %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+	l.lines = append(l.lines, line)
+}
+
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) { l.record("DEBUG", msg, kv) }
+func (l *recordingLogger) Info(msg string, kv ...interface{})  { l.record("INFO", msg, kv) }
+func (l *recordingLogger) Warn(msg string, kv ...interface{})  { l.record("WARN", msg, kv) }
+
+func TestLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry", client.WithLogger(logger))
+	defer c.Close()
+
+	created, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.GetSchemaVersion("SalesforceAudit", 1); err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if _, err := c.GetSchemaVersion("SalesforceAudit", 7); err == nil {
+		t.Fatal("Expected error getting a version that does not exist")
+	}
+
+	expected := []string{
+		"INFO glue schema registry call operation=CreateSchema registry=test-registry schema=SalesforceAudit version=" + aws.StringValue(created.SchemaVersionId),
+		"DEBUG glue schema registry call operation=GetSchemaVersion registry=test-registry schema=SalesforceAudit version=1",
+		"WARN glue schema registry call failed operation=GetSchemaVersion registry=test-registry schema=SalesforceAudit version=7 error_code=EntityNotFoundException",
+	}
+	if len(logger.lines) != len(expected) {
+		t.Fatalf("Expected %d log lines, got %d: %v", len(expected), len(logger.lines), logger.lines)
+	}
+	for i, line := range logger.lines {
+		if !strings.HasPrefix(line, expected[i]) {
+			t.Errorf("Log line %d mismatch: expected %s, got %s", i, expected[i], line)
+		}
+	}
+
+	var buf bytes.Buffer
+	slogged := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry",
+		client.WithLogger(client.NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))))
+	defer slogged.Close()
+	if _, err := slogged.GetSchema("SalesforceAudit"); err == nil {
+		t.Fatal("Expected error getting a schema that does not exist")
+	}
+	if out := buf.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "operation=GetSchema") {
+		t.Errorf("Expected a slog warning for the failed call, got %q", out)
+	}
+}
+
+// recordingClientMetrics keeps the Glue calls and cache lookups it receives
+type recordingClientMetrics struct {
+	client.NopMetricsRecorder
+
+	mu    sync.Mutex
+	calls []string
+	hits  []bool
+}
+
+func (m *recordingClientMetrics) RecordGlueCall(op string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		op += " failed"
+	}
+	m.calls = append(m.calls, op)
+}
+
+func (m *recordingClientMetrics) RecordCacheHit(schemaName string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hits = append(m.hits, hit)
+}
+
+func TestClientMetrics(t *testing.T) {
+	metrics := &recordingClientMetrics{}
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry",
+		client.WithMetrics(metrics), client.WithSchemaVersionCache(true))
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetSchemaVersion("SalesforceAudit", 1); err != nil {
+			t.Fatalf("Failed to get schema version: %v", err)
+		}
+	}
+	if _, err := c.GetSchema("Missing"); err == nil {
+		t.Fatal("Expected error getting a schema that does not exist")
+	}
+
+	expectedCalls := []string{"CreateSchema", "GetSchemaVersion", "GetSchema failed"}
+	if fmt.Sprint(metrics.calls) != fmt.Sprint(expectedCalls) {
+		t.Errorf("Glue calls mismatch: expected %v, got %v", expectedCalls, metrics.calls)
+	}
+	if expectedHits := []bool{false, true}; fmt.Sprint(metrics.hits) != fmt.Sprint(expectedHits) {
+		t.Errorf("Cache hits mismatch: expected %v, got %v", expectedHits, metrics.hits)
+	}
+}
+
+func TestListSchemasPagination(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	// More schemas than fit in one default-sized Glue page
+	const total = 150
+	for i := 0; i < total; i++ {
+		if _, err := c.CreateSchema(fmt.Sprintf("Schema%03d", i), "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+	}
+
+	schemas, err := c.ListSchemas()
+	if err != nil {
+		t.Fatalf("Failed to list schemas: %v", err)
+	}
+	if len(schemas) != total {
+		t.Fatalf("Schema count mismatch: expected %d, got %d", total, len(schemas))
+	}
+	if calls := fake.Calls("ListSchemas"); calls != 2 {
+		t.Errorf("ListSchemas call count mismatch: expected 2, got %d", calls)
+	}
+
+	var names []string
+	var token *string
+	for pages := 0; ; pages++ {
+		if pages > total {
+			t.Fatal("ListSchemasPaginated did not terminate")
+		}
+		page, next, err := c.ListSchemasPaginated(40, token)
+		if err != nil {
+			t.Fatalf("Failed to list schema page: %v", err)
+		}
+		if len(page) > 40 {
+			t.Fatalf("Page size mismatch: expected at most 40, got %d", len(page))
+		}
+		for _, s := range page {
+			names = append(names, aws.StringValue(s.SchemaName))
+		}
+		if next == nil {
+			break
+		}
+		token = next
+	}
+	if len(names) != total || names[0] != "Schema000" || names[total-1] != fmt.Sprintf("Schema%03d", total-1) {
+		t.Errorf("Paginated schemas mismatch: got %d schemas from %v to %v", len(names), names[0], names[len(names)-1])
+	}
+}
+
+func TestListSchemaVersionsPagination(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	// More versions than fit in one default-sized Glue page
+	const total = 150
+	for i := 2; i <= total; i++ {
+		definition := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+			fmt.Sprintf(`"fields": [{"name": "field%03d", "type": "string", "default": ""},`, i), 1)
+		if _, err := c.RegisterSchemaVersion("SalesforceAudit", definition); err != nil {
+			t.Fatalf("Failed to register schema version %d: %v", i, err)
+		}
+	}
+
+	versions, err := c.ListSchemaVersions("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to list schema versions: %v", err)
+	}
+	if len(versions) != total {
+		t.Fatalf("Version count mismatch: expected %d, got %d", total, len(versions))
+	}
+	for i, v := range versions {
+		if number := aws.Int64Value(v.VersionNumber); number != int64(i+1) {
+			t.Fatalf("Version number mismatch at %d: expected %d, got %d", i, i+1, number)
+		}
+	}
+	if calls := fake.Calls("ListSchemaVersions"); calls != 2 {
+		t.Errorf("ListSchemaVersions call count mismatch: expected 2, got %d", calls)
+	}
+}
+
+func TestSchemaVersionCache(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry", client.WithSchemaVersionCache(true))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		version, err := c.GetSchemaVersion("SalesforceAudit", 1)
+		if err != nil {
+			t.Fatalf("Failed to get schema version: %v", err)
+		}
+		if aws.StringValue(version.SchemaDefinition) != gluetest.SalesforceAuditAvroSchema {
+			t.Errorf("SchemaDefinition mismatch: got %s", aws.StringValue(version.SchemaDefinition))
+		}
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 1 {
+		t.Errorf("GetSchemaVersion call count mismatch: expected 1, got %d", calls)
+	}
+
+	// Missing versions are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetSchemaVersion("SalesforceAudit", 2); err == nil {
+			t.Fatal("Expected error for a missing version")
+		}
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 3 {
+		t.Errorf("GetSchemaVersion call count mismatch: expected 3, got %d", calls)
+	}
+
+	c.InvalidateSchemaCache("SalesforceAudit")
+	if _, err := c.GetSchemaVersion("SalesforceAudit", 1); err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 4 {
+		t.Errorf("GetSchemaVersion call count mismatch after InvalidateSchemaCache: expected 4, got %d", calls)
+	}
+
+	if _, err := c.DeleteSchema("SalesforceAudit"); err != nil {
+		t.Fatalf("Failed to delete schema: %v", err)
+	}
+	if _, err := c.GetSchemaVersion("SalesforceAudit", 1); err == nil {
+		t.Error("Expected DeleteSchema to evict cached versions")
+	}
+
+	uncached := client.NewGlueSchemaRegistryClientWithAPI(fake, "other-registry")
+	defer uncached.Close()
+	if _, err := uncached.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	before := fake.Calls("GetSchemaVersion")
+	for i := 0; i < 2; i++ {
+		if _, err := uncached.GetSchemaVersion("SalesforceAudit", 1); err != nil {
+			t.Fatalf("Failed to get schema version: %v", err)
+		}
+	}
+	if calls := fake.Calls("GetSchemaVersion") - before; calls != 2 {
+		t.Errorf("Expected the cache to be disabled by default, got %d GetSchemaVersion calls", calls)
+	}
+}
+
+func TestBuildGlueAPIFromConfig(t *testing.T) {
+	// Keep the test independent of the machine's AWS configuration
+	configFile := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configFile, []byte("[profile registry]\nregion = ap-southeast-2\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	static := credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", "")
+	tests := []struct {
+		name           string
+		cfg            client.ClientConfig
+		expectRegion   string
+		expectEndpoint string
+		expectStatic   bool
+	}{
+		{
+			name:           "Region",
+			cfg:            client.ClientConfig{Region: "eu-west-1", AWSConfig: aws.NewConfig().WithCredentials(static)},
+			expectRegion:   "eu-west-1",
+			expectEndpoint: "https://glue.eu-west-1.amazonaws.com",
+			expectStatic:   true,
+		},
+		{
+			name: "LocalStack",
+			cfg: client.ClientConfig{
+				Region:     "us-east-1",
+				AWSConfig:  aws.NewConfig().WithCredentials(static),
+				Endpoint:   "localhost:4566",
+				DisableSSL: true,
+			},
+			expectRegion:   "us-east-1",
+			expectEndpoint: "http://localhost:4566",
+			expectStatic:   true,
+		},
+		{
+			name:         "Profile",
+			cfg:          client.ClientConfig{Profile: "registry", AWSConfig: aws.NewConfig().WithCredentials(static)},
+			expectRegion: "ap-southeast-2",
+			expectStatic: true,
+		},
+		{
+			name: "AssumeRoleWithEndpoint",
+			cfg: client.ClientConfig{
+				Region:     "eu-west-1",
+				AWSConfig:  aws.NewConfig().WithCredentials(static),
+				RoleARN:    "arn:aws:iam::123456789012:role/schema-registry",
+				ExternalID: "registry-consumer",
+				Endpoint:   "https://glue.example.internal",
+			},
+			expectRegion:   "eu-west-1",
+			expectEndpoint: "https://glue.example.internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, err := client.BuildGlueAPI(tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to build Glue API: %v", err)
+			}
+			info := api.(*glue.Glue).Client
+			if aws.StringValue(info.Config.Region) != tt.expectRegion {
+				t.Errorf("Region mismatch: expected %s, got %s", tt.expectRegion, aws.StringValue(info.Config.Region))
+			}
+			if tt.expectEndpoint != "" && info.Endpoint != tt.expectEndpoint {
+				t.Errorf("Endpoint mismatch: expected %s, got %s", tt.expectEndpoint, info.Endpoint)
+			}
+			if usesStatic := info.Config.Credentials == static; usesStatic != tt.expectStatic {
+				t.Errorf("Credentials mismatch: expected static credentials %v, got %v", tt.expectStatic, usesStatic)
+			}
+		})
+	}
+}
+
+func TestRetryThrottledCalls(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	tests := []struct {
+		name          string
+		failures      int
+		status        int
+		code          string
+		expectError   bool
+		expectRequest int
+	}{
+		{name: "ThrottledThenSucceeds", failures: 2, status: http.StatusBadRequest, code: "ThrottlingException", expectRequest: 3},
+		{name: "ThrottledUntilRetriesRunOut", failures: 5, status: http.StatusBadRequest, code: "ThrottlingException", expectError: true, expectRequest: 3},
+		{name: "ServerErrorThenSucceeds", failures: 1, status: http.StatusInternalServerError, code: "InternalServiceException", expectRequest: 2},
+		{name: "EntityNotFoundFailsFast", failures: 5, status: http.StatusBadRequest, code: "EntityNotFoundException", expectError: true, expectRequest: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+				if requests <= tt.failures {
+					w.WriteHeader(tt.status)
+					fmt.Fprintf(w, `{"__type": %q, "message": "injected failure"}`, tt.code)
+					return
+				}
+				fmt.Fprint(w, `{"SchemaName": "SalesforceAudit", "RegistryName": "test-registry", "DataFormat": "AVRO", "LatestSchemaVersion": 1}`)
+			}))
+			defer server.Close()
+
+			c, err := client.NewGlueSchemaRegistryClientWithConfig(client.ClientConfig{
+				Region:       "us-east-1",
+				RegistryName: "test-registry",
+				Endpoint:     server.URL,
+				AWSConfig:    aws.NewConfig().WithCredentials(credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", "")),
+			}, client.WithMaxRetries(2), client.WithRetryBaseDelay(time.Millisecond))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			defer c.Close()
+
+			result, err := c.GetSchema("SalesforceAudit")
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Error mismatch: expected error %v, got %v", tt.expectError, err)
+			}
+			if err == nil && aws.StringValue(result.SchemaName) != "SalesforceAudit" {
+				t.Errorf("SchemaName mismatch: expected SalesforceAudit, got %s", aws.StringValue(result.SchemaName))
+			}
+			if requests != tt.expectRequest {
+				t.Errorf("Request count mismatch: expected %d, got %d", tt.expectRequest, requests)
+			}
+		})
+	}
+}
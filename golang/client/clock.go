@@ -0,0 +1,38 @@
+package client
+
+import "time"
+
+// Clock is the source of time for the client: the delays of WaitForSchemaVersionAvailable
+// and of the backoff in fan-out methods such as ListSchemasWithLatestVersion, and the
+// latencies reported by WithLogger and WithMetrics. Timeouts and deadlines still come
+// from contexts, which always use real time.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTimer returns a channel that receives the time once d has elapsed, and a function
+	// that stops the timer, as time.Timer.Stop does, so that an abandoned wait releases it
+	NewTimer(d time.Duration) (<-chan time.Time, func() bool)
+}
+
+// WithClock makes the client read time from clock instead of the system clock, so that
+// tests can run backoff and polling without waiting. A nil clock uses the system clock.
+func WithClock(clock Clock) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		if clock == nil {
+			clock = systemClock{}
+		}
+		c.clock = clock
+	}
+}
+
+// systemClock is the Clock of the time package
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
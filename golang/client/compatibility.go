@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws-glue-schema-registry/golang/compat"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// CheckSchemaCompatibility reports, without contacting Glue, the changes from an old to a
+// new Avro definition that the given compatibility mode would reject. See package compat
+// for the rules applied.
+func CheckSchemaCompatibility(oldDefinition, newDefinition string, mode Compatibility) ([]compat.BreakingChange, error) {
+	return compat.Check(oldDefinition, newDefinition, string(mode))
+}
+
+// SchemaCompatibilityReport is the result of checking one schema in AuditCompatibility
+type SchemaCompatibilityReport struct {
+	SchemaName    string
+	LatestVersion int64
+	Compatible    bool
+	// Changes lists the breaking changes found, keyed by the earlier version the latest
+	// version was compared with
+	Changes map[int64][]compat.BreakingChange
+	// Err is set when the schema could not be checked, for example because it is not an
+	// Avro schema; Compatible is then false
+	Err error
+}
+
+// AuditCompatibility reports, for every schema in the registry, whether its latest version
+// would be accepted under mode. The latest version is compared with the version before it,
+// or with every earlier version for the _ALL modes. Nothing is changed in the registry.
+func (c *GlueSchemaRegistryClient) AuditCompatibility(mode Compatibility) ([]SchemaCompatibilityReport, error) {
+	return c.AuditCompatibilityWithContext(context.Background(), mode)
+}
+
+// AuditCompatibilityWithContext is like AuditCompatibility but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) AuditCompatibilityWithContext(ctx context.Context, mode Compatibility) ([]SchemaCompatibilityReport, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schemas, err := c.ListSchemasWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]SchemaCompatibilityReport, 0, len(schemas))
+	for _, item := range schemas {
+		reports = append(reports, c.auditSchema(ctx, aws.StringValue(item.SchemaName), mode))
+	}
+	return reports, nil
+}
+
+func (c *GlueSchemaRegistryClient) auditSchema(ctx context.Context, schemaName string, mode Compatibility) SchemaCompatibilityReport {
+	report := SchemaCompatibilityReport{SchemaName: schemaName}
+
+	schema, err := c.GetSchemaWithContext(ctx, schemaName)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	if dataFormat := aws.StringValue(schema.DataFormat); dataFormat != "AVRO" {
+		report.Err = fmt.Errorf("compatibility audit is not supported for data format %s", dataFormat)
+		return report
+	}
+
+	latest, changes, err := c.compatibilityChanges(ctx, schemaName, "", mode)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	if latest == 0 {
+		report.Err = fmt.Errorf("schema has no available versions")
+		return report
+	}
+	report.LatestVersion = latest
+	if len(changes) > 0 {
+		report.Changes = changes
+	}
+	report.Compatible = len(report.Changes) == 0
+	return report
+}
+
+// compatibilityChanges checks candidate against the available versions of a schema that
+// a new version must be compatible with under mode: the latest, or every one for the _ALL
+// modes. An empty candidate stands for the latest available version, which is checked
+// against the versions before it. It returns the latest available version, or 0 if the
+// schema has none, and the breaking changes by the version they break.
+func (c *GlueSchemaRegistryClient) compatibilityChanges(ctx context.Context, schemaName, candidate string, mode Compatibility) (int64, map[int64][]compat.BreakingChange, error) {
+	versionList, err := c.ListSchemaVersionsWithContext(ctx, schemaName)
+	if err != nil {
+		return 0, nil, err
+	}
+	var versions []int64
+	for _, v := range versionList {
+		if aws.StringValue(v.Status) == glue.SchemaVersionStatusAvailable {
+			versions = append(versions, aws.Int64Value(v.VersionNumber))
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	if len(versions) == 0 {
+		return 0, nil, nil
+	}
+
+	latest := versions[len(versions)-1]
+	earlier := versions
+	if candidate == "" {
+		earlier = versions[:len(versions)-1]
+	}
+	if !strings.HasSuffix(string(mode), "_ALL") && len(earlier) > 1 {
+		earlier = earlier[len(earlier)-1:]
+	}
+	fetch := earlier
+	if candidate == "" {
+		fetch = append(append([]int64(nil), earlier...), latest)
+	}
+
+	definitions, errs := c.GetSchemaVersionsWithContext(ctx, schemaName, fetch)
+	if len(errs) > 0 {
+		return 0, nil, errs[0]
+	}
+	if candidate == "" {
+		candidate = aws.StringValue(definitions[latest].SchemaDefinition)
+	}
+	breaking := make(map[int64][]compat.BreakingChange)
+	for _, version := range earlier {
+		changes, err := CheckSchemaCompatibility(aws.StringValue(definitions[version].SchemaDefinition), candidate, mode)
+		if err != nil {
+			return 0, nil, fmt.Errorf("version %d: %w", version, err)
+		}
+		if len(changes) > 0 {
+			breaking[version] = changes
+		}
+	}
+	return latest, breaking, nil
+}
+
+// Guarantees made by each compatibility mode about newly registered versions
+const (
+	backwardLatest = 1 << iota
+	backwardAll
+	forwardLatest
+	forwardAll
+)
+
+var compatibilityGuarantees = []struct {
+	mode       Compatibility
+	guarantees int
+}{
+	{CompatibilityNone, 0},
+	{CompatibilityBackward, backwardLatest},
+	{CompatibilityBackwardAll, backwardLatest | backwardAll},
+	{CompatibilityForward, forwardLatest},
+	{CompatibilityForwardAll, forwardLatest | forwardAll},
+	{CompatibilityFull, backwardLatest | forwardLatest},
+	{CompatibilityFullAll, backwardLatest | backwardAll | forwardLatest | forwardAll},
+}
+
+// SafeCompatibilityTransitions returns the modes a schema can move to from current without
+// weakening any guarantee its consumers rely on: every guarantee of current is kept, so
+// the target is at least as strict. The current mode itself is not included.
+// DISABLED, which blocks new versions altogether, is never suggested, and no transition
+// out of it is safe because any other mode admits changes consumers have not seen.
+// Unknown modes have no safe transitions.
+func SafeCompatibilityTransitions(current Compatibility) []Compatibility {
+	currentGuarantees := -1
+	for _, g := range compatibilityGuarantees {
+		if g.mode == current {
+			currentGuarantees = g.guarantees
+		}
+	}
+	if currentGuarantees < 0 {
+		return nil
+	}
+
+	var safe []Compatibility
+	for _, g := range compatibilityGuarantees {
+		if g.mode != current && g.guarantees&currentGuarantees == currentGuarantees {
+			safe = append(safe, g.mode)
+		}
+	}
+	return safe
+}
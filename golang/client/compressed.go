@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// GetSchemaVersionCompressed returns the definition of a schema version as gzip-compressed
+// bytes, for shipping schema text to consumers over constrained links. Use
+// DecompressSchemaDefinition to recover the definition.
+func (c *GlueSchemaRegistryClient) GetSchemaVersionCompressed(schemaName string, versionNumber int64) ([]byte, error) {
+	return c.GetSchemaVersionCompressedWithContext(context.Background(), schemaName, versionNumber)
+}
+
+// GetSchemaVersionCompressedWithContext is like GetSchemaVersionCompressed but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaVersionCompressedWithContext(ctx context.Context, schemaName string, versionNumber int64) ([]byte, error) {
+	version, err := c.GetSchemaVersionWithContext(ctx, schemaName, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := CompressSchemaDefinition(aws.StringValue(version.SchemaDefinition))
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to compress schema version: %s (version %d)", schemaName, versionNumber),
+			Err:     err,
+		}
+	}
+	return compressed, nil
+}
+
+// CompressSchemaDefinition gzip-compresses a schema definition
+func CompressSchemaDefinition(definition string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(w, definition); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressSchemaDefinition reverses CompressSchemaDefinition and GetSchemaVersionCompressed
+func DecompressSchemaDefinition(compressed []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", fmt.Errorf("failed to read compressed schema definition: %w", err)
+	}
+	defer r.Close()
+
+	definition, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress schema definition: %w", err)
+	}
+	return string(definition), nil
+}
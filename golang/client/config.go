@@ -0,0 +1,115 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+)
+
+// ClientConfig configures the AWS session built by NewGlueSchemaRegistryClientWithConfig.
+// Only RegistryName is required; every other field falls back to the AWS SDK defaults.
+type ClientConfig struct {
+	// Region is the AWS region of the registry. It takes precedence over a region set in
+	// AWSConfig or in the shared config file.
+	Region string
+
+	// RegistryName is the registry used when no RegistryResolver is configured
+	RegistryName string
+
+	// AWSConfig is the base AWS configuration, for example to supply explicit credentials,
+	// an HTTP client or retry settings
+	AWSConfig *aws.Config
+
+	// Profile selects a named profile from the shared config and credentials files
+	Profile string
+
+	// RoleARN, if set, is assumed through STS using the session's credentials, and Glue is
+	// called with the temporary credentials of the role. This is needed when the registry
+	// lives in a different account.
+	RoleARN string
+
+	// ExternalID is passed to STS when assuming RoleARN, if the role's trust policy requires one
+	ExternalID string
+
+	// Endpoint overrides the Glue endpoint URL, for example for a VPC endpoint or a local
+	// emulator such as LocalStack at http://localhost:4566. It does not apply to the STS
+	// calls made to assume RoleARN. When empty, the regional Glue endpoint is used.
+	Endpoint string
+
+	// DisableSSL calls Glue over plain HTTP, as local emulators usually require. It applies
+	// to Endpoint values without a scheme and to the default endpoint. Glue has no
+	// virtual-hosted addressing, so unlike S3 no path-style setting is needed.
+	DisableSSL bool
+}
+
+// NewGlueSchemaRegistryClientWithConfig creates a new GlueSchemaRegistryClient with an AWS
+// session built from cfg. The AWS session is created before returning unless WithLazySession is used.
+func NewGlueSchemaRegistryClientWithConfig(cfg ClientConfig, opts ...Option) (*GlueSchemaRegistryClient, error) {
+	c := newClient(nil, cfg.RegistryName, opts)
+	cfg.AWSConfig = c.applyRetryer(cfg.AWSConfig)
+	c.connect = func() (glueiface.GlueAPI, error) {
+		return newGlueAPI(cfg)
+	}
+	if !c.lazySession {
+		if _, err := c.api(); err != nil {
+			return nil, c.connectErr
+		}
+	}
+	return c, nil
+}
+
+// newGlueAPI creates the Glue API client used by the session-based constructors; it is a
+// variable so tests can observe when sessions are created
+var newGlueAPI = buildGlueAPI
+
+func buildGlueAPI(cfg ClientConfig) (glueiface.GlueAPI, error) {
+	sess, err := newSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var glueConfig []*aws.Config
+	if cfg.Endpoint != "" {
+		glueConfig = append(glueConfig, aws.NewConfig().WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.DisableSSL {
+		glueConfig = append(glueConfig, aws.NewConfig().WithDisableSSL(true))
+	}
+	return glue.New(sess, glueConfig...), nil
+}
+
+func newSession(cfg ClientConfig) (*session.Session, error) {
+	awsConfig := aws.NewConfig()
+	if cfg.AWSConfig != nil {
+		awsConfig = cfg.AWSConfig.Copy()
+	}
+	if cfg.Region != "" {
+		awsConfig.WithRegion(cfg.Region)
+	}
+
+	options := session.Options{
+		Config:  *awsConfig,
+		Profile: cfg.Profile,
+	}
+	if cfg.Profile != "" {
+		// A named profile usually carries its region in the config file, not just the credentials file
+		options.SharedConfigState = session.SharedConfigEnable
+	}
+	sess, err := session.NewSessionWithOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	if cfg.RoleARN != "" {
+		creds := stscreds.NewCredentials(sess, cfg.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if cfg.ExternalID != "" {
+				p.ExternalID = aws.String(cfg.ExternalID)
+			}
+		})
+		sess = sess.Copy(aws.NewConfig().WithCredentials(creds))
+	}
+	return sess, nil
+}
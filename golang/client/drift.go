@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-glue-schema-registry/golang/schemadiff"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// DriftResult compares a local schema definition with the latest registered version.
+// Changes lists field differences from the registered to the local definition. It can be
+// empty while InSync is false when the definitions differ outside individual fields.
+type DriftResult struct {
+	InSync  bool
+	Changes []schemadiff.FieldChange
+}
+
+// DetectDrift reports whether localDefinition matches the latest registered version of a
+// schema, ignoring formatting differences. A schema registered with a different data format
+// is reported as out of sync with a single change at the root.
+func (c *GlueSchemaRegistryClient) DetectDrift(schemaName, localDefinition, dataFormat string) (DriftResult, error) {
+	return c.DetectDriftWithContext(context.Background(), schemaName, localDefinition, dataFormat)
+}
+
+// DetectDriftWithContext is like DetectDrift but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) DetectDriftWithContext(ctx context.Context, schemaName, localDefinition, dataFormat string) (DriftResult, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return DriftResult{}, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schema, err := c.GetSchemaWithContext(ctx, schemaName)
+	if err != nil {
+		return DriftResult{}, err
+	}
+	if schema.LatestSchemaVersion == nil {
+		return DriftResult{}, &SchemaRegistryException{
+			Message: fmt.Sprintf("Schema has no latest version: %s", schemaName),
+			Err:     ErrNoLatestVersion,
+		}
+	}
+	latest, err := c.GetSchemaVersionWithContext(ctx, schemaName, *schema.LatestSchemaVersion)
+	if err != nil {
+		return DriftResult{}, err
+	}
+
+	if registeredFormat := aws.StringValue(schema.DataFormat); registeredFormat != dataFormat {
+		return DriftResult{Changes: []schemadiff.FieldChange{
+			{Kind: schemadiff.FieldChanged, Old: registeredFormat, New: dataFormat},
+		}}, nil
+	}
+
+	registered := aws.StringValue(latest.SchemaDefinition)
+	normalizedRegistered, err := schemadiff.Normalize(registered)
+	if err != nil {
+		return DriftResult{}, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to normalize registered schema: %s", schemaName),
+			Err:     err,
+		}
+	}
+	normalizedLocal, err := schemadiff.Normalize(localDefinition)
+	if err != nil {
+		return DriftResult{}, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to normalize local schema: %s", schemaName),
+			Err:     err,
+		}
+	}
+	if normalizedRegistered == normalizedLocal {
+		return DriftResult{InSync: true}, nil
+	}
+
+	changes, err := schemadiff.DiffFields(registered, localDefinition, dataFormat)
+	if err != nil {
+		return DriftResult{}, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to compare schema: %s", schemaName),
+			Err:     err,
+		}
+	}
+	return DriftResult{Changes: changes}, nil
+}
+
+// CompareSchemaVersions reports the field differences from version v1 to version v2 of a
+// schema, classified as schemadiff.DiffSchemas does. Only AVRO and JSON schemas can be
+// compared.
+func (c *GlueSchemaRegistryClient) CompareSchemaVersions(schemaName string, v1, v2 int64) (schemadiff.SchemaDiff, error) {
+	return c.CompareSchemaVersionsWithContext(context.Background(), schemaName, v1, v2)
+}
+
+// CompareSchemaVersionsWithContext is like CompareSchemaVersions but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) CompareSchemaVersionsWithContext(ctx context.Context, schemaName string, v1, v2 int64) (schemadiff.SchemaDiff, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return schemadiff.SchemaDiff{}, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	oldVersion, err := c.GetSchemaVersionWithContext(ctx, schemaName, v1)
+	if err != nil {
+		return schemadiff.SchemaDiff{}, err
+	}
+	newVersion, err := c.GetSchemaVersionWithContext(ctx, schemaName, v2)
+	if err != nil {
+		return schemadiff.SchemaDiff{}, err
+	}
+
+	d, err := schemadiff.DiffSchemas(aws.StringValue(oldVersion.SchemaDefinition),
+		aws.StringValue(newVersion.SchemaDefinition), aws.StringValue(oldVersion.DataFormat))
+	if err != nil {
+		return schemadiff.SchemaDiff{}, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to compare schema versions: %s (versions %d and %d)", schemaName, v1, v2),
+			Err:     err,
+		}
+	}
+	return d, nil
+}
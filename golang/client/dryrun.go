@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws-glue-schema-registry/golang/compat"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// ErrInvalidSchemaDefinition is matched by errors from CheckSchemaVersionValidity and the
+// dry runs when Glue reports that a definition is not valid for its data format
+var ErrInvalidSchemaDefinition = errors.New("invalid schema definition")
+
+// DryRunAction is what a create or register call would do
+type DryRunAction string
+
+const (
+	// DryRunCreateSchema means CreateSchema would create the schema with the definition as version 1
+	DryRunCreateSchema DryRunAction = "CREATE_SCHEMA"
+	// DryRunRegisterVersion means RegisterSchemaVersion would register a new version
+	DryRunRegisterVersion DryRunAction = "REGISTER_VERSION"
+	// DryRunNoChange means the definition is already registered and nothing would change
+	DryRunNoChange DryRunAction = "NO_CHANGE"
+	// DryRunRejected means Glue would reject the new version under the schema's compatibility mode
+	DryRunRejected DryRunAction = "REJECTED"
+)
+
+// DryRunResult describes the change a create or register call would make
+type DryRunResult struct {
+	Action     DryRunAction
+	SchemaName string
+	// VersionNumber is the number the definition would be registered as, or already has
+	VersionNumber int64
+	// BreakingChanges lists, for DryRunRejected, the changes that break the compatibility
+	// mode, keyed by the earlier version they break
+	BreakingChanges map[int64][]compat.BreakingChange
+	// Description is a one-line human-readable summary of the intended action
+	Description string
+}
+
+// CheckSchemaVersionValidity asks Glue whether schemaDefinition is a valid definition for
+// dataFormat. An invalid definition is reported with an error matching
+// ErrInvalidSchemaDefinition and carrying Glue's reason.
+func (c *GlueSchemaRegistryClient) CheckSchemaVersionValidity(dataFormat DataFormat, schemaDefinition string) error {
+	return c.CheckSchemaVersionValidityWithContext(context.Background(), dataFormat, schemaDefinition)
+}
+
+// CheckSchemaVersionValidityWithContext is like CheckSchemaVersionValidity but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) CheckSchemaVersionValidityWithContext(ctx context.Context, dataFormat DataFormat, schemaDefinition string) error {
+	if err := dataFormat.validate(); err != nil {
+		return &SchemaRegistryException{Message: "Failed to check schema version validity", Err: err}
+	}
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	input := &glue.CheckSchemaVersionValidityInput{
+		DataFormat:       aws.String(string(dataFormat)),
+		SchemaDefinition: aws.String(c.definition(schemaDefinition)),
+	}
+
+	result, err := api.CheckSchemaVersionValidityWithContext(ctx, input)
+	if err != nil {
+		return &SchemaRegistryException{
+			Message:   "Failed to check schema version validity",
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+	if !aws.BoolValue(result.Valid) {
+		return &SchemaRegistryException{
+			Message: fmt.Sprintf("Schema definition is not valid %s", dataFormat),
+			Err:     fmt.Errorf("%w: %s", ErrInvalidSchemaDefinition, aws.StringValue(result.Error)),
+		}
+	}
+	return nil
+}
+
+// CreateSchemaDryRun reports what CreateSchema would do with the same arguments, without
+// changing the registry. The definition is checked with CheckSchemaVersionValidity, and
+// the errors CreateSchema would return for an invalid definition or an existing schema
+// are returned instead of a result.
+func (c *GlueSchemaRegistryClient) CreateSchemaDryRun(schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*DryRunResult, error) {
+	return c.CreateSchemaDryRunWithContext(context.Background(), schemaName, dataFormat, schemaDefinition, compatibility)
+}
+
+// CreateSchemaDryRunWithContext is like CreateSchemaDryRun but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) CreateSchemaDryRunWithContext(ctx context.Context, schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*DryRunResult, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	if err := c.CheckSchemaVersionValidityWithContext(ctx, dataFormat, schemaDefinition); err != nil {
+		return nil, err
+	}
+	exists, err := c.SchemaExistsWithContext(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		err := awserr.New(glue.ErrCodeAlreadyExistsException, fmt.Sprintf("Schema already exists. SchemaName: %s", schemaName), nil)
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to create schema: %s", schemaName),
+			Err:       err,
+			ErrorCode: err.Code(),
+		}
+	}
+
+	if compatibility == "" {
+		// Glue's default
+		compatibility = CompatibilityBackward
+	}
+	return &DryRunResult{
+		Action:        DryRunCreateSchema,
+		SchemaName:    schemaName,
+		VersionNumber: 1,
+		Description:   fmt.Sprintf("would create %s schema %s in registry %s with compatibility %s", dataFormat, schemaName, registryName, compatibility),
+	}, nil
+}
+
+// RegisterSchemaVersionDryRun reports what RegisterSchemaVersion would do with the same
+// arguments, without changing the registry. The definition is checked with
+// CheckSchemaVersionValidity and, for Avro schemas, against the schema's compatibility
+// mode as AuditCompatibility checks it; a definition that mode would reject gives a
+// DryRunRejected result. Glue does not evaluate compatibility without registering, so
+// JSON and Protobuf definitions are only checked for validity.
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionDryRun(schemaName, schemaDefinition string) (*DryRunResult, error) {
+	return c.RegisterSchemaVersionDryRunWithContext(context.Background(), schemaName, schemaDefinition)
+}
+
+// RegisterSchemaVersionDryRunWithContext is like RegisterSchemaVersionDryRun but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) RegisterSchemaVersionDryRunWithContext(ctx context.Context, schemaName, schemaDefinition string) (*DryRunResult, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schema, err := c.GetSchemaWithContext(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	dataFormat := DataFormat(aws.StringValue(schema.DataFormat))
+	if err := c.CheckSchemaVersionValidityWithContext(ctx, dataFormat, schemaDefinition); err != nil {
+		return nil, err
+	}
+
+	existing, err := c.GetSchemaByDefinitionWithContext(ctx, schemaName, schemaDefinition)
+	if err == nil {
+		version, err := c.GetSchemaVersionByIdWithContext(ctx, aws.StringValue(existing.SchemaVersionId))
+		if err != nil {
+			return nil, err
+		}
+		number := aws.Int64Value(version.VersionNumber)
+		return &DryRunResult{
+			Action:        DryRunNoChange,
+			SchemaName:    schemaName,
+			VersionNumber: number,
+			Description:   fmt.Sprintf("definition is already registered as version %d of %s", number, schemaName),
+		}, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+
+	next := aws.Int64Value(schema.NextSchemaVersion)
+	if next == 0 {
+		next = aws.Int64Value(schema.LatestSchemaVersion) + 1
+	}
+	result := &DryRunResult{
+		Action:        DryRunRegisterVersion,
+		SchemaName:    schemaName,
+		VersionNumber: next,
+		Description:   fmt.Sprintf("would register version %d of %s", next, schemaName),
+	}
+
+	mode := Compatibility(aws.StringValue(schema.Compatibility))
+	switch {
+	case mode == CompatibilityDisabled:
+		result.Action = DryRunRejected
+		result.Description = fmt.Sprintf("compatibility mode DISABLED of %s allows no new versions", schemaName)
+	case dataFormat == DataFormatAvro && mode != CompatibilityNone && mode != "":
+		_, changes, err := c.compatibilityChanges(ctx, schemaName, schemaDefinition, mode)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			result.Action = DryRunRejected
+			result.BreakingChanges = changes
+			result.Description = fmt.Sprintf("version %d of %s would be rejected under %s: %s", next, schemaName, mode, describeBreaking(changes))
+		}
+	}
+	return result, nil
+}
+
+// describeBreaking lists breaking changes by the version they break, oldest first
+func describeBreaking(breaking map[int64][]compat.BreakingChange) string {
+	versions := make([]int64, 0, len(breaking))
+	for version := range breaking {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	var parts []string
+	for _, version := range versions {
+		for _, change := range breaking[version] {
+			parts = append(parts, fmt.Sprintf("version %d: %s", version, change))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
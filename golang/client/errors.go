@@ -0,0 +1,87 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// ErrSchemaNotFound is matched by errors from DeleteSchema when the schema does not exist.
+// The EntityNotFoundException from AWS remains available through errors.As.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// ErrRegistryNotFound is matched by errors from UpdateRegistry when the registry does not exist.
+// The EntityNotFoundException from AWS remains available through errors.As.
+var ErrRegistryNotFound = errors.New("registry not found")
+
+// ErrInvalidDataFormat is matched by errors from CreateSchema when the data format is not
+// one of the DataFormat constants
+var ErrInvalidDataFormat = errors.New("invalid data format")
+
+// ErrNoLatestVersion is matched by errors reporting that a schema has no latest version to
+// use, for example because its only version failed validation or is still pending
+var ErrNoLatestVersion = errors.New("schema has no latest version")
+
+func isEntityNotFound(err error) bool {
+	return errorCode(err) == glue.ErrCodeEntityNotFoundException
+}
+
+// errorCode returns the code of the AWS error in err's chain, or an empty string if there is none
+func errorCode(err error) string {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code()
+	}
+	return ""
+}
+
+// IsNotFound reports whether err, or any error it wraps, reports that a registry, schema
+// or schema version does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrSchemaNotFound) || errors.Is(err, ErrRegistryNotFound) || isEntityNotFound(err)
+}
+
+// IsAlreadyExists reports whether err, or any error it wraps, reports that a registry or
+// schema being created already exists, for example because another process created it first
+func IsAlreadyExists(err error) bool {
+	return errorCode(err) == glue.ErrCodeAlreadyExistsException
+}
+
+// IsThrottling reports whether err, or any error it wraps, is an AWS throttling error such
+// as ThrottlingException that is worth retrying after a delay
+func IsThrottling(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && request.IsErrorThrottle(awsErr)
+}
+
+// ErrorFormatter renders a SchemaRegistryException as the string returned by its Error method
+type ErrorFormatter func(e *SchemaRegistryException) string
+
+// DefaultErrorFormatter renders "Message: underlying error", or just the message when
+// there is no underlying error
+func DefaultErrorFormatter(e *SchemaRegistryException) string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var errorFormatter atomic.Pointer[ErrorFormatter]
+
+func init() {
+	SetErrorFormatter(DefaultErrorFormatter)
+}
+
+// SetErrorFormatter changes how every SchemaRegistryException in the process is rendered,
+// for example as JSON for log pipelines. It is safe to call concurrently with Error, but is
+// meant to be called once during start-up. A nil formatter restores DefaultErrorFormatter.
+func SetErrorFormatter(fn ErrorFormatter) {
+	if fn == nil {
+		fn = DefaultErrorFormatter
+	}
+	errorFormatter.Store(&fn)
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// ExportManifestFile is the name of the manifest ExportRegistry writes to the export directory
+const ExportManifestFile = "manifest.json"
+
+// ExportManifest describes the schemas of a registry exported by ExportRegistry. Registry
+// is nil if the registry itself could not be found, as for registries Glue created
+// implicitly.
+type ExportManifest struct {
+	RegistryName string            `json:"registryName"`
+	Registry     *ExportedRegistry `json:"registry,omitempty"`
+	Schemas      []ExportedSchema  `json:"schemas"`
+}
+
+// ExportedRegistry is the configuration of the registry of an ExportManifest, which
+// ImportRegistry creates the registry with if it does not exist
+type ExportedRegistry struct {
+	Description string            `json:"description,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// ExportedSchema is one schema of an ExportManifest, with its versions in ascending order
+type ExportedSchema struct {
+	SchemaName    string            `json:"schemaName"`
+	DataFormat    string            `json:"dataFormat"`
+	Compatibility string            `json:"compatibility"`
+	Description   string            `json:"description,omitempty"`
+	Versions      []ExportedVersion `json:"versions"`
+}
+
+// ExportedVersion is one version of an ExportedSchema. File is the path of its definition
+// relative to the export directory.
+type ExportedVersion struct {
+	VersionNumber int64  `json:"versionNumber"`
+	Status        string `json:"status"`
+	File          string `json:"file"`
+}
+
+// ExportRegistry writes every version of every schema in the registry to dir, creating it
+// if needed. The definition of each version is written to <schema>/<version>.<ext>, where
+// ext is avsc, json or proto by data format, and ExportManifestFile lists the registry's
+// description and tags and the schemas with their data format, compatibility mode,
+// description and versions. The manifest is written last, so a directory without one
+// holds an incomplete export.
+//
+// Schemas are exported concurrently, by up to WithBatchConcurrency at a time, and throttled
+// calls are retried with backoff as for ListSchemasWithLatestVersion. Schemas and versions
+// deleted while the export runs are left out.
+func (c *GlueSchemaRegistryClient) ExportRegistry(dir string) error {
+	return c.ExportRegistryWithContext(context.Background(), dir)
+}
+
+// ExportRegistryWithContext is like ExportRegistry but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) ExportRegistryWithContext(ctx context.Context, dir string) error {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	var schemas []*glue.SchemaListItem
+	err = c.withBackoff(ctx, func() error {
+		var err error
+		schemas, err = c.ListSchemasWithContext(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas {
+		// Glue allows dots in schema names, so "." and ".." must not become directories
+		if name := aws.StringValue(schema.SchemaName); !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+			return &SchemaRegistryException{
+				Message: fmt.Sprintf("Failed to export schema: %s", name),
+				Err:     fmt.Errorf("schema name %q is not a valid directory name", name),
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return &SchemaRegistryException{Message: fmt.Sprintf("Failed to export registry: %s", registryName), Err: err}
+	}
+
+	workers := c.batchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(schemas) {
+		workers = len(schemas)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exported := make([]ExportedSchema, len(schemas))
+	errs := make([]error, len(schemas))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var failOnce sync.Once
+	var failure error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				exported[i], errs[i] = c.exportSchema(ctx, dir, aws.StringValue(schemas[i].SchemaName))
+				if errs[i] != nil && !IsNotFound(errs[i]) {
+					// Stop the remaining exports; the first failure is the one reported
+					failOnce.Do(func() {
+						failure = errs[i]
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	for i := range schemas {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if failure != nil {
+		return failure
+	}
+
+	registry, err := c.exportRegistryConfig(ctx)
+	if err != nil {
+		return err
+	}
+	manifest := ExportManifest{RegistryName: registryName, Registry: registry, Schemas: make([]ExportedSchema, 0, len(schemas))}
+	for i, schema := range exported {
+		if errs[i] == nil {
+			manifest.Schemas = append(manifest.Schemas, schema)
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, ExportManifestFile), append(data, '\n'), 0o644)
+	}
+	if err != nil {
+		return &SchemaRegistryException{Message: fmt.Sprintf("Failed to export registry: %s", registryName), Err: err}
+	}
+	return nil
+}
+
+// exportRegistryConfig returns the description and tags of the registry, or nil if it
+// cannot be found
+func (c *GlueSchemaRegistryClient) exportRegistryConfig(ctx context.Context) (*ExportedRegistry, error) {
+	var registry *glue.GetRegistryOutput
+	err := c.withBackoff(ctx, func() error {
+		var err error
+		registry, err = c.GetRegistryWithContext(ctx)
+		return err
+	})
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tags map[string]string
+	err = c.withBackoff(ctx, func() error {
+		var err error
+		tags, err = c.GetTagsWithContext(ctx, aws.StringValue(registry.RegistryArn))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		tags = nil
+	}
+	return &ExportedRegistry{Description: aws.StringValue(registry.Description), Tags: tags}, nil
+}
+
+// exportSchema writes the definitions of every version of a schema to dir/schemaName. It
+// fails with a NotFound error only if the schema itself is gone.
+func (c *GlueSchemaRegistryClient) exportSchema(ctx context.Context, dir, schemaName string) (ExportedSchema, error) {
+	schema, err := c.getSchemaWithBackoff(ctx, schemaName)
+	if err != nil {
+		return ExportedSchema{}, err
+	}
+	var versions []*glue.SchemaVersionListItem
+	err = c.withBackoff(ctx, func() error {
+		var err error
+		versions, err = c.ListSchemaVersionsWithContext(ctx, schemaName)
+		return err
+	})
+	if err != nil {
+		return ExportedSchema{}, err
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return aws.Int64Value(versions[i].VersionNumber) < aws.Int64Value(versions[j].VersionNumber)
+	})
+
+	exported := ExportedSchema{
+		SchemaName:    schemaName,
+		DataFormat:    aws.StringValue(schema.DataFormat),
+		Compatibility: aws.StringValue(schema.Compatibility),
+		Description:   aws.StringValue(schema.Description),
+		Versions:      make([]ExportedVersion, 0, len(versions)),
+	}
+	if err := os.MkdirAll(filepath.Join(dir, schemaName), 0o755); err != nil {
+		return ExportedSchema{}, &SchemaRegistryException{Message: fmt.Sprintf("Failed to export schema: %s", schemaName), Err: err}
+	}
+	for _, item := range versions {
+		versionNumber := aws.Int64Value(item.VersionNumber)
+		var version *glue.GetSchemaVersionOutput
+		err := c.withBackoff(ctx, func() error {
+			var err error
+			version, err = c.GetSchemaVersionWithContext(ctx, schemaName, versionNumber)
+			return err
+		})
+		if IsNotFound(err) {
+			// The version was deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return ExportedSchema{}, err
+		}
+
+		file := filepath.Join(schemaName, strconv.FormatInt(versionNumber, 10)+"."+exportExtension(exported.DataFormat))
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(aws.StringValue(version.SchemaDefinition)), 0o644); err != nil {
+			return ExportedSchema{}, &SchemaRegistryException{
+				Message: fmt.Sprintf("Failed to export schema version: %s (version %d)", schemaName, versionNumber),
+				Err:     err,
+			}
+		}
+		exported.Versions = append(exported.Versions, ExportedVersion{
+			VersionNumber: versionNumber,
+			Status:        aws.StringValue(item.Status),
+			File:          filepath.ToSlash(file),
+		})
+	}
+	return exported, nil
+}
+
+// exportExtension returns the file extension of definitions in dataFormat
+func exportExtension(dataFormat string) string {
+	switch DataFormat(dataFormat) {
+	case DataFormatAvro:
+		return "avsc"
+	case DataFormatJSON:
+		return "json"
+	case DataFormatProtobuf:
+		return "proto"
+	default:
+		return "txt"
+	}
+}
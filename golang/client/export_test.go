@@ -0,0 +1,26 @@
+package client
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+)
+
+// SetNewGlueAPI replaces the Glue API constructor used by the session-based constructors
+func SetNewGlueAPI(fn func(region string) (glueiface.GlueAPI, error)) (restore func()) {
+	previous := newGlueAPI
+	newGlueAPI = func(cfg ClientConfig) (glueiface.GlueAPI, error) {
+		return fn(cfg.Region)
+	}
+	return func() { newGlueAPI = previous }
+}
+
+// BuildGlueAPI creates a Glue API client from cfg the way NewGlueSchemaRegistryClientWithConfig does
+var BuildGlueAPI = buildGlueAPI
+
+// SetWaitPollDelay replaces the delays between the status checks of WaitForSchemaVersionAvailable
+func SetWaitPollDelay(delay, maxDelay time.Duration) (restore func()) {
+	previousDelay, previousMax := waitPollDelay, waitMaxPollDelay
+	waitPollDelay, waitMaxPollDelay = delay, maxDelay
+	return func() { waitPollDelay, waitMaxPollDelay = previousDelay, previousMax }
+}
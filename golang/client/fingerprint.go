@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/linkedin/goavro/v2"
+)
+
+// FingerprintMetadataKey is the schema version metadata key under which
+// RegisterSchemaByFingerprint stores the Avro fingerprint of a definition
+const FingerprintMetadataKey = "avro-fingerprint-rabin"
+
+// AvroFingerprint returns the 64-bit Rabin fingerprint of the Parsing Canonical Form of an
+// Avro definition, as 16 hexadecimal digits. Definitions that differ only in formatting,
+// documentation or defaults have the same fingerprint.
+func AvroFingerprint(definition string) (string, error) {
+	codec, err := goavro.NewCodec(definition)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Avro schema: %w", err)
+	}
+	return fmt.Sprintf("%016x", codec.Rabin), nil
+}
+
+// FingerprintRegistration is the result of RegisterSchemaByFingerprint
+type FingerprintRegistration struct {
+	// SchemaName is the schema holding the definition, which differs from the requested
+	// name when an existing schema was reused
+	SchemaName      string
+	SchemaVersionId string
+	Fingerprint     string
+	// Reused is true when no schema was created because one with the same fingerprint exists
+	Reused bool
+}
+
+// RegisterSchemaByFingerprint creates an Avro schema unless a schema whose latest version
+// has the same fingerprint already exists in the registry, in which case that schema is
+// returned instead. Versions created here are tagged with their fingerprint in schema
+// version metadata; schemas registered by other means are not found. Tagging is
+// best-effort: a failure is logged and does not fail the registration.
+func (c *GlueSchemaRegistryClient) RegisterSchemaByFingerprint(schemaName, schemaDefinition string, compatibility Compatibility) (*FingerprintRegistration, error) {
+	return c.RegisterSchemaByFingerprintWithContext(context.Background(), schemaName, schemaDefinition, compatibility)
+}
+
+// RegisterSchemaByFingerprintWithContext is like RegisterSchemaByFingerprint but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) RegisterSchemaByFingerprintWithContext(ctx context.Context, schemaName, schemaDefinition string, compatibility Compatibility) (*FingerprintRegistration, error) {
+	fingerprint, err := AvroFingerprint(schemaDefinition)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to fingerprint schema: %s", schemaName),
+			Err:     err,
+		}
+	}
+
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schemas, err := c.ListSchemasWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range schemas {
+		result, err := api.QuerySchemaVersionMetadataWithContext(ctx, &glue.QuerySchemaVersionMetadataInput{
+			SchemaId: &glue.SchemaId{
+				RegistryName: aws.String(registryName),
+				SchemaName:   item.SchemaName,
+			},
+			SchemaVersionNumber: &glue.SchemaVersionNumber{LatestVersion: aws.Bool(true)},
+			MetadataList: []*glue.MetadataKeyValuePair{
+				{MetadataKey: aws.String(FingerprintMetadataKey), MetadataValue: aws.String(fingerprint)},
+			},
+		})
+		if IsNotFound(err) {
+			// A schema deleted since it was listed, or one being deleted, cannot match
+			continue
+		}
+		if err != nil {
+			return nil, &SchemaRegistryException{
+				Message:   fmt.Sprintf("Failed to query schema version metadata: %s", aws.StringValue(item.SchemaName)),
+				Err:       err,
+				ErrorCode: errorCode(err),
+			}
+		}
+		if info, ok := result.MetadataInfoMap[FingerprintMetadataKey]; ok && aws.StringValue(info.MetadataValue) == fingerprint {
+			return &FingerprintRegistration{
+				SchemaName:      aws.StringValue(item.SchemaName),
+				SchemaVersionId: aws.StringValue(result.SchemaVersionId),
+				Fingerprint:     fingerprint,
+				Reused:          true,
+			}, nil
+		}
+	}
+
+	created, err := c.CreateSchemaWithContext(ctx, schemaName, DataFormatAvro, schemaDefinition, compatibility)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.PutSchemaVersionMetadataWithContext(ctx, aws.StringValue(created.SchemaVersionId), FingerprintMetadataKey, fingerprint); err != nil {
+		c.logf("glue schema registry: failed to store fingerprint of %s: %v", schemaName, err)
+	}
+
+	return &FingerprintRegistration{
+		SchemaName:      schemaName,
+		SchemaVersionId: aws.StringValue(created.SchemaVersionId),
+		Fingerprint:     fingerprint,
+	}, nil
+}
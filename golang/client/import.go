@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// ImportSummary counts what ImportRegistry created and what it skipped because the
+// registry already had it. RegistryCreated reports whether the registry itself was
+// created. VersionsSkipped also counts exported versions that were not AVAILABLE, which
+// are never imported.
+type ImportSummary struct {
+	RegistryCreated bool
+	SchemasCreated  int
+	SchemasSkipped  int
+	VersionsCreated int
+	VersionsSkipped int
+}
+
+// ImportRegistry recreates in the registry the schemas of a directory written by
+// ExportRegistry. The versions of each schema are registered in version order, so that
+// their version numbers match the export when the schema is new and no versions were
+// deleted before the export. It is safe to run again: schemas that exist and versions whose
+// definition is already registered are skipped.
+//
+// A registry that does not exist is created with the exported description and tags; the
+// configuration of an existing registry is left as it is.
+//
+// A new schema is created with the exported description and compatibility NONE, so that
+// its history registers whatever mode it was written under, and gets the exported
+// compatibility mode once its versions are registered. Versions missing from an existing
+// schema are checked against its current mode, which is then set to the exported one if
+// it differs.
+func (c *GlueSchemaRegistryClient) ImportRegistry(dir string) (ImportSummary, error) {
+	return c.ImportRegistryWithContext(context.Background(), dir)
+}
+
+// ImportRegistryWithContext is like ImportRegistry but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) ImportRegistryWithContext(ctx context.Context, dir string) (ImportSummary, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return ImportSummary{}, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	data, err := os.ReadFile(filepath.Join(dir, ExportManifestFile))
+	if err != nil {
+		return ImportSummary{}, &SchemaRegistryException{Message: fmt.Sprintf("Failed to import registry: %s", registryName), Err: err}
+	}
+	var manifest ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ImportSummary{}, &SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to import registry: %s", registryName),
+			Err:     fmt.Errorf("invalid manifest %s: %w", ExportManifestFile, err),
+		}
+	}
+
+	var summary ImportSummary
+	if manifest.Registry != nil {
+		if summary.RegistryCreated, err = c.importRegistryConfig(ctx, *manifest.Registry); err != nil {
+			return summary, err
+		}
+	}
+	for _, schema := range manifest.Schemas {
+		if err := c.importSchema(ctx, dir, schema, &summary); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// importRegistryConfig creates the registry with the exported configuration unless it
+// exists, and reports whether it did
+func (c *GlueSchemaRegistryClient) importRegistryConfig(ctx context.Context, registry ExportedRegistry) (bool, error) {
+	err := c.withBackoff(ctx, func() error {
+		_, err := c.GetRegistryWithContext(ctx)
+		return err
+	})
+	if err == nil || !IsNotFound(err) {
+		return false, err
+	}
+
+	var created *glue.CreateRegistryOutput
+	err = c.withBackoff(ctx, func() error {
+		var err error
+		created, err = c.CreateRegistryWithContext(ctx, registry.Description)
+		return err
+	})
+	if IsAlreadyExists(err) {
+		// Another process created the registry since GetRegistry failed
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(registry.Tags) == 0 {
+		return true, nil
+	}
+	return true, c.withBackoff(ctx, func() error {
+		return c.TagResourceWithContext(ctx, aws.StringValue(created.RegistryArn), registry.Tags)
+	})
+}
+
+// importSchema creates schema and its missing versions, adding what it did to summary
+func (c *GlueSchemaRegistryClient) importSchema(ctx context.Context, dir string, schema ExportedSchema, summary *ImportSummary) error {
+	definitions := make([]string, 0, len(schema.Versions))
+	for _, version := range schema.Versions {
+		if version.Status != glue.SchemaVersionStatusAvailable {
+			summary.VersionsSkipped++
+			continue
+		}
+		file := filepath.FromSlash(version.File)
+		if !filepath.IsLocal(file) {
+			return &SchemaRegistryException{
+				Message: fmt.Sprintf("Failed to import schema version: %s (version %d)", schema.SchemaName, version.VersionNumber),
+				Err:     fmt.Errorf("file %q is outside the export directory", version.File),
+			}
+		}
+		definition, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return &SchemaRegistryException{
+				Message: fmt.Sprintf("Failed to import schema version: %s (version %d)", schema.SchemaName, version.VersionNumber),
+				Err:     err,
+			}
+		}
+		definitions = append(definitions, string(definition))
+	}
+	if len(definitions) == 0 {
+		// There is nothing to create the schema with
+		return nil
+	}
+
+	var existing *glue.GetSchemaOutput
+	err := c.withBackoff(ctx, func() error {
+		var err error
+		existing, err = c.GetSchemaWithContext(ctx, schema.SchemaName)
+		return err
+	})
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	if existing == nil {
+		err := c.withBackoff(ctx, func() error {
+			_, err := c.createSchema(ctx, schema.SchemaName, DataFormat(schema.DataFormat), definitions[0], CompatibilityNone, schema.Description)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		summary.SchemasCreated++
+		summary.VersionsCreated++
+		definitions = definitions[1:]
+	} else {
+		summary.SchemasSkipped++
+	}
+
+	for _, definition := range definitions {
+		err := c.withBackoff(ctx, func() error {
+			_, err := c.GetSchemaByDefinitionWithContext(ctx, schema.SchemaName, definition)
+			return err
+		})
+		if err == nil {
+			summary.VersionsSkipped++
+			continue
+		}
+		if !IsNotFound(err) {
+			return err
+		}
+		err = c.withBackoff(ctx, func() error {
+			_, err := c.RegisterSchemaVersionWithContext(ctx, schema.SchemaName, definition)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		summary.VersionsCreated++
+	}
+
+	current := string(CompatibilityNone)
+	if existing != nil {
+		current = aws.StringValue(existing.Compatibility)
+	}
+	if schema.Compatibility == "" || schema.Compatibility == current {
+		return nil
+	}
+	return c.withBackoff(ctx, func() error {
+		_, err := c.UpdateSchemaCompatibilityWithContext(ctx, schema.SchemaName, Compatibility(schema.Compatibility))
+		return err
+	})
+}
@@ -0,0 +1,43 @@
+package client
+
+import "github.com/aws/aws-sdk-go/service/glue/glueiface"
+
+// WithLazySession defers creating the AWS session in NewGlueSchemaRegistryClient and
+// NewGlueSchemaRegistryClientWithConfig until the client is first used. Construction then
+// cannot fail on session setup; a failure is returned, wrapped in SchemaRegistryException,
+// from the first call and every call after it. The session is created at most once, even
+// under concurrent first use. The option has no effect on
+// NewGlueSchemaRegistryClientWithAPI.
+func WithLazySession() Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.lazySession = true
+	}
+}
+
+// api returns the Glue API client, creating it on first use if construction was deferred,
+// and wrapped to log calls and record metrics if WithLogger or WithMetrics was used
+func (c *GlueSchemaRegistryClient) api() (glueiface.GlueAPI, error) {
+	glueClient, err := c.connected()
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   "Failed to initialize Glue client",
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+	if c.logger != nil || c.metrics != nil {
+		return instrumentedAPI{GlueAPI: glueClient, logger: c.logger, metrics: c.metrics, clock: c.clock}, nil
+	}
+	return glueClient, nil
+}
+
+// connected returns the Glue API client, creating it on first use if construction was
+// deferred, or the error that creating it failed with
+func (c *GlueSchemaRegistryClient) connected() (glueiface.GlueAPI, error) {
+	c.connectOnce.Do(func() {
+		if c.glueClient == nil && c.connect != nil {
+			c.glueClient, c.connectErr = c.connect()
+		}
+	})
+	return c.glueClient, c.connectErr
+}
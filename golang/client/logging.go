@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+)
+
+// Logger receives structured log lines from the client. keysAndValues alternate between
+// a string key and its value, as with log/slog. Implementations must be safe for
+// concurrent use.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// WithLogger logs every Glue call made by the client's methods: successful reads at Debug,
+// successful writes such as CreateSchema and RegisterSchemaVersion at Info, and failures
+// at Warn. Each line has the keys "operation", "latency" and, where the call concerns
+// them, "registry", "schema", "version" and "resource"; failures add "error_code" and "error".
+// Background failures, such as a failed version prune, are logged at Warn instead of
+// with log.Printf.
+//
+// Without this option nothing is logged apart from those background failures. Calls made
+// directly through GlueClient are never logged.
+func WithLogger(l Logger) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.logger = l
+		c.logf = func(format string, args ...interface{}) {
+			l.Warn(fmt.Sprintf(format, args...))
+		}
+	}
+}
+
+// NewSlogLogger adapts a *slog.Logger to Logger. A nil l uses slog.Default().
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return slogLogger{l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	s.l.Debug(msg, keysAndValues...)
+}
+
+func (s slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	s.l.Info(msg, keysAndValues...)
+}
+
+func (s slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	s.l.Warn(msg, keysAndValues...)
+}
+
+// instrumentedAPI logs and records metrics for the Glue operations used by the client,
+// passing every call through. Either logger or metrics may be nil.
+type instrumentedAPI struct {
+	glueiface.GlueAPI
+	logger  Logger
+	metrics MetricsRecorder
+	clock   Clock
+}
+
+// observe reports a call to op that started at start. Successful calls are logged at
+// Info if write is set and at Debug otherwise, failures at Warn.
+func (a instrumentedAPI) observe(write bool, op string, start time.Time, err error, keysAndValues ...interface{}) {
+	latency := a.clock.Now().Sub(start)
+	if a.metrics != nil {
+		a.metrics.RecordGlueCall(op, latency, err)
+	}
+	if a.logger == nil {
+		return
+	}
+
+	kv := append([]interface{}{"operation", op}, keysAndValues...)
+	kv = append(kv, "latency", latency)
+	switch {
+	case err != nil:
+		a.logger.Warn("glue schema registry call failed", append(kv, "error_code", errorCode(err), "error", err)...)
+	case write:
+		a.logger.Info("glue schema registry call", kv...)
+	default:
+		a.logger.Debug("glue schema registry call", kv...)
+	}
+}
+
+// schemaKeys returns the registry and schema named by id
+func schemaKeys(id *glue.SchemaId) []interface{} {
+	if id == nil {
+		return nil
+	}
+	if id.SchemaArn != nil {
+		return []interface{}{"schema", aws.StringValue(id.SchemaArn)}
+	}
+	return []interface{}{"registry", aws.StringValue(id.RegistryName), "schema", aws.StringValue(id.SchemaName)}
+}
+
+// registryKeys returns the registry named by id
+func registryKeys(id *glue.RegistryId) []interface{} {
+	if id == nil {
+		return nil
+	}
+	if id.RegistryArn != nil {
+		return []interface{}{"registry", aws.StringValue(id.RegistryArn)}
+	}
+	return []interface{}{"registry", aws.StringValue(id.RegistryName)}
+}
+
+// versionKeys returns the version named by a version id, number or the latest flag
+func versionKeys(id *string, number *glue.SchemaVersionNumber) []interface{} {
+	switch {
+	case id != nil:
+		return []interface{}{"version", aws.StringValue(id)}
+	case number != nil && number.VersionNumber != nil:
+		return []interface{}{"version", aws.Int64Value(number.VersionNumber)}
+	case number != nil && aws.BoolValue(number.LatestVersion):
+		return []interface{}{"version", "latest"}
+	}
+	return nil
+}
+
+func (a instrumentedAPI) CreateSchemaWithContext(ctx context.Context, input *glue.CreateSchemaInput, opts ...request.Option) (*glue.CreateSchemaOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.CreateSchemaWithContext(ctx, input, opts...)
+	kv := append(registryKeys(input.RegistryId), "schema", aws.StringValue(input.SchemaName))
+	if err == nil {
+		kv = append(kv, "version", aws.StringValue(result.SchemaVersionId))
+	}
+	a.observe(true, "CreateSchema", start, err, kv...)
+	return result, err
+}
+
+func (a instrumentedAPI) DeleteSchemaWithContext(ctx context.Context, input *glue.DeleteSchemaInput, opts ...request.Option) (*glue.DeleteSchemaOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.DeleteSchemaWithContext(ctx, input, opts...)
+	a.observe(true, "DeleteSchema", start, err, schemaKeys(input.SchemaId)...)
+	return result, err
+}
+
+func (a instrumentedAPI) DeleteSchemaVersionsWithContext(ctx context.Context, input *glue.DeleteSchemaVersionsInput, opts ...request.Option) (*glue.DeleteSchemaVersionsOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.DeleteSchemaVersionsWithContext(ctx, input, opts...)
+	a.observe(true, "DeleteSchemaVersions", start, err, append(schemaKeys(input.SchemaId), "version", aws.StringValue(input.Versions))...)
+	return result, err
+}
+
+func (a instrumentedAPI) GetSchemaWithContext(ctx context.Context, input *glue.GetSchemaInput, opts ...request.Option) (*glue.GetSchemaOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.GetSchemaWithContext(ctx, input, opts...)
+	kv := schemaKeys(input.SchemaId)
+	if err == nil {
+		kv = append(kv, "version", aws.Int64Value(result.LatestSchemaVersion))
+	}
+	a.observe(false, "GetSchema", start, err, kv...)
+	return result, err
+}
+
+func (a instrumentedAPI) GetSchemaVersionWithContext(ctx context.Context, input *glue.GetSchemaVersionInput, opts ...request.Option) (*glue.GetSchemaVersionOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.GetSchemaVersionWithContext(ctx, input, opts...)
+	kv := schemaKeys(input.SchemaId)
+	if err == nil {
+		kv = append(kv, "version", aws.Int64Value(result.VersionNumber))
+	} else {
+		kv = append(kv, versionKeys(input.SchemaVersionId, input.SchemaVersionNumber)...)
+	}
+	a.observe(false, "GetSchemaVersion", start, err, kv...)
+	return result, err
+}
+
+func (a instrumentedAPI) GetSchemaByDefinitionWithContext(ctx context.Context, input *glue.GetSchemaByDefinitionInput, opts ...request.Option) (*glue.GetSchemaByDefinitionOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.GetSchemaByDefinitionWithContext(ctx, input, opts...)
+	kv := schemaKeys(input.SchemaId)
+	if err == nil {
+		kv = append(kv, "version", aws.StringValue(result.SchemaVersionId))
+	}
+	a.observe(false, "GetSchemaByDefinition", start, err, kv...)
+	return result, err
+}
+
+func (a instrumentedAPI) CheckSchemaVersionValidityWithContext(ctx context.Context, input *glue.CheckSchemaVersionValidityInput, opts ...request.Option) (*glue.CheckSchemaVersionValidityOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.CheckSchemaVersionValidityWithContext(ctx, input, opts...)
+	a.observe(false, "CheckSchemaVersionValidity", start, err, "data_format", aws.StringValue(input.DataFormat))
+	return result, err
+}
+
+func (a instrumentedAPI) ListSchemasWithContext(ctx context.Context, input *glue.ListSchemasInput, opts ...request.Option) (*glue.ListSchemasOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.ListSchemasWithContext(ctx, input, opts...)
+	a.observe(false, "ListSchemas", start, err, registryKeys(input.RegistryId)...)
+	return result, err
+}
+
+func (a instrumentedAPI) ListSchemaVersionsWithContext(ctx context.Context, input *glue.ListSchemaVersionsInput, opts ...request.Option) (*glue.ListSchemaVersionsOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.ListSchemaVersionsWithContext(ctx, input, opts...)
+	a.observe(false, "ListSchemaVersions", start, err, schemaKeys(input.SchemaId)...)
+	return result, err
+}
+
+func (a instrumentedAPI) UpdateSchemaWithContext(ctx context.Context, input *glue.UpdateSchemaInput, opts ...request.Option) (*glue.UpdateSchemaOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.UpdateSchemaWithContext(ctx, input, opts...)
+	a.observe(true, "UpdateSchema", start, err, append(schemaKeys(input.SchemaId), versionKeys(nil, input.SchemaVersionNumber)...)...)
+	return result, err
+}
+
+func (a instrumentedAPI) RegisterSchemaVersionWithContext(ctx context.Context, input *glue.RegisterSchemaVersionInput, opts ...request.Option) (*glue.RegisterSchemaVersionOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.RegisterSchemaVersionWithContext(ctx, input, opts...)
+	kv := schemaKeys(input.SchemaId)
+	if err == nil {
+		kv = append(kv, "version", aws.Int64Value(result.VersionNumber))
+	}
+	a.observe(true, "RegisterSchemaVersion", start, err, kv...)
+	return result, err
+}
+
+func (a instrumentedAPI) PutSchemaVersionMetadataWithContext(ctx context.Context, input *glue.PutSchemaVersionMetadataInput, opts ...request.Option) (*glue.PutSchemaVersionMetadataOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.PutSchemaVersionMetadataWithContext(ctx, input, opts...)
+	a.observe(true, "PutSchemaVersionMetadata", start, err, versionKeys(input.SchemaVersionId, input.SchemaVersionNumber)...)
+	return result, err
+}
+
+func (a instrumentedAPI) QuerySchemaVersionMetadataWithContext(ctx context.Context, input *glue.QuerySchemaVersionMetadataInput, opts ...request.Option) (*glue.QuerySchemaVersionMetadataOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.QuerySchemaVersionMetadataWithContext(ctx, input, opts...)
+	a.observe(false, "QuerySchemaVersionMetadata", start, err, versionKeys(input.SchemaVersionId, input.SchemaVersionNumber)...)
+	return result, err
+}
+
+func (a instrumentedAPI) CreateRegistryWithContext(ctx context.Context, input *glue.CreateRegistryInput, opts ...request.Option) (*glue.CreateRegistryOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.CreateRegistryWithContext(ctx, input, opts...)
+	a.observe(true, "CreateRegistry", start, err, "registry", aws.StringValue(input.RegistryName))
+	return result, err
+}
+
+func (a instrumentedAPI) GetRegistryWithContext(ctx context.Context, input *glue.GetRegistryInput, opts ...request.Option) (*glue.GetRegistryOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.GetRegistryWithContext(ctx, input, opts...)
+	a.observe(false, "GetRegistry", start, err, registryKeys(input.RegistryId)...)
+	return result, err
+}
+
+func (a instrumentedAPI) UpdateRegistryWithContext(ctx context.Context, input *glue.UpdateRegistryInput, opts ...request.Option) (*glue.UpdateRegistryOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.UpdateRegistryWithContext(ctx, input, opts...)
+	a.observe(true, "UpdateRegistry", start, err, registryKeys(input.RegistryId)...)
+	return result, err
+}
+
+func (a instrumentedAPI) DeleteRegistryWithContext(ctx context.Context, input *glue.DeleteRegistryInput, opts ...request.Option) (*glue.DeleteRegistryOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.DeleteRegistryWithContext(ctx, input, opts...)
+	a.observe(true, "DeleteRegistry", start, err, registryKeys(input.RegistryId)...)
+	return result, err
+}
+
+func (a instrumentedAPI) GetTagsWithContext(ctx context.Context, input *glue.GetTagsInput, opts ...request.Option) (*glue.GetTagsOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.GetTagsWithContext(ctx, input, opts...)
+	a.observe(false, "GetTags", start, err, "resource", aws.StringValue(input.ResourceArn))
+	return result, err
+}
+
+func (a instrumentedAPI) TagResourceWithContext(ctx context.Context, input *glue.TagResourceInput, opts ...request.Option) (*glue.TagResourceOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.TagResourceWithContext(ctx, input, opts...)
+	a.observe(true, "TagResource", start, err, "resource", aws.StringValue(input.ResourceArn))
+	return result, err
+}
+
+func (a instrumentedAPI) UntagResourceWithContext(ctx context.Context, input *glue.UntagResourceInput, opts ...request.Option) (*glue.UntagResourceOutput, error) {
+	start := a.clock.Now()
+	result, err := a.GlueAPI.UntagResourceWithContext(ctx, input, opts...)
+	a.observe(true, "UntagResource", start, err, "resource", aws.StringValue(input.ResourceArn))
+	return result, err
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// PutSchemaVersionMetadata attaches a key/value pair, such as a git commit or deployment
+// environment, to a schema version. Glue keeps earlier values put under the same key and
+// returns them in the OtherMetadataValueList of the key's MetadataInfo.
+func (c *GlueSchemaRegistryClient) PutSchemaVersionMetadata(schemaVersionID, key, value string) (*glue.PutSchemaVersionMetadataOutput, error) {
+	return c.PutSchemaVersionMetadataWithContext(context.Background(), schemaVersionID, key, value)
+}
+
+// PutSchemaVersionMetadataWithContext is like PutSchemaVersionMetadata but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) PutSchemaVersionMetadataWithContext(ctx context.Context, schemaVersionID, key, value string) (*glue.PutSchemaVersionMetadataOutput, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.PutSchemaVersionMetadataInput{
+		SchemaVersionId: aws.String(schemaVersionID),
+		MetadataKeyValue: &glue.MetadataKeyValuePair{
+			MetadataKey:   aws.String(key),
+			MetadataValue: aws.String(value),
+		},
+	}
+
+	result, err := api.PutSchemaVersionMetadataWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to put schema version metadata: %s (key %s)", schemaVersionID, key),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return result, nil
+}
+
+// QuerySchemaVersionMetadata returns all metadata attached to a schema version, keyed by
+// metadata key, following pagination
+func (c *GlueSchemaRegistryClient) QuerySchemaVersionMetadata(schemaVersionID string) (map[string]*glue.MetadataInfo, error) {
+	return c.QuerySchemaVersionMetadataWithContext(context.Background(), schemaVersionID)
+}
+
+// QuerySchemaVersionMetadataWithContext is like QuerySchemaVersionMetadata but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) QuerySchemaVersionMetadataWithContext(ctx context.Context, schemaVersionID string) (map[string]*glue.MetadataInfo, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.QuerySchemaVersionMetadataInput{
+		SchemaVersionId: aws.String(schemaVersionID),
+	}
+
+	metadata := make(map[string]*glue.MetadataInfo)
+	for {
+		result, err := api.QuerySchemaVersionMetadataWithContext(ctx, input)
+		if err != nil {
+			return nil, &SchemaRegistryException{
+				Message:   fmt.Sprintf("Failed to query schema version metadata: %s", schemaVersionID),
+				Err:       err,
+				ErrorCode: errorCode(err),
+			}
+		}
+		for key, info := range result.MetadataInfoMap {
+			metadata[key] = info
+		}
+		if aws.StringValue(result.NextToken) == "" {
+			return metadata, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+var (
+	// ErrNoVersionWithMetadata is matched by errors from GetSchemaVersionByMetadata when no
+	// version of the schema has the metadata value
+	ErrNoVersionWithMetadata = errors.New("no schema version has the metadata value")
+
+	// ErrAmbiguousMetadata is matched by errors from GetSchemaVersionByMetadata when more
+	// than one version of the schema has the metadata value
+	ErrAmbiguousMetadata = errors.New("more than one schema version has the metadata value")
+)
+
+// GetSchemaVersionByMetadata returns the version of a schema whose metadata has value
+// under key, for example the version tagged env=prod. Earlier values put under the key
+// are not considered. The metadata of the schema's versions is queried concurrently, by up
+// to WithBatchConcurrency calls at a time. If no version matches, the error matches
+// ErrNoVersionWithMetadata; if several do, it matches ErrAmbiguousMetadata and names them.
+func (c *GlueSchemaRegistryClient) GetSchemaVersionByMetadata(schemaName, key, value string) (*glue.GetSchemaVersionOutput, error) {
+	return c.GetSchemaVersionByMetadataWithContext(context.Background(), schemaName, key, value)
+}
+
+// GetSchemaVersionByMetadataWithContext is like GetSchemaVersionByMetadata but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetSchemaVersionByMetadataWithContext(ctx context.Context, schemaName, key, value string) (*glue.GetSchemaVersionOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	versions, err := c.ListSchemaVersionsWithContext(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := c.batchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(versions) {
+		workers = len(versions)
+	}
+
+	queryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	matched := make([]bool, len(versions))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var failOnce sync.Once
+	var failure error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				metadata, err := c.QuerySchemaVersionMetadataWithContext(queryCtx, aws.StringValue(versions[i].SchemaVersionId))
+				if err != nil {
+					// A version deleted since it was listed cannot match
+					if !IsNotFound(err) {
+						failOnce.Do(func() {
+							failure = err
+							cancel()
+						})
+					}
+					continue
+				}
+				info, ok := metadata[key]
+				matched[i] = ok && aws.StringValue(info.MetadataValue) == value
+			}
+		}()
+	}
+	for i := range versions {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if failure != nil {
+		return nil, failure
+	}
+
+	var matches []int64
+	for i, version := range versions {
+		if matched[i] {
+			matches = append(matches, aws.Int64Value(version.VersionNumber))
+		}
+	}
+	message := fmt.Sprintf("Failed to get schema version by metadata: %s (%s=%s)", schemaName, key, value)
+	switch len(matches) {
+	case 0:
+		return nil, &SchemaRegistryException{Message: message, Err: ErrNoVersionWithMetadata}
+	case 1:
+		return c.GetSchemaVersionWithContext(ctx, schemaName, matches[0])
+	default:
+		sort.Slice(matches, func(i, j int) bool { return matches[i] < matches[j] })
+		return nil, &SchemaRegistryException{
+			Message: message,
+			Err:     fmt.Errorf("%w: versions %v", ErrAmbiguousMetadata, matches),
+		}
+	}
+}
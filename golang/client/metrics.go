@@ -0,0 +1,43 @@
+package client
+
+import "time"
+
+// MetricsRecorder receives instrumentation events from the client and serializers.
+// Implementations must be safe for concurrent use. Embedding NopMetricsRecorder lets an
+// implementation handle only the events it is interested in.
+type MetricsRecorder interface {
+	// RecordSerde records one serialize or deserialize call. op is "serialize" or
+	// "deserialize", dataFormat is the schema's data format and errCategory is empty
+	// on success or names the kind of failure (for example "registry" or "decode").
+	RecordSerde(op, schemaName, dataFormat string, latency time.Duration, errCategory string)
+	// RecordGlueCall records one Glue API call made by the client. op is the Glue
+	// operation, such as "GetSchemaVersion", and err is nil on success. A failure is
+	// usually an awserr.Error, whose Code makes a suitable label.
+	RecordGlueCall(op string, latency time.Duration, err error)
+	// RecordCacheHit records one lookup in a cache of schema versions: the client's
+	// version cache, or the Avro and Protobuf serializers' caches of compiled schemas.
+	RecordCacheHit(schemaName string, hit bool)
+}
+
+// NopMetricsRecorder is a MetricsRecorder that discards every event
+type NopMetricsRecorder struct{}
+
+// RecordSerde implements MetricsRecorder
+func (NopMetricsRecorder) RecordSerde(op, schemaName, dataFormat string, latency time.Duration, errCategory string) {
+}
+
+// RecordGlueCall implements MetricsRecorder
+func (NopMetricsRecorder) RecordGlueCall(op string, latency time.Duration, err error) {}
+
+// RecordCacheHit implements MetricsRecorder
+func (NopMetricsRecorder) RecordCacheHit(schemaName string, hit bool) {}
+
+// WithMetrics records the latency and outcome of every Glue call made by the client's
+// methods, and, with WithSchemaVersionCache, every version cache lookup. Calls made
+// directly through GlueClient are not recorded. Pass the same recorder to
+// serializer.WithMetrics to record serialize and deserialize calls.
+func WithMetrics(m MetricsRecorder) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.metrics = m
+	}
+}
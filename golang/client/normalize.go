@@ -0,0 +1,34 @@
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/aws-glue-schema-registry/golang/schemadiff"
+)
+
+// WithNormalizedDefinitions normalizes Avro and JSON Schema definitions passed to
+// CreateSchema, RegisterSchemaVersion and GetSchemaByDefinition with schemadiff.Normalize,
+// which removes insignificant whitespace and sorts object keys. Glue compares definitions
+// as text, so without normalization a definition that differs from a registered one only
+// in formatting is registered as a new version and is not found by GetSchemaByDefinition.
+//
+// Definitions registered before this option was used are found and reused only if they
+// were already normalized. Protobuf definitions, and anything else that is not JSON, are
+// passed to Glue unchanged.
+func WithNormalizedDefinitions() Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.normalizeDefinitions = true
+	}
+}
+
+// definition returns schemaDefinition as it should be sent to Glue
+func (c *GlueSchemaRegistryClient) definition(schemaDefinition string) string {
+	if !c.normalizeDefinitions || !json.Valid([]byte(schemaDefinition)) {
+		return schemaDefinition
+	}
+	normalized, err := schemadiff.Normalize(schemaDefinition)
+	if err != nil {
+		return schemaDefinition
+	}
+	return normalized
+}
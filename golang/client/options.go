@@ -0,0 +1,17 @@
+package client
+
+// Option configures a GlueSchemaRegistryClient
+type Option func(*GlueSchemaRegistryClient)
+
+// SchemaRegisteredFunc is called after a schema version has been registered
+type SchemaRegisteredFunc func(schemaName, versionID string, versionNumber int64)
+
+// WithOnSchemaRegistered sets a callback invoked after every successful CreateSchema and
+// RegisterSchemaVersion with the schema name and the resulting version. The callback runs
+// synchronously on the calling goroutine before the method returns, so it should hand off
+// any slow work (notifications, cache invalidation across services) rather than block.
+func WithOnSchemaRegistered(fn SchemaRegisteredFunc) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.onSchemaRegistered = fn
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// WithMaxVersions keeps at most n versions of a schema by deleting the oldest versions
+// after every successful RegisterSchemaVersion. Pruning is best-effort: failures are
+// logged and never fail the registration. Glue only deletes the first version of a schema
+// together with the schema, so version 1 is never pruned and counts towards n.
+// A value of zero or less disables pruning.
+func WithMaxVersions(n int) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.maxVersions = n
+	}
+}
+
+// pruneVersions deletes the oldest versions of schemaName beyond c.maxVersions
+func (c *GlueSchemaRegistryClient) pruneVersions(ctx context.Context, schemaName string) {
+	versions, err := c.ListSchemaVersionsWithContext(ctx, schemaName)
+	if err != nil {
+		c.logf("glue schema registry: failed to prune versions of %s: %v", schemaName, err)
+		return
+	}
+
+	var numbers []int64
+	for _, v := range versions {
+		if aws.StringValue(v.Status) != glue.SchemaVersionStatusDeleting {
+			numbers = append(numbers, aws.Int64Value(v.VersionNumber))
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	excess := len(numbers) - c.maxVersions
+	if excess <= 0 {
+		return
+	}
+	if numbers[0] == 1 {
+		numbers = numbers[1:]
+	}
+	if excess > len(numbers) {
+		excess = len(numbers)
+	}
+
+	for _, versions := range versionRanges(numbers[:excess]) {
+		result, err := c.DeleteSchemaVersionsWithContext(ctx, schemaName, versions)
+		if err != nil {
+			c.logf("glue schema registry: failed to prune versions of %s: %v", schemaName, err)
+			continue
+		}
+		for _, item := range result.SchemaVersionErrors {
+			var message string
+			if item.ErrorDetails != nil {
+				message = aws.StringValue(item.ErrorDetails.ErrorMessage)
+			}
+			c.logf("glue schema registry: failed to prune version %d of %s: %s",
+				aws.Int64Value(item.VersionNumber), schemaName, message)
+		}
+	}
+}
+
+// versionRanges groups ascending version numbers into the single-version and range
+// expressions accepted by DeleteSchemaVersions, for example [2 3 4 7] -> ["2-4" "7"]
+func versionRanges(numbers []int64) []string {
+	var ranges []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, fmt.Sprintf("%d", numbers[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return ranges
+}
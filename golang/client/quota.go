@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// ErrRegistryQuotaExceeded is matched by errors from CreateSchema and RegisterSchemaVersion
+// when Glue rejects the call because a registry limit, such as the number of schemas per
+// registry or versions per schema, has been reached. The AWS error remains available
+// through errors.As.
+var ErrRegistryQuotaExceeded = errors.New("registry quota exceeded")
+
+// QuotaExceededFunc is called when CreateSchema or RegisterSchemaVersion hits a registry
+// limit. It can free capacity, for example by pruning old versions, and return true to
+// retry the call once.
+type QuotaExceededFunc func(ctx context.Context, schemaName string, err error) (retry bool)
+
+// WithOnQuotaExceeded sets a hook invoked synchronously when a registry limit is hit.
+// The hook may use the client, including for deletes.
+func WithOnQuotaExceeded(fn QuotaExceededFunc) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.onQuotaExceeded = fn
+	}
+}
+
+func isQuotaExceeded(err error) bool {
+	return errorCode(err) == glue.ErrCodeResourceNumberLimitExceededException
+}
+
+// retryAfterQuotaExceeded reports whether a call that failed with err should be retried
+func (c *GlueSchemaRegistryClient) retryAfterQuotaExceeded(ctx context.Context, schemaName string, err error) bool {
+	return c.onQuotaExceeded != nil && isQuotaExceeded(err) && c.onQuotaExceeded(ctx, schemaName, err)
+}
+
+// wrapQuotaExceeded marks a registry limit error so that it matches ErrRegistryQuotaExceeded
+func wrapQuotaExceeded(e *SchemaRegistryException) error {
+	if isQuotaExceeded(e.Err) {
+		e.Err = fmt.Errorf("%w: %w", ErrRegistryQuotaExceeded, e.Err)
+	}
+	return e
+}
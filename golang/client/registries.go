@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// CreateRegistry creates the client's registry, for bootstrapping environments where it
+// does not exist yet
+func (c *GlueSchemaRegistryClient) CreateRegistry(description string) (*glue.CreateRegistryOutput, error) {
+	return c.CreateRegistryWithContext(context.Background(), description)
+}
+
+// CreateRegistryWithContext is like CreateRegistry but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) CreateRegistryWithContext(ctx context.Context, description string) (*glue.CreateRegistryOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.CreateRegistryInput{
+		RegistryName: aws.String(registryName),
+	}
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+
+	result, err := api.CreateRegistryWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to create registry: %s", registryName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return result, nil
+}
+
+// GetRegistry gets the client's registry
+func (c *GlueSchemaRegistryClient) GetRegistry() (*glue.GetRegistryOutput, error) {
+	return c.GetRegistryWithContext(context.Background())
+}
+
+// GetRegistryWithContext is like GetRegistry but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetRegistryWithContext(ctx context.Context) (*glue.GetRegistryOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.GetRegistryInput{
+		RegistryId: &glue.RegistryId{
+			RegistryName: aws.String(registryName),
+		},
+	}
+
+	result, err := api.GetRegistryWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to get registry: %s", registryName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateRegistry replaces the description of the client's registry. A registry that does
+// not exist is reported with an error matching ErrRegistryNotFound.
+func (c *GlueSchemaRegistryClient) UpdateRegistry(description string) (*glue.UpdateRegistryOutput, error) {
+	return c.UpdateRegistryWithContext(context.Background(), description)
+}
+
+// UpdateRegistryWithContext is like UpdateRegistry but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) UpdateRegistryWithContext(ctx context.Context, description string) (*glue.UpdateRegistryOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.UpdateRegistryInput{
+		RegistryId: &glue.RegistryId{
+			RegistryName: aws.String(registryName),
+		},
+		Description: aws.String(description),
+	}
+
+	result, err := api.UpdateRegistryWithContext(ctx, input)
+	if err != nil {
+		if isEntityNotFound(err) {
+			return nil, &SchemaRegistryException{
+				Message:   fmt.Sprintf("Registry not found: %s", registryName),
+				Err:       fmt.Errorf("%w: %w", ErrRegistryNotFound, err),
+				ErrorCode: errorCode(err),
+			}
+		}
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to update registry: %s", registryName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteRegistry deletes the client's registry together with all of its schemas. Deletion
+// is asynchronous: the returned status is DELETING until Glue finishes, and GetRegistry
+// reports the same status in the meantime.
+func (c *GlueSchemaRegistryClient) DeleteRegistry() (*glue.DeleteRegistryOutput, error) {
+	return c.DeleteRegistryWithContext(context.Background())
+}
+
+// DeleteRegistryWithContext is like DeleteRegistry but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) DeleteRegistryWithContext(ctx context.Context) (*glue.DeleteRegistryOutput, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.DeleteRegistryInput{
+		RegistryId: &glue.RegistryId{
+			RegistryName: aws.String(registryName),
+		},
+	}
+
+	result, err := api.DeleteRegistryWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to delete registry: %s", registryName),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	c.evictRegistry(registryName)
+
+	return result, nil
+}
@@ -0,0 +1,23 @@
+package client
+
+import "github.com/aws/aws-sdk-go/service/glue"
+
+// Registry is the set of schema registry operations used by the serializers and
+// higher-level helpers. GlueSchemaRegistryClient implements it; other implementations
+// can be substituted for testing or to front a different backend. The serializers call a
+// Registry from whichever goroutines they are used on, so implementations shared between
+// goroutines must be safe for concurrent use.
+type Registry interface {
+	// CreateSchema creates a new schema in the registry
+	CreateSchema(schemaName string, dataFormat DataFormat, schemaDefinition string, compatibility Compatibility) (*glue.CreateSchemaOutput, error)
+	// GetSchema gets a schema by name
+	GetSchema(schemaName string) (*glue.GetSchemaOutput, error)
+	// GetDataFormat returns the data format of a schema
+	GetDataFormat(schemaName string) (string, error)
+	// GetSchemaVersion gets a specific version of a schema
+	GetSchemaVersion(schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error)
+	// RegisterSchemaVersion registers a new version of a schema
+	RegisterSchemaVersion(schemaName, schemaDefinition string) (*glue.RegisterSchemaVersionOutput, error)
+}
+
+var _ Registry = (*GlueSchemaRegistryClient)(nil)
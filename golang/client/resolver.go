@@ -0,0 +1,89 @@
+package client
+
+import "context"
+
+// RegistryResolver selects the registry a call operates on, for example from a tenant or
+// environment carried in the request context
+type RegistryResolver interface {
+	ResolveRegistry(ctx context.Context) (string, error)
+}
+
+// RegistryResolverFunc adapts an ordinary function to a RegistryResolver
+type RegistryResolverFunc func(ctx context.Context) (string, error)
+
+// ResolveRegistry calls f(ctx)
+func (f RegistryResolverFunc) ResolveRegistry(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithRegistryResolver resolves the registry name for every call from the call's context.
+// Calls without a context use context.Background(). When the resolver returns an empty
+// name the registry name passed to the constructor is used.
+func WithRegistryResolver(r RegistryResolver) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.registryResolver = r
+	}
+}
+
+// resolvedRegistryKey marks a context whose registry has already been resolved, so that
+// methods built on other methods consult the resolver once per call
+type resolvedRegistryKey struct{}
+
+func withRegistry(ctx context.Context, registryName string) context.Context {
+	return context.WithValue(ctx, resolvedRegistryKey{}, registryName)
+}
+
+// resolveRegistry returns the registry name to use for a call made with ctx
+func (c *GlueSchemaRegistryClient) resolveRegistry(ctx context.Context) (string, error) {
+	if registryName, ok := ctx.Value(resolvedRegistryKey{}).(string); ok {
+		return registryName, nil
+	}
+	if c.registryResolver == nil {
+		return c.registryName, nil
+	}
+
+	registryName, err := c.registryResolver.ResolveRegistry(ctx)
+	if err != nil {
+		return "", &SchemaRegistryException{
+			Message: "Failed to resolve registry",
+			Err:     err,
+		}
+	}
+	if registryName == "" {
+		return c.registryName, nil
+	}
+	return registryName, nil
+}
+
+// ForRegistry returns a client for another registry that shares c's Glue client and AWS
+// session, so that one session can serve schemas spread across several registries. The
+// returned client has c's options, except that it always uses registryName and ignores
+// WithRegistryResolver. Its caches are its own. With WithLazySession, the session is
+// created once, by whichever of the clients is used first.
+func (c *GlueSchemaRegistryClient) ForRegistry(registryName string) *GlueSchemaRegistryClient {
+	view := &GlueSchemaRegistryClient{
+		registryName:       registryName,
+		connect:            c.connected,
+		lazySession:        c.lazySession,
+		onSchemaRegistered: c.onSchemaRegistered,
+		onQuotaExceeded:    c.onQuotaExceeded,
+		maxVersions:        c.maxVersions,
+		batchConcurrency:   c.batchConcurrency,
+		retry:              c.retry,
+		logger:             c.logger,
+		metrics:            c.metrics,
+		logf:               c.logf,
+		dataFormats:        make(map[string]string),
+
+		normalizeDefinitions: c.normalizeDefinitions,
+		sts:                  c.sts,
+		clock:                c.clock,
+		cacheVersions:        c.cacheVersions,
+		cacheSize:            c.cacheSize,
+		latestTTL:            c.latestTTL,
+	}
+	if c.cacheVersions {
+		view.versions = newVersionCache(c.cacheSize)
+	}
+	return view
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
+)
+
+// WithMaxRetries retries Glue calls that fail with a throttling error or a transient server
+// error, such as ThrottlingException or an HTTP 5xx response, up to n times with
+// exponential backoff and jitter. Other errors, for example EntityNotFoundException, are
+// returned without retrying. Without this option the AWS SDK default of 3 retries applies,
+// and n = 0 disables retries.
+//
+// The retry options configure the AWS SDK retryer of the session built by
+// NewGlueSchemaRegistryClient and NewGlueSchemaRegistryClientWithConfig, replacing any
+// retryer or MaxRetries in ClientConfig.AWSConfig. On NewGlueSchemaRegistryClientWithAPI,
+// whose Glue client carries its own retry configuration, they only configure the backoff
+// of fan-out methods such as ListSchemasWithLatestVersion.
+func WithMaxRetries(n int) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.retryer().NumMaxRetries = n
+	}
+}
+
+// WithRetryBaseDelay sets the minimum delay before the first retry of a Glue call. The
+// delay doubles with every further attempt and is randomized by up to the same amount
+// again. The AWS SDK defaults are 30ms, or 500ms after throttling.
+func WithRetryBaseDelay(d time.Duration) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		r := c.retryer()
+		r.MinRetryDelay = d
+		r.MinThrottleDelay = d
+	}
+}
+
+// retryer returns the retry configuration set by the options, creating it with the AWS
+// SDK defaults on first use
+func (c *GlueSchemaRegistryClient) retryer() *awsclient.DefaultRetryer {
+	if c.retry == nil {
+		c.retry = &awsclient.DefaultRetryer{NumMaxRetries: awsclient.DefaultRetryerMaxNumRetries}
+	}
+	return c.retry
+}
+
+// applyRetryer returns a copy of awsConfig that uses the retry configuration set by the
+// options, or awsConfig itself if none was set
+func (c *GlueSchemaRegistryClient) applyRetryer(awsConfig *aws.Config) *aws.Config {
+	if c.retry == nil {
+		return awsConfig
+	}
+	if awsConfig == nil {
+		awsConfig = aws.NewConfig()
+	} else {
+		awsConfig = awsConfig.Copy()
+	}
+	awsConfig.Retryer = *c.retry
+	return awsConfig
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// SchemaSummary describes a schema and its latest version
+type SchemaSummary struct {
+	SchemaName          string
+	SchemaArn           string
+	DataFormat          string
+	LatestSchemaVersion int64
+}
+
+// ListSchemasWithLatestVersion lists every schema in the registry with its data format and
+// latest version number, in the order ListSchemas returns them. The schemas are looked up
+// concurrently, by up to WithBatchConcurrency calls at a time. A lookup that is throttled
+// is retried with exponential backoff, as configured by WithMaxRetries and
+// WithRetryBaseDelay, before the listing fails. Schemas deleted while the listing runs are
+// left out.
+func (c *GlueSchemaRegistryClient) ListSchemasWithLatestVersion() ([]SchemaSummary, error) {
+	return c.ListSchemasWithLatestVersionWithContext(context.Background())
+}
+
+// ListSchemasWithLatestVersionWithContext is like ListSchemasWithLatestVersion but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) ListSchemasWithLatestVersionWithContext(ctx context.Context) ([]SchemaSummary, error) {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	schemas, err := c.ListSchemasWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := c.batchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(schemas) {
+		workers = len(schemas)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	outputs := make([]*glue.GetSchemaOutput, len(schemas))
+	errs := make([]error, len(schemas))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var failOnce sync.Once
+	var failure error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				outputs[i], errs[i] = c.getSchemaWithBackoff(ctx, aws.StringValue(schemas[i].SchemaName))
+				if errs[i] != nil && !IsNotFound(errs[i]) {
+					// Stop the remaining lookups; the first failure is the one reported
+					failOnce.Do(func() {
+						failure = errs[i]
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	for i := range schemas {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if failure != nil {
+		return nil, failure
+	}
+
+	summaries := make([]SchemaSummary, 0, len(schemas))
+	for i, output := range outputs {
+		if errs[i] != nil {
+			continue
+		}
+		summaries = append(summaries, SchemaSummary{
+			SchemaName:          aws.StringValue(output.SchemaName),
+			SchemaArn:           aws.StringValue(output.SchemaArn),
+			DataFormat:          aws.StringValue(output.DataFormat),
+			LatestSchemaVersion: aws.Int64Value(output.LatestSchemaVersion),
+		})
+	}
+	return summaries, nil
+}
+
+// getSchemaWithBackoff calls GetSchema, retrying throttled calls as withBackoff does
+func (c *GlueSchemaRegistryClient) getSchemaWithBackoff(ctx context.Context, schemaName string) (*glue.GetSchemaOutput, error) {
+	var output *glue.GetSchemaOutput
+	err := c.withBackoff(ctx, func() error {
+		var err error
+		output, err = c.GetSchemaWithContext(ctx, schemaName)
+		return err
+	})
+	return output, err
+}
+
+// withBackoff calls call, retrying throttled calls with exponential backoff and jitter.
+// No wait is longer than the retryer's maximum throttle delay. Once ctx is done, the last
+// error is returned.
+func (c *GlueSchemaRegistryClient) withBackoff(ctx context.Context, call func() error) error {
+	maxRetries, delay := awsclient.DefaultRetryerMaxNumRetries, awsclient.DefaultRetryerMinThrottleDelay
+	maxDelay := awsclient.DefaultRetryerMaxThrottleDelay
+	if c.retry != nil {
+		maxRetries, delay = c.retry.NumMaxRetries, c.retry.MinThrottleDelay
+		if c.retry.MaxThrottleDelay > 0 {
+			maxDelay = c.retry.MaxThrottleDelay
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil || !IsThrottling(err) || attempt >= maxRetries {
+			return err
+		}
+		// Double the delay one attempt at a time so that it stops at maxDelay rather than
+		// overflowing
+		backoff := delay
+		for i := 0; i < attempt && backoff < maxDelay; i++ {
+			backoff <<= 1
+		}
+		if backoff > 0 {
+			backoff += time.Duration(rand.Int63n(int64(backoff)))
+		}
+		if backoff > maxDelay {
+			backoff = maxDelay
+		}
+		fired, stop := c.clock.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			stop()
+			return err
+		case <-fired:
+		}
+	}
+}
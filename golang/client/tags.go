@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// RegistryARN returns the ARN of the registry named registryName in the given region and
+// AWS account, for use with the tagging methods. The partition, such as aws-cn, is derived
+// from the region. GetRegistry also returns the ARN, at the cost of a Glue call.
+func RegistryARN(region, accountID, registryName string) string {
+	return fmt.Sprintf("arn:%s:glue:%s:%s:registry/%s", partition(region), region, accountID, registryName)
+}
+
+// SchemaARN returns the ARN of the schema named schemaName in a registry, as RegistryARN
+// does for the registry
+func SchemaARN(region, accountID, registryName, schemaName string) string {
+	return fmt.Sprintf("arn:%s:glue:%s:%s:schema/%s/%s", partition(region), region, accountID, registryName, schemaName)
+}
+
+// partition returns the ID of the AWS partition of region, aws for unknown regions
+func partition(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.ID()
+	}
+	return endpoints.AwsPartitionID
+}
+
+// GetTags returns the tags of a Glue resource, such as a registry or schema
+func (c *GlueSchemaRegistryClient) GetTags(resourceArn string) (map[string]string, error) {
+	return c.GetTagsWithContext(context.Background(), resourceArn)
+}
+
+// GetTagsWithContext is like GetTags but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) GetTagsWithContext(ctx context.Context, resourceArn string) (map[string]string, error) {
+	api, err := c.api()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &glue.GetTagsInput{
+		ResourceArn: aws.String(resourceArn),
+	}
+
+	result, err := api.GetTagsWithContext(ctx, input)
+	if err != nil {
+		return nil, &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to get tags: %s", resourceArn),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return aws.StringValueMap(result.Tags), nil
+}
+
+// TagResource adds tags to a Glue resource, replacing the values of keys it already has
+func (c *GlueSchemaRegistryClient) TagResource(resourceArn string, tags map[string]string) error {
+	return c.TagResourceWithContext(context.Background(), resourceArn, tags)
+}
+
+// TagResourceWithContext is like TagResource but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) TagResourceWithContext(ctx context.Context, resourceArn string, tags map[string]string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	input := &glue.TagResourceInput{
+		ResourceArn: aws.String(resourceArn),
+		TagsToAdd:   aws.StringMap(tags),
+	}
+
+	if _, err := api.TagResourceWithContext(ctx, input); err != nil {
+		return &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to tag resource: %s", resourceArn),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return nil
+}
+
+// UntagResource removes tags from a Glue resource. Keys the resource does not have are ignored.
+func (c *GlueSchemaRegistryClient) UntagResource(resourceArn string, keys []string) error {
+	return c.UntagResourceWithContext(context.Background(), resourceArn, keys)
+}
+
+// UntagResourceWithContext is like UntagResource but honours the context's deadline and cancellation
+func (c *GlueSchemaRegistryClient) UntagResourceWithContext(ctx context.Context, resourceArn string, keys []string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	input := &glue.UntagResourceInput{
+		ResourceArn:  aws.String(resourceArn),
+		TagsToRemove: aws.StringSlice(keys),
+	}
+
+	if _, err := api.UntagResourceWithContext(ctx, input); err != nil {
+		return &SchemaRegistryException{
+			Message:   fmt.Sprintf("Failed to untag resource: %s", resourceArn),
+			Err:       err,
+			ErrorCode: errorCode(err),
+		}
+	}
+
+	return nil
+}
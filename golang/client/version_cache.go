@@ -0,0 +1,283 @@
+package client
+
+import (
+	"container/list"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// versionKey identifies one version of a schema in a registry. A version of latestVersion
+// stands for whichever version is the latest, and one of schemaEntry for the GetSchema
+// output of the schema.
+type versionKey struct {
+	registryName string
+	schemaName   string
+	version      int64
+}
+
+// latestVersion is the version of the cache key of a schema's latest version; Glue numbers
+// versions from 1
+const latestVersion = 0
+
+// schemaEntry is the version of the cache key of a schema's GetSchema output
+const schemaEntry = -1
+
+// CacheStats counts the lookups in the version cache. Evictions counts entries dropped to
+// stay within WithSchemaVersionCacheSize and latest versions dropped once their
+// WithLatestVersionCacheTTL expired, but not entries dropped by InvalidateSchemaCache or
+// DeleteSchema.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// WithSchemaVersionCache enables or disables an in-memory cache of GetSchemaVersion
+// results keyed by registry, schema name and version number. Glue schema versions are
+// immutable once registered, so entries are only dropped by InvalidateSchemaCache,
+// DeleteSchema and version pruning, and to stay within WithSchemaVersionCacheSize.
+// Cached outputs are shared between callers and must not be modified. The cache is
+// disabled by default.
+func WithSchemaVersionCache(enabled bool) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.cacheVersions = enabled
+	}
+}
+
+// WithSchemaVersionCacheSize bounds the version cache to n entries, dropping the least
+// recently used entry to make room for a new one. A value of zero or less, the default,
+// leaves the cache unbounded. It has no effect without WithSchemaVersionCache(true).
+func WithSchemaVersionCacheSize(n int) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.cacheSize = n
+	}
+}
+
+// WithLatestVersionCacheTTL caches the results of GetLatestSchemaDefinition and GetSchema,
+// which the serializers use to find the latest version, in the version cache for ttl.
+// Unlike numbered versions, the latest version changes whenever a version is registered,
+// so a producer using the cache picks up versions registered by other clients up to ttl
+// late, and the same goes for other changes to a schema such as its compatibility mode;
+// changes made through this client replace the cached entries at once. A ttl of zero or
+// less, the default, looks the latest version up on every call. It has no effect without
+// WithSchemaVersionCache(true).
+func WithLatestVersionCacheTTL(ttl time.Duration) Option {
+	return func(c *GlueSchemaRegistryClient) {
+		c.latestTTL = ttl
+	}
+}
+
+// SchemaCacheStats returns the hits, misses and evictions of the version cache so far. It
+// is zero without WithSchemaVersionCache(true).
+func (c *GlueSchemaRegistryClient) SchemaCacheStats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.versions == nil {
+		return CacheStats{}
+	}
+	return c.versions.stats
+}
+
+// versionCache is a least recently used cache of schema versions. The client's mu guards it.
+type versionCache struct {
+	maxEntries int
+	entries    map[versionKey]*list.Element
+	// order holds the *cacheEntry values, most recently used first
+	order *list.List
+	stats CacheStats
+}
+
+type cacheEntry struct {
+	key     versionKey
+	version *glue.GetSchemaVersionOutput
+	// schema is set instead of version for keys of schemaEntry
+	schema *glue.GetSchemaOutput
+	// expires is when a latest version or schema must be looked up again; zero for
+	// numbered versions
+	expires time.Time
+}
+
+func newVersionCache(maxEntries int) *versionCache {
+	return &versionCache{maxEntries: maxEntries, entries: make(map[versionKey]*list.Element), order: list.New()}
+}
+
+// get returns the entry cached under key, dropping it instead if it expired before now
+func (vc *versionCache) get(key versionKey, now time.Time) (*cacheEntry, bool) {
+	element, ok := vc.entries[key]
+	if ok {
+		if entry := element.Value.(*cacheEntry); !entry.expires.IsZero() && !now.Before(entry.expires) {
+			vc.remove(element)
+			vc.stats.Evictions++
+			ok = false
+		}
+	}
+	if !ok {
+		vc.stats.Misses++
+		return nil, false
+	}
+	vc.stats.Hits++
+	vc.order.MoveToFront(element)
+	return element.Value.(*cacheEntry), true
+}
+
+// put caches entry under its key, dropping the least recently used entry if the cache is full
+func (vc *versionCache) put(entry *cacheEntry) {
+	if element, ok := vc.entries[entry.key]; ok {
+		element.Value = entry
+		vc.order.MoveToFront(element)
+		return
+	}
+	vc.entries[entry.key] = vc.order.PushFront(entry)
+	if vc.maxEntries > 0 && vc.order.Len() > vc.maxEntries {
+		vc.remove(vc.order.Back())
+		vc.stats.Evictions++
+	}
+}
+
+// removeIf drops every entry whose key matches
+func (vc *versionCache) removeIf(match func(versionKey) bool) {
+	for key, element := range vc.entries {
+		if match(key) {
+			vc.remove(element)
+		}
+	}
+}
+
+func (vc *versionCache) remove(element *list.Element) {
+	vc.order.Remove(element)
+	delete(vc.entries, element.Value.(*cacheEntry).key)
+}
+
+// InvalidateSchemaCache drops everything cached for schemaName in any registry: its data
+// format and, with WithSchemaVersionCache, its schema versions.
+func (c *GlueSchemaRegistryClient) InvalidateSchemaCache(schemaName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.dataFormats {
+		if _, name, _ := strings.Cut(key, "/"); name == schemaName {
+			delete(c.dataFormats, key)
+		}
+	}
+	if c.versions != nil {
+		c.versions.removeIf(func(key versionKey) bool { return key.schemaName == schemaName })
+	}
+}
+
+// cachedVersion looks key up in the version cache, recording the lookup if the cache is
+// enabled. Latest versions are only looked up with WithLatestVersionCacheTTL.
+func (c *GlueSchemaRegistryClient) cachedVersion(key versionKey) (*glue.GetSchemaVersionOutput, bool) {
+	if key.version == latestVersion && c.latestTTL <= 0 {
+		return nil, false
+	}
+	entry, ok := c.cachedEntry(key)
+	if !ok {
+		return nil, false
+	}
+	return entry.version, true
+}
+
+// cachedSchema looks the GetSchema output of a schema up in the version cache, which only
+// holds it with WithLatestVersionCacheTTL
+func (c *GlueSchemaRegistryClient) cachedSchema(registryName, schemaName string) (*glue.GetSchemaOutput, bool) {
+	if c.latestTTL <= 0 {
+		return nil, false
+	}
+	entry, ok := c.cachedEntry(versionKey{registryName: registryName, schemaName: schemaName, version: schemaEntry})
+	if !ok {
+		return nil, false
+	}
+	return entry.schema, true
+}
+
+// cachedEntry looks key up in the version cache, recording the lookup if the cache is enabled
+func (c *GlueSchemaRegistryClient) cachedEntry(key versionKey) (*cacheEntry, bool) {
+	c.mu.Lock()
+	enabled := c.versions != nil
+	var entry *cacheEntry
+	var ok bool
+	if enabled {
+		entry, ok = c.versions.get(key, c.clock.Now())
+	}
+	c.mu.Unlock()
+	if enabled && c.metrics != nil {
+		c.metrics.RecordCacheHit(key.schemaName, ok)
+	}
+	return entry, ok
+}
+
+// cacheVersion caches version if it is AVAILABLE; the status of other versions can change.
+// A latest version is cached for the WithLatestVersionCacheTTL, if one is set.
+func (c *GlueSchemaRegistryClient) cacheVersion(key versionKey, version *glue.GetSchemaVersionOutput) {
+	if status := aws.StringValue(version.Status); status != "" && status != glue.SchemaVersionStatusAvailable {
+		return
+	}
+	var expires time.Time
+	if key.version == latestVersion {
+		if c.latestTTL <= 0 {
+			return
+		}
+		expires = c.clock.Now().Add(c.latestTTL)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.versions != nil {
+		c.versions.put(&cacheEntry{key: key, version: version, expires: expires})
+	}
+}
+
+// cacheSchema caches the GetSchema output of a schema for the WithLatestVersionCacheTTL,
+// if one is set
+func (c *GlueSchemaRegistryClient) cacheSchema(registryName, schemaName string, schema *glue.GetSchemaOutput) {
+	if c.latestTTL <= 0 {
+		return
+	}
+	expires := c.clock.Now().Add(c.latestTTL)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.versions != nil {
+		key := versionKey{registryName: registryName, schemaName: schemaName, version: schemaEntry}
+		c.versions.put(&cacheEntry{key: key, schema: schema, expires: expires})
+	}
+}
+
+// evictLatest drops the cached latest version and GetSchema output of one schema in one
+// registry, which change when the schema does
+func (c *GlueSchemaRegistryClient) evictLatest(registryName, schemaName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.versions != nil {
+		for _, version := range []int64{latestVersion, schemaEntry} {
+			if element, ok := c.versions.entries[versionKey{registryName: registryName, schemaName: schemaName, version: version}]; ok {
+				c.versions.remove(element)
+			}
+		}
+	}
+}
+
+// evictVersions drops the cached versions of one schema in one registry
+func (c *GlueSchemaRegistryClient) evictVersions(registryName, schemaName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.versions != nil {
+		c.versions.removeIf(func(key versionKey) bool {
+			return key.registryName == registryName && key.schemaName == schemaName
+		})
+	}
+}
+
+// evictRegistry drops everything cached for the schemas of one registry
+func (c *GlueSchemaRegistryClient) evictRegistry(registryName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.dataFormats {
+		if registry, _, _ := strings.Cut(key, "/"); registry == registryName {
+			delete(c.dataFormats, key)
+		}
+	}
+	if c.versions != nil {
+		c.versions.removeIf(func(key versionKey) bool { return key.registryName == registryName })
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// ErrSchemaVersionFailed is matched by errors from WaitForSchemaVersionAvailable when Glue
+// rejected the version, for example because it failed the compatibility check
+var ErrSchemaVersionFailed = errors.New("schema version failed")
+
+// waitPollDelay is the delay before the second status check of WaitForSchemaVersionAvailable;
+// it doubles after every further check up to waitMaxPollDelay
+var (
+	waitPollDelay    = 100 * time.Millisecond
+	waitMaxPollDelay = 5 * time.Second
+)
+
+// WaitForSchemaVersionAvailable polls the status of a schema version, with exponential
+// backoff, until it is AVAILABLE. It fails with an error matching ErrSchemaVersionFailed if
+// the status becomes FAILURE, and with one matching context.DeadlineExceeded if the version
+// is still pending after timeout. A timeout of zero or less waits until the version
+// settles. Versions returned by RegisterSchemaVersion are often PENDING at first and
+// cannot be used to serialize until they are AVAILABLE.
+func (c *GlueSchemaRegistryClient) WaitForSchemaVersionAvailable(schemaName string, versionNumber int64, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return c.WaitForSchemaVersionAvailableWithContext(ctx, schemaName, versionNumber)
+}
+
+// WaitForSchemaVersionAvailableWithContext is like WaitForSchemaVersionAvailable but waits
+// until the context is done instead of for a timeout. Once the context is cancelled, the
+// poll stops and ctx.Err() is returned as is; a deadline fails as the timeout does.
+func (c *GlueSchemaRegistryClient) WaitForSchemaVersionAvailableWithContext(ctx context.Context, schemaName string, versionNumber int64) error {
+	registryName, err := c.resolveRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = withRegistry(ctx, registryName)
+
+	delay := waitPollDelay
+	for {
+		version, err := c.GetSchemaVersionWithContext(ctx, schemaName, versionNumber)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The SDK reports a request abandoned with the context as its own error
+				return waitAbandoned(ctx, schemaName, versionNumber, "unknown")
+			}
+			return err
+		}
+
+		status := aws.StringValue(version.Status)
+		switch status {
+		case glue.SchemaVersionStatusAvailable:
+			return nil
+		case glue.SchemaVersionStatusFailure:
+			return &SchemaRegistryException{
+				Message: fmt.Sprintf("Schema version failed: %s (version %d)", schemaName, versionNumber),
+				Err:     ErrSchemaVersionFailed,
+			}
+		case glue.SchemaVersionStatusDeleting:
+			return &SchemaRegistryException{
+				Message: fmt.Sprintf("Schema version is being deleted: %s (version %d)", schemaName, versionNumber),
+			}
+		}
+
+		fired, stop := c.clock.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			stop()
+			return waitAbandoned(ctx, schemaName, versionNumber, status)
+		case <-fired:
+		}
+		if delay *= 2; delay > waitMaxPollDelay {
+			delay = waitMaxPollDelay
+		}
+	}
+}
+
+// waitAbandoned returns the error of a wait whose context is done while the version has
+// status: ctx.Err() if it was cancelled, otherwise a timeout
+func waitAbandoned(ctx context.Context, schemaName string, versionNumber int64, status string) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return ctx.Err()
+	}
+	return &SchemaRegistryException{
+		Message: fmt.Sprintf("Timed out waiting for schema version: %s (version %d)", schemaName, versionNumber),
+		Err:     fmt.Errorf("status is still %s: %w", status, ctx.Err()),
+	}
+}
@@ -0,0 +1,296 @@
+// Command gsr manages the schemas of an AWS Glue Schema Registry from the command line.
+//
+// Usage:
+//
+//	gsr [-region region] [-registry name] [-o text|json] command [arguments]
+//
+// The commands are:
+//
+//	list                                            list schemas with their latest version
+//	get schema [version]                            show a schema, or the definition of a version
+//	create schema format file [compatibility]       create a schema from a definition file
+//	register-version schema file                    register a definition as a new version
+//	set-compatibility schema mode                   change the compatibility mode of a schema
+//	delete schema [versions]                        delete a schema, or versions such as 5 or 5-8
+//
+// A file of "-" is read from standard input. The region and registry default to the
+// AWS_REGION and GLUE_REGISTRY_NAME environment variables; without a region, the AWS SDK
+// resolves one from the shared configuration, but a registry must be given. With -o json,
+// each command writes its result as JSON for scripting.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// newClient creates the client the commands use; it is a variable so tests can use a fake
+var newClient = func(region, registryName string) (*client.GlueSchemaRegistryClient, error) {
+	return client.NewGlueSchemaRegistryClient(region, registryName)
+}
+
+// errUsage is returned for invalid command lines, which exit with status 2
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gsr", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	region := flags.String("region", os.Getenv("AWS_REGION"), "AWS region of the registry")
+	registryName := flags.String("registry", os.Getenv("GLUE_REGISTRY_NAME"), "name of the registry")
+	output := flags.String("o", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gsr [flags] list | get | create | register-version | set-compatibility | delete")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "gsr: unknown output format %q\n", *output)
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *registryName == "" {
+		fmt.Fprintln(stderr, "gsr: no registry given; use -registry or set GLUE_REGISTRY_NAME")
+		return 2
+	}
+
+	c, err := newClient(*region, *registryName)
+	if err != nil {
+		fmt.Fprintf(stderr, "gsr: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+
+	cmd := command{c: c, stdin: stdin, stdout: stdout, json: *output == "json"}
+	if err := cmd.run(flags.Arg(0), flags.Args()[1:]); err != nil {
+		fmt.Fprintf(stderr, "gsr: %v\n", err)
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+type command struct {
+	c      *client.GlueSchemaRegistryClient
+	stdin  io.Reader
+	stdout io.Writer
+	json   bool
+}
+
+func (cmd command) run(name string, args []string) error {
+	switch name {
+	case "list":
+		return cmd.list(args)
+	case "get":
+		return cmd.get(args)
+	case "create":
+		return cmd.create(args)
+	case "register-version":
+		return cmd.registerVersion(args)
+	case "set-compatibility":
+		return cmd.setCompatibility(args)
+	case "delete":
+		return cmd.delete(args)
+	}
+	return fmt.Errorf("%w: unknown command %q", errUsage, name)
+}
+
+func (cmd command) list(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: gsr list", errUsage)
+	}
+	summaries, err := cmd.c.ListSchemasWithLatestVersion()
+	if err != nil {
+		return err
+	}
+	if cmd.json {
+		return cmd.writeJSON(summaries)
+	}
+	w := tabwriter.NewWriter(cmd.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFORMAT\tLATEST")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", s.SchemaName, s.DataFormat, s.LatestSchemaVersion)
+	}
+	return w.Flush()
+}
+
+func (cmd command) get(args []string) error {
+	switch len(args) {
+	case 1:
+		schema, err := cmd.c.GetSchema(args[0])
+		if err != nil {
+			return err
+		}
+		if cmd.json {
+			return cmd.writeJSON(schema)
+		}
+		w := tabwriter.NewWriter(cmd.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "Name:\t%s\n", aws.StringValue(schema.SchemaName))
+		fmt.Fprintf(w, "Format:\t%s\n", aws.StringValue(schema.DataFormat))
+		fmt.Fprintf(w, "Compatibility:\t%s\n", aws.StringValue(schema.Compatibility))
+		fmt.Fprintf(w, "Latest version:\t%d\n", aws.Int64Value(schema.LatestSchemaVersion))
+		fmt.Fprintf(w, "Status:\t%s\n", aws.StringValue(schema.SchemaStatus))
+		fmt.Fprintf(w, "ARN:\t%s\n", aws.StringValue(schema.SchemaArn))
+		return w.Flush()
+	case 2:
+		versionNumber, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid version %q", errUsage, args[1])
+		}
+		version, err := cmd.c.GetSchemaVersion(args[0], versionNumber)
+		if err != nil {
+			return err
+		}
+		if cmd.json {
+			return cmd.writeJSON(version)
+		}
+		_, err = fmt.Fprintln(cmd.stdout, aws.StringValue(version.SchemaDefinition))
+		return err
+	}
+	return fmt.Errorf("%w: gsr get schema [version]", errUsage)
+}
+
+func (cmd command) create(args []string) error {
+	if len(args) != 3 && len(args) != 4 {
+		return fmt.Errorf("%w: gsr create schema format file [compatibility]", errUsage)
+	}
+	definition, err := cmd.readDefinition(args[2])
+	if err != nil {
+		return err
+	}
+	var compatibility client.Compatibility
+	if len(args) == 4 {
+		compatibility = client.Compatibility(strings.ToUpper(args[3]))
+	}
+	result, err := cmd.c.CreateSchema(args[0], client.DataFormat(strings.ToUpper(args[1])), definition, compatibility)
+	if err != nil {
+		return err
+	}
+	if cmd.json {
+		return cmd.writeJSON(result)
+	}
+	_, err = fmt.Fprintf(cmd.stdout, "Created schema %s (version %d, %s)\n", aws.StringValue(result.SchemaName),
+		aws.Int64Value(result.LatestSchemaVersion), aws.StringValue(result.Compatibility))
+	return err
+}
+
+func (cmd command) registerVersion(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%w: gsr register-version schema file", errUsage)
+	}
+	definition, err := cmd.readDefinition(args[1])
+	if err != nil {
+		return err
+	}
+	result, err := cmd.c.RegisterSchemaVersion(args[0], definition)
+	if err != nil {
+		return err
+	}
+	if cmd.json {
+		return cmd.writeJSON(result)
+	}
+	_, err = fmt.Fprintf(cmd.stdout, "Registered %s version %d (%s)\n", args[0],
+		aws.Int64Value(result.VersionNumber), aws.StringValue(result.Status))
+	return err
+}
+
+func (cmd command) setCompatibility(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%w: gsr set-compatibility schema mode", errUsage)
+	}
+	result, err := cmd.c.UpdateSchemaCompatibility(args[0], client.Compatibility(strings.ToUpper(args[1])))
+	if err != nil {
+		return err
+	}
+	if cmd.json {
+		return cmd.writeJSON(result)
+	}
+	_, err = fmt.Fprintf(cmd.stdout, "Set compatibility of %s to %s\n", args[0], strings.ToUpper(args[1]))
+	return err
+}
+
+func (cmd command) delete(args []string) error {
+	switch len(args) {
+	case 1:
+		result, err := cmd.c.DeleteSchema(args[0])
+		if err != nil {
+			return err
+		}
+		if cmd.json {
+			return cmd.writeJSON(result)
+		}
+		_, err = fmt.Fprintf(cmd.stdout, "Deleted schema %s (%s)\n", args[0], aws.StringValue(result.Status))
+		return err
+	case 2:
+		result, err := cmd.c.DeleteSchemaVersions(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		// Partial deletes fail in both output formats, so that scripts can detect them
+		var failed error
+		if len(result.SchemaVersionErrors) > 0 {
+			failed = fmt.Errorf("failed to delete %d of versions %s of %s", len(result.SchemaVersionErrors), args[1], args[0])
+		}
+		if cmd.json {
+			if err := cmd.writeJSON(result); err != nil {
+				return err
+			}
+			return failed
+		}
+		if failed != nil {
+			for _, e := range result.SchemaVersionErrors {
+				message := "unknown error"
+				if e.ErrorDetails != nil {
+					message = aws.StringValue(e.ErrorDetails.ErrorMessage)
+				}
+				fmt.Fprintf(cmd.stdout, "Version %d not deleted: %s\n", aws.Int64Value(e.VersionNumber), message)
+			}
+			return failed
+		}
+		_, err = fmt.Fprintf(cmd.stdout, "Deleted versions %s of %s\n", args[1], args[0])
+		return err
+	}
+	return fmt.Errorf("%w: gsr delete schema [versions]", errUsage)
+}
+
+// readDefinition reads a schema definition from file, or from standard input for "-"
+func (cmd command) readDefinition(file string) (string, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(cmd.stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read schema definition: %w", err)
+	}
+	return string(data), nil
+}
+
+func (cmd command) writeJSON(v interface{}) error {
+	encoder := json.NewEncoder(cmd.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
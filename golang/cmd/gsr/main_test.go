@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+)
+
+func TestCommands(t *testing.T) {
+	fake := gluetest.New()
+	restore := newClient
+	defer func() { newClient = restore }()
+	var registries []string
+	newClient = func(region, registryName string) (*client.GlueSchemaRegistryClient, error) {
+		registries = append(registries, registryName)
+		return client.NewGlueSchemaRegistryClientWithAPI(fake, registryName), nil
+	}
+
+	gsr := func(stdin string, args ...string) (string, int) {
+		var stdout, stderr bytes.Buffer
+		status := run(append([]string{"-registry", "test-registry"}, args...), strings.NewReader(stdin), &stdout, &stderr)
+		return stdout.String() + stderr.String(), status
+	}
+
+	if out, status := gsr(gluetest.SalesforceAuditAvroSchema, "create", "SalesforceAudit", "avro", "-", "backward"); status != 0 || out != "Created schema SalesforceAudit (version 1, BACKWARD)\n" {
+		t.Errorf("create mismatch: status %d, output %q", status, out)
+	}
+	v2 := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events", "Salesforce audit events, v2", 1)
+	file := filepath.Join(t.TempDir(), "v2.avsc")
+	if err := os.WriteFile(file, []byte(v2), 0o644); err != nil {
+		t.Fatalf("Failed to write definition: %v", err)
+	}
+	if out, status := gsr("", "register-version", "SalesforceAudit", file); status != 0 || out != "Registered SalesforceAudit version 2 (AVAILABLE)\n" {
+		t.Errorf("register-version mismatch: status %d, output %q", status, out)
+	}
+	if out, status := gsr("", "set-compatibility", "SalesforceAudit", "full"); status != 0 || out != "Set compatibility of SalesforceAudit to FULL\n" {
+		t.Errorf("set-compatibility mismatch: status %d, output %q", status, out)
+	}
+
+	out, status := gsr("", "-o", "json", "list")
+	if status != 0 {
+		t.Fatalf("list failed with status %d: %s", status, out)
+	}
+	var summaries []client.SchemaSummary
+	if err := json.Unmarshal([]byte(out), &summaries); err != nil {
+		t.Fatalf("Failed to parse list output %q: %v", out, err)
+	}
+	if len(summaries) != 1 || summaries[0].SchemaName != "SalesforceAudit" || summaries[0].LatestSchemaVersion != 2 {
+		t.Errorf("list mismatch: got %+v", summaries)
+	}
+	if out, status := gsr("", "get", "SalesforceAudit"); status != 0 || !strings.Contains(out, "Compatibility:   FULL") {
+		t.Errorf("get mismatch: status %d, output %q", status, out)
+	}
+	if out, status := gsr("", "get", "SalesforceAudit", "2"); status != 0 || out != v2+"\n" {
+		t.Errorf("get version mismatch: status %d, output %q", status, out)
+	}
+
+	// Glue refuses to delete the first version, which fails the command in both formats
+	if out, status := gsr("", "delete", "SalesforceAudit", "1"); status != 1 || !strings.Contains(out, "Version 1 not deleted") {
+		t.Errorf("delete version mismatch: status %d, output %q", status, out)
+	}
+	if out, status := gsr("", "-o", "json", "delete", "SalesforceAudit", "1"); status != 1 || !strings.Contains(out, `"SchemaVersionErrors"`) {
+		t.Errorf("delete version JSON mismatch: status %d, output %q", status, out)
+	}
+	if out, status := gsr("", "delete", "SalesforceAudit"); status != 0 || !strings.HasPrefix(out, "Deleted schema SalesforceAudit") {
+		t.Errorf("delete mismatch: status %d, output %q", status, out)
+	}
+	if out, status := gsr("", "get", "SalesforceAudit"); status != 1 {
+		t.Errorf("Expected status 1 getting a deleted schema, got %d: %s", status, out)
+	}
+	for _, args := range [][]string{{}, {"rename"}, {"get"}, {"get", "SalesforceAudit", "latest"}, {"-o", "yaml", "list"}} {
+		if out, status := gsr("", args...); status != 2 {
+			t.Errorf("Expected usage status 2 for %v, got %d: %s", args, status, out)
+		}
+	}
+	// No registry fails before any client is created, rather than falling back to a default
+	t.Setenv("GLUE_REGISTRY_NAME", "")
+	calls := len(registries)
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"list"}, strings.NewReader(""), &stdout, &stderr); status != 2 || len(registries) != calls {
+		t.Errorf("Expected usage status 2 without a registry, got %d: %s", status, stderr.String())
+	}
+	t.Setenv("GLUE_REGISTRY_NAME", "env-registry")
+	if status := run([]string{"list"}, strings.NewReader(""), &stdout, &stderr); status != 0 || registries[len(registries)-1] != "env-registry" {
+		t.Errorf("Expected GLUE_REGISTRY_NAME to select the registry, got status %d and %v", status, registries)
+	}
+	if registries[0] != "test-registry" {
+		t.Errorf("Registry mismatch: expected test-registry, got %s", registries[0])
+	}
+}
@@ -0,0 +1,196 @@
+// Package compat checks locally whether a new Avro schema definition can replace an old one
+// under a Glue compatibility mode, without a round trip to the registry.
+//
+// The check is conservative: it compares the fields of the top-level record, and of records
+// nested inline in it. Any other field present in both definitions must have an identical
+// type, so changes that Avro schema resolution would accept, such as promoting int to long,
+// are reported as breaking.
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BreakingChange is one reason the new definition is incompatible with the old one.
+// Path is the name of the affected field, dotted for fields of nested records, such as
+// "address.zip".
+type BreakingChange struct {
+	Path   string
+	Reason string
+}
+
+func (c BreakingChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Path, c.Reason)
+}
+
+// Check reports the changes from oldDefinition to newDefinition that break mode, which is
+// one of the Glue compatibility modes (BACKWARD, FORWARD, FULL, their _ALL variants,
+// NONE or DISABLED). The _ALL variants are checked like their base mode, since only two
+// definitions are compared. An empty result means the change is compatible.
+func Check(oldDefinition, newDefinition, mode string) ([]BreakingChange, error) {
+	backward, forward, err := directions(mode)
+	if err != nil {
+		return nil, err
+	}
+	if !backward && !forward {
+		return nil, nil
+	}
+
+	oldFields, err := recordFields(oldDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse old schema: %w", err)
+	}
+	newFields, err := recordFields(newDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new schema: %w", err)
+	}
+
+	return compareFields(oldFields, newFields, "", backward, forward), nil
+}
+
+// compareFields reports the breaking changes between the fields of two versions of a
+// record, prefixing paths with prefix
+func compareFields(oldFields, newFields []field, prefix string, backward, forward bool) []BreakingChange {
+	changes := typeChanges(oldFields, newFields, prefix, backward, forward)
+	if backward {
+		// Consumers using the new schema must read data written with the old one
+		changes = append(changes, missingFields(newFields, oldFields, prefix, "new", "old")...)
+	}
+	if forward {
+		// Consumers using the old schema must read data written with the new one
+		changes = append(changes, missingFields(oldFields, newFields, prefix, "old", "new")...)
+	}
+	return changes
+}
+
+// directions reports which reader/writer directions mode requires
+func directions(mode string) (backward, forward bool, err error) {
+	switch strings.TrimSuffix(mode, "_ALL") {
+	case "BACKWARD":
+		return true, false, nil
+	case "FORWARD":
+		return false, true, nil
+	case "FULL":
+		return true, true, nil
+	case "NONE", "DISABLED":
+		return false, false, nil
+	default:
+		return false, false, fmt.Errorf("unknown compatibility mode: %s", mode)
+	}
+}
+
+type field struct {
+	name       string
+	typ        string
+	hasDefault bool
+	// record is the parsed type of a field whose type is an inline record definition
+	record *record
+}
+
+type record struct {
+	name   string
+	fields []field
+}
+
+// typeChanges reports the fields whose type differs between the two definitions. Fields
+// that are the same named record in both are compared field by field instead.
+func typeChanges(oldFields, newFields []field, prefix string, backward, forward bool) []BreakingChange {
+	oldByName := make(map[string]field, len(oldFields))
+	for _, f := range oldFields {
+		oldByName[f.name] = f
+	}
+
+	var changes []BreakingChange
+	for _, f := range newFields {
+		old, ok := oldByName[f.name]
+		if !ok {
+			continue
+		}
+		if old.record != nil && f.record != nil && old.record.name == f.record.name {
+			changes = append(changes, compareFields(old.record.fields, f.record.fields, prefix+f.name+".", backward, forward)...)
+			continue
+		}
+		if old.typ != f.typ {
+			changes = append(changes, BreakingChange{
+				Path:   prefix + f.name,
+				Reason: fmt.Sprintf("type changed from %s to %s", old.typ, f.typ),
+			})
+		}
+	}
+	return changes
+}
+
+// missingFields reports the reader fields that cannot be filled from data written with
+// writer because they are absent from it and have no default
+func missingFields(reader, writer []field, prefix, readerName, writerName string) []BreakingChange {
+	written := make(map[string]bool, len(writer))
+	for _, f := range writer {
+		written[f.name] = true
+	}
+
+	var changes []BreakingChange
+	for _, f := range reader {
+		if !written[f.name] && !f.hasDefault {
+			changes = append(changes, BreakingChange{
+				Path:   prefix + f.name,
+				Reason: fmt.Sprintf("field in %s schema has no default and is missing from %s schema", readerName, writerName),
+			})
+		}
+	}
+	return changes
+}
+
+// recordFields parses an Avro record definition into its fields
+func recordFields(definition string) ([]field, error) {
+	r, err := parseRecord([]byte(definition))
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("schema is not an Avro record")
+	}
+	return r.fields, nil
+}
+
+// parseRecord parses an Avro record definition, returning nil if it is valid JSON but
+// not an inline record
+func parseRecord(definition []byte) (*record, error) {
+	var raw struct {
+		Type   string                       `json:"type"`
+		Name   string                       `json:"name"`
+		Fields []map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(definition, &raw); err != nil {
+		var other interface{}
+		if json.Unmarshal(definition, &other) == nil {
+			// A primitive name, union or other non-object type
+			return nil, nil
+		}
+		return nil, err
+	}
+	if raw.Type != "record" {
+		return nil, nil
+	}
+
+	fields := make([]field, len(raw.Fields))
+	for i, f := range raw.Fields {
+		var name string
+		if err := json.Unmarshal(f["name"], &name); err != nil {
+			return nil, fmt.Errorf("field %d has no name", i)
+		}
+		var typ bytes.Buffer
+		if err := json.Compact(&typ, f["type"]); err != nil {
+			return nil, fmt.Errorf("field %s has no type", name)
+		}
+		nested, err := parseRecord(f["type"])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		_, hasDefault := f["default"]
+		fields[i] = field{name: name, typ: typ.String(), hasDefault: hasDefault, record: nested}
+	}
+	return &record{name: raw.Name, fields: fields}, nil
+}
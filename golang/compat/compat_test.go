@@ -0,0 +1,102 @@
+package compat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/compat"
+)
+
+const base = `{"type": "record", "name": "Event", "fields": [
+	{"name": "id", "type": "string"},
+	{"name": "count", "type": "int"}
+]}`
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		mode       string
+		expected   string
+	}{
+		{
+			name:       "added field with default is fully compatible",
+			definition: `{"type": "record", "name": "Event", "fields": [{"name": "id", "type": "string"}, {"name": "count", "type": "int"}, {"name": "note", "type": ["null", "string"], "default": null}]}`,
+			mode:       "FULL",
+			expected:   "[]",
+		},
+		{
+			name:       "added field without default breaks backward",
+			definition: `{"type": "record", "name": "Event", "fields": [{"name": "id", "type": "string"}, {"name": "count", "type": "int"}, {"name": "note", "type": "string"}]}`,
+			mode:       "BACKWARD",
+			expected:   "[note: field in new schema has no default and is missing from old schema]",
+		},
+		{
+			name:       "removed field without default breaks forward",
+			definition: `{"type": "record", "name": "Event", "fields": [{"name": "id", "type": "string"}]}`,
+			mode:       "FORWARD_ALL",
+			expected:   "[count: field in old schema has no default and is missing from new schema]",
+		},
+		{
+			name:       "removed field is backward compatible",
+			definition: `{"type": "record", "name": "Event", "fields": [{"name": "id", "type": "string"}]}`,
+			mode:       "BACKWARD",
+			expected:   "[]",
+		},
+		{
+			name:       "type change is breaking",
+			definition: `{"type": "record", "name": "Event", "fields": [{"name": "id", "type": "string"}, {"name": "count", "type": "long"}]}`,
+			mode:       "BACKWARD",
+			expected:   `[count: type changed from "int" to "long"]`,
+		},
+		{
+			name:       "none accepts anything",
+			definition: `{"type": "record", "name": "Event", "fields": [{"name": "other", "type": "bytes"}]}`,
+			mode:       "NONE",
+			expected:   "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := compat.Check(base, tt.definition, tt.mode)
+			if err != nil {
+				t.Fatalf("Failed to check compatibility: %v", err)
+			}
+			if got := fmt.Sprint(changes); got != tt.expected {
+				t.Errorf("Changes mismatch: expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckUnknownMode(t *testing.T) {
+	if _, err := compat.Check(base, base, "SIDEWAYS"); err == nil {
+		t.Fatal("Expected error for unknown compatibility mode")
+	}
+}
+
+func TestCheckNestedRecord(t *testing.T) {
+	old := `{"type": "record", "name": "Event", "fields": [
+		{"name": "address", "type": {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}}
+	]}`
+	changed := `{"type": "record", "name": "Event", "fields": [
+		{"name": "address", "type": {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}, {"name": "zip", "type": "string"}]}}
+	]}`
+
+	changes, err := compat.Check(old, changed, "FULL")
+	if err != nil {
+		t.Fatalf("Failed to check compatibility: %v", err)
+	}
+	expected := "[address.zip: field in new schema has no default and is missing from old schema]"
+	if got := fmt.Sprint(changes); got != expected {
+		t.Errorf("Changes mismatch: expected %s, got %s", expected, got)
+	}
+
+	renamed := `{"type": "record", "name": "Event", "fields": [
+		{"name": "address", "type": {"type": "record", "name": "Location", "fields": [{"name": "city", "type": "string"}]}}
+	]}`
+	if changes, err := compat.Check(old, renamed, "BACKWARD"); err != nil || len(changes) != 1 || changes[0].Path != "address" {
+		t.Errorf("Expected a type change for a renamed nested record, got %v (error %v)", changes, err)
+	}
+}
@@ -4,10 +4,14 @@ go 1.21
 
 require (
 	github.com/aws/aws-sdk-go v1.50.0
+	github.com/bufbuild/protocompile v0.14.1
 	github.com/linkedin/goavro/v2 v2.12.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
 )
@@ -0,0 +1,192 @@
+package gluetest
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// The WithContext variants fail with the context's error once it is done, wait for any
+// latency configured with SetLatency and otherwise behave exactly like the plain
+// operations. Request options are ignored.
+
+// wait blocks for the latency configured for op and returns ctx's error if it is done first
+func (g *Glue) wait(ctx aws.Context, op string) error {
+	g.mu.Lock()
+	latency := g.latencies[op]
+	g.mu.Unlock()
+
+	if latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// CreateRegistryWithContext implements glueiface.GlueAPI
+func (g *Glue) CreateRegistryWithContext(ctx aws.Context, input *glue.CreateRegistryInput, _ ...request.Option) (*glue.CreateRegistryOutput, error) {
+	if err := g.wait(ctx, "CreateRegistry"); err != nil {
+		return nil, err
+	}
+	return g.CreateRegistry(input)
+}
+
+// GetRegistryWithContext implements glueiface.GlueAPI
+func (g *Glue) GetRegistryWithContext(ctx aws.Context, input *glue.GetRegistryInput, _ ...request.Option) (*glue.GetRegistryOutput, error) {
+	if err := g.wait(ctx, "GetRegistry"); err != nil {
+		return nil, err
+	}
+	return g.GetRegistry(input)
+}
+
+// UpdateRegistryWithContext implements glueiface.GlueAPI
+func (g *Glue) UpdateRegistryWithContext(ctx aws.Context, input *glue.UpdateRegistryInput, _ ...request.Option) (*glue.UpdateRegistryOutput, error) {
+	if err := g.wait(ctx, "UpdateRegistry"); err != nil {
+		return nil, err
+	}
+	return g.UpdateRegistry(input)
+}
+
+// DeleteRegistryWithContext implements glueiface.GlueAPI
+func (g *Glue) DeleteRegistryWithContext(ctx aws.Context, input *glue.DeleteRegistryInput, _ ...request.Option) (*glue.DeleteRegistryOutput, error) {
+	if err := g.wait(ctx, "DeleteRegistry"); err != nil {
+		return nil, err
+	}
+	return g.DeleteRegistry(input)
+}
+
+// CreateSchemaWithContext implements glueiface.GlueAPI
+func (g *Glue) CreateSchemaWithContext(ctx aws.Context, input *glue.CreateSchemaInput, _ ...request.Option) (*glue.CreateSchemaOutput, error) {
+	if err := g.wait(ctx, "CreateSchema"); err != nil {
+		return nil, err
+	}
+	return g.CreateSchema(input)
+}
+
+// GetSchemaWithContext implements glueiface.GlueAPI
+func (g *Glue) GetSchemaWithContext(ctx aws.Context, input *glue.GetSchemaInput, _ ...request.Option) (*glue.GetSchemaOutput, error) {
+	if err := g.wait(ctx, "GetSchema"); err != nil {
+		return nil, err
+	}
+	return g.GetSchema(input)
+}
+
+// GetSchemaVersionWithContext implements glueiface.GlueAPI
+func (g *Glue) GetSchemaVersionWithContext(ctx aws.Context, input *glue.GetSchemaVersionInput, _ ...request.Option) (*glue.GetSchemaVersionOutput, error) {
+	if err := g.wait(ctx, "GetSchemaVersion"); err != nil {
+		return nil, err
+	}
+	return g.GetSchemaVersion(input)
+}
+
+// GetSchemaByDefinitionWithContext implements glueiface.GlueAPI
+func (g *Glue) GetSchemaByDefinitionWithContext(ctx aws.Context, input *glue.GetSchemaByDefinitionInput, _ ...request.Option) (*glue.GetSchemaByDefinitionOutput, error) {
+	if err := g.wait(ctx, "GetSchemaByDefinition"); err != nil {
+		return nil, err
+	}
+	return g.GetSchemaByDefinition(input)
+}
+
+// RegisterSchemaVersionWithContext implements glueiface.GlueAPI
+func (g *Glue) RegisterSchemaVersionWithContext(ctx aws.Context, input *glue.RegisterSchemaVersionInput, _ ...request.Option) (*glue.RegisterSchemaVersionOutput, error) {
+	if err := g.wait(ctx, "RegisterSchemaVersion"); err != nil {
+		return nil, err
+	}
+	return g.RegisterSchemaVersion(input)
+}
+
+// ListSchemasWithContext implements glueiface.GlueAPI
+func (g *Glue) ListSchemasWithContext(ctx aws.Context, input *glue.ListSchemasInput, _ ...request.Option) (*glue.ListSchemasOutput, error) {
+	if err := g.wait(ctx, "ListSchemas"); err != nil {
+		return nil, err
+	}
+	return g.ListSchemas(input)
+}
+
+// UpdateSchemaWithContext implements glueiface.GlueAPI
+func (g *Glue) UpdateSchemaWithContext(ctx aws.Context, input *glue.UpdateSchemaInput, _ ...request.Option) (*glue.UpdateSchemaOutput, error) {
+	if err := g.wait(ctx, "UpdateSchema"); err != nil {
+		return nil, err
+	}
+	return g.UpdateSchema(input)
+}
+
+// ListSchemaVersionsWithContext implements glueiface.GlueAPI
+func (g *Glue) ListSchemaVersionsWithContext(ctx aws.Context, input *glue.ListSchemaVersionsInput, _ ...request.Option) (*glue.ListSchemaVersionsOutput, error) {
+	if err := g.wait(ctx, "ListSchemaVersions"); err != nil {
+		return nil, err
+	}
+	return g.ListSchemaVersions(input)
+}
+
+// DeleteSchemaVersionsWithContext implements glueiface.GlueAPI
+func (g *Glue) DeleteSchemaVersionsWithContext(ctx aws.Context, input *glue.DeleteSchemaVersionsInput, _ ...request.Option) (*glue.DeleteSchemaVersionsOutput, error) {
+	if err := g.wait(ctx, "DeleteSchemaVersions"); err != nil {
+		return nil, err
+	}
+	return g.DeleteSchemaVersions(input)
+}
+
+// PutSchemaVersionMetadataWithContext implements glueiface.GlueAPI
+func (g *Glue) PutSchemaVersionMetadataWithContext(ctx aws.Context, input *glue.PutSchemaVersionMetadataInput, _ ...request.Option) (*glue.PutSchemaVersionMetadataOutput, error) {
+	if err := g.wait(ctx, "PutSchemaVersionMetadata"); err != nil {
+		return nil, err
+	}
+	return g.PutSchemaVersionMetadata(input)
+}
+
+// QuerySchemaVersionMetadataWithContext implements glueiface.GlueAPI
+func (g *Glue) QuerySchemaVersionMetadataWithContext(ctx aws.Context, input *glue.QuerySchemaVersionMetadataInput, _ ...request.Option) (*glue.QuerySchemaVersionMetadataOutput, error) {
+	if err := g.wait(ctx, "QuerySchemaVersionMetadata"); err != nil {
+		return nil, err
+	}
+	return g.QuerySchemaVersionMetadata(input)
+}
+
+// DeleteSchemaWithContext implements glueiface.GlueAPI
+func (g *Glue) DeleteSchemaWithContext(ctx aws.Context, input *glue.DeleteSchemaInput, _ ...request.Option) (*glue.DeleteSchemaOutput, error) {
+	if err := g.wait(ctx, "DeleteSchema"); err != nil {
+		return nil, err
+	}
+	return g.DeleteSchema(input)
+}
+
+// GetTagsWithContext implements glueiface.GlueAPI
+func (g *Glue) GetTagsWithContext(ctx aws.Context, input *glue.GetTagsInput, _ ...request.Option) (*glue.GetTagsOutput, error) {
+	if err := g.wait(ctx, "GetTags"); err != nil {
+		return nil, err
+	}
+	return g.GetTags(input)
+}
+
+// TagResourceWithContext implements glueiface.GlueAPI
+func (g *Glue) TagResourceWithContext(ctx aws.Context, input *glue.TagResourceInput, _ ...request.Option) (*glue.TagResourceOutput, error) {
+	if err := g.wait(ctx, "TagResource"); err != nil {
+		return nil, err
+	}
+	return g.TagResource(input)
+}
+
+// UntagResourceWithContext implements glueiface.GlueAPI
+func (g *Glue) UntagResourceWithContext(ctx aws.Context, input *glue.UntagResourceInput, _ ...request.Option) (*glue.UntagResourceOutput, error) {
+	if err := g.wait(ctx, "UntagResource"); err != nil {
+		return nil, err
+	}
+	return g.UntagResource(input)
+}
+
+// CheckSchemaVersionValidityWithContext implements glueiface.GlueAPI
+func (g *Glue) CheckSchemaVersionValidityWithContext(ctx aws.Context, input *glue.CheckSchemaVersionValidityInput, _ ...request.Option) (*glue.CheckSchemaVersionValidityOutput, error) {
+	if err := g.wait(ctx, "CheckSchemaVersionValidity"); err != nil {
+		return nil, err
+	}
+	return g.CheckSchemaVersionValidity(input)
+}
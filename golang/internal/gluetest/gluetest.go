@@ -0,0 +1,862 @@
+// Package gluetest provides an in-memory fake of the AWS Glue Schema Registry API
+// so that the client and serializers can be tested without AWS credentials.
+package gluetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/linkedin/goavro/v2"
+)
+
+// SalesforceAuditAvroSchema is the Avro definition of the SalesforceAudit record
+const SalesforceAuditAvroSchema = `{
+  "type": "record",
+  "name": "SalesforceAudit",
+  "namespace": "com.aws.glue.schema.registry",
+  "doc": "Schema for Salesforce audit events",
+  "fields": [
+    {"name": "eventId", "type": "string", "doc": "Unique identifier for the audit event"},
+    {"name": "eventName", "type": "string", "doc": "Name of the audit event"},
+    {"name": "timestamp", "type": "long", "doc": "Timestamp of the event in milliseconds since epoch"},
+    {"name": "eventDetails", "type": "string", "doc": "Detailed information about the audit event"}
+  ]
+}`
+
+// SalesforceAuditNullableAvroSchema is SalesforceAuditAvroSchema with an optional
+// eventDetails field, a union of null and string that defaults to null
+const SalesforceAuditNullableAvroSchema = `{
+  "type": "record",
+  "name": "SalesforceAudit",
+  "namespace": "com.aws.glue.schema.registry",
+  "doc": "Schema for Salesforce audit events",
+  "fields": [
+    {"name": "eventId", "type": "string", "doc": "Unique identifier for the audit event"},
+    {"name": "eventName", "type": "string", "doc": "Name of the audit event"},
+    {"name": "timestamp", "type": "long", "doc": "Timestamp of the event in milliseconds since epoch"},
+    {"name": "eventDetails", "type": ["null", "string"], "default": null, "doc": "Detailed information about the audit event, if any"}
+  ]
+}`
+
+// SalesforceAuditTimestampMillisAvroSchema is SalesforceAuditAvroSchema with a timestamp of
+// logical type timestamp-millis, which goavro decodes as a time.Time
+const SalesforceAuditTimestampMillisAvroSchema = `{
+  "type": "record",
+  "name": "SalesforceAudit",
+  "namespace": "com.aws.glue.schema.registry",
+  "doc": "Schema for Salesforce audit events",
+  "fields": [
+    {"name": "eventId", "type": "string", "doc": "Unique identifier for the audit event"},
+    {"name": "eventName", "type": "string", "doc": "Name of the audit event"},
+    {"name": "timestamp", "type": {"type": "long", "logicalType": "timestamp-millis"}, "doc": "Timestamp of the event"},
+    {"name": "eventDetails", "type": "string", "doc": "Detailed information about the audit event"}
+  ]
+}`
+
+// SalesforceAuditJSONSchema is the JSON Schema definition of the SalesforceAudit record
+const SalesforceAuditJSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "SalesforceAudit",
+  "type": "object",
+  "properties": {
+    "eventId": {"type": "string"},
+    "eventName": {"type": "string"},
+    "timestamp": {"type": "integer"},
+    "eventDetails": {"type": "string"}
+  },
+  "required": ["eventId", "eventName", "timestamp", "eventDetails"],
+  "additionalProperties": false
+}`
+
+// Glue is an in-memory implementation of the Glue schema registry operations.
+// Operations it does not implement panic through the embedded nil GlueAPI.
+type Glue struct {
+	glueiface.GlueAPI
+
+	mu         sync.Mutex
+	registries map[string]*registry
+	schemas    map[string]*schema
+	tags       map[string]map[string]string
+	errors     map[string]error
+	latencies  map[string]time.Duration
+	calls      map[string]int
+	nextID     int
+	seededID   string
+}
+
+type registry struct {
+	name        string
+	description string
+}
+
+type schema struct {
+	registryName  string
+	schemaName    string
+	dataFormat    string
+	compatibility string
+	description   string
+	versions      []*schemaVersion
+}
+
+type schemaVersion struct {
+	id         string
+	number     int64
+	definition string
+	status     string
+	metadata   map[string]string
+}
+
+// New creates an empty fake registry
+func New() *Glue {
+	return &Glue{
+		registries: make(map[string]*registry),
+		schemas:    make(map[string]*schema),
+		tags:       make(map[string]map[string]string),
+		errors:     make(map[string]error),
+		latencies:  make(map[string]time.Duration),
+		calls:      make(map[string]int),
+	}
+}
+
+// SetError makes every call to op fail with err until it is cleared with a nil err
+func (g *Glue) SetError(op string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err == nil {
+		delete(g.errors, op)
+		return
+	}
+	g.errors[op] = err
+}
+
+// SetLatency delays every WithContext call to op by d, returning the context's error
+// instead if it is done first. The plain operations are never delayed.
+func (g *Glue) SetLatency(op string, d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.latencies[op] = d
+}
+
+// Calls returns the number of times op has been invoked
+func (g *Glue) Calls(op string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.calls[op]
+}
+
+// begin records a call to op and returns the configured error for it, if any.
+// The caller must hold g.mu.
+func (g *Glue) begin(op string) error {
+	g.calls[op]++
+	return g.errors[op]
+}
+
+// SetNextVersionID makes the next schema version created, by CreateSchema or
+// RegisterSchemaVersion, get id instead of a generated ID, so that payloads whose header
+// names a known version ID can be decoded
+func (g *Glue) SetNextVersionID(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.seededID = id
+}
+
+func (g *Glue) newVersionID() string {
+	if id := g.seededID; id != "" {
+		g.seededID = ""
+		return id
+	}
+	g.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", g.nextID)
+}
+
+func schemaKey(registryName, schemaName string) string {
+	return registryName + "/" + schemaName
+}
+
+func notFound(format string, args ...interface{}) error {
+	return awserr.New(glue.ErrCodeEntityNotFoundException, fmt.Sprintf(format, args...), nil)
+}
+
+func (g *Glue) lookup(id *glue.SchemaId) (*schema, error) {
+	if id == nil {
+		return nil, awserr.New(glue.ErrCodeInvalidInputException, "SchemaId is required", nil)
+	}
+	s, ok := g.schemas[schemaKey(aws.StringValue(id.RegistryName), aws.StringValue(id.SchemaName))]
+	if !ok {
+		return nil, notFound("Schema is not found. RegistryName: %s, SchemaName: %s",
+			aws.StringValue(id.RegistryName), aws.StringValue(id.SchemaName))
+	}
+	return s, nil
+}
+
+func (s *schema) latest() *schemaVersion {
+	if len(s.versions) == 0 {
+		return nil
+	}
+	return s.versions[len(s.versions)-1]
+}
+
+func (s *schema) arn() string {
+	return fmt.Sprintf("arn:aws:glue:us-east-1:123456789012:schema/%s/%s", s.registryName, s.schemaName)
+}
+
+func (s *schema) output(v *schemaVersion) *glue.GetSchemaVersionOutput {
+	return &glue.GetSchemaVersionOutput{
+		DataFormat:       aws.String(s.dataFormat),
+		SchemaArn:        aws.String(s.arn()),
+		SchemaDefinition: aws.String(v.definition),
+		SchemaVersionId:  aws.String(v.id),
+		Status:           aws.String(v.status),
+		VersionNumber:    aws.Int64(v.number),
+	}
+}
+
+func registryARN(name string) string {
+	return fmt.Sprintf("arn:aws:glue:us-east-1:123456789012:registry/%s", name)
+}
+
+// CreateRegistry records a registry. Schemas can be created in any registry, whether
+// or not it was created first.
+func (g *Glue) CreateRegistry(input *glue.CreateRegistryInput) (*glue.CreateRegistryOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("CreateRegistry"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.RegistryName)
+	if _, ok := g.registries[name]; ok {
+		return nil, awserr.New(glue.ErrCodeAlreadyExistsException,
+			fmt.Sprintf("Registry already exists. RegistryName: %s", name), nil)
+	}
+	g.registries[name] = &registry{name: name, description: aws.StringValue(input.Description)}
+	return &glue.CreateRegistryOutput{
+		Description:  input.Description,
+		RegistryArn:  aws.String(registryARN(name)),
+		RegistryName: aws.String(name),
+	}, nil
+}
+
+// GetRegistry returns a registry created with CreateRegistry
+func (g *Glue) GetRegistry(input *glue.GetRegistryInput) (*glue.GetRegistryOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("GetRegistry"); err != nil {
+		return nil, err
+	}
+
+	r, err := g.lookupRegistry(input.RegistryId)
+	if err != nil {
+		return nil, err
+	}
+	return &glue.GetRegistryOutput{
+		Description:  aws.String(r.description),
+		RegistryArn:  aws.String(registryARN(r.name)),
+		RegistryName: aws.String(r.name),
+		Status:       aws.String(glue.RegistryStatusAvailable),
+	}, nil
+}
+
+// UpdateRegistry replaces the description of a registry created with CreateRegistry
+func (g *Glue) UpdateRegistry(input *glue.UpdateRegistryInput) (*glue.UpdateRegistryOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("UpdateRegistry"); err != nil {
+		return nil, err
+	}
+
+	r, err := g.lookupRegistry(input.RegistryId)
+	if err != nil {
+		return nil, err
+	}
+	r.description = aws.StringValue(input.Description)
+	return &glue.UpdateRegistryOutput{
+		RegistryArn:  aws.String(registryARN(r.name)),
+		RegistryName: aws.String(r.name),
+	}, nil
+}
+
+// DeleteRegistry removes a registry and its schemas immediately, but reports the
+// DELETING status that Glue returns while it deletes asynchronously
+func (g *Glue) DeleteRegistry(input *glue.DeleteRegistryInput) (*glue.DeleteRegistryOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("DeleteRegistry"); err != nil {
+		return nil, err
+	}
+
+	r, err := g.lookupRegistry(input.RegistryId)
+	if err != nil {
+		return nil, err
+	}
+	delete(g.registries, r.name)
+	for key, s := range g.schemas {
+		if s.registryName == r.name {
+			delete(g.schemas, key)
+		}
+	}
+	return &glue.DeleteRegistryOutput{
+		RegistryArn:  aws.String(registryARN(r.name)),
+		RegistryName: aws.String(r.name),
+		Status:       aws.String(glue.RegistryStatusDeleting),
+	}, nil
+}
+
+// GetTags returns the tags added to a resource ARN with TagResource. ARNs are not
+// checked against the registries and schemas of the fake.
+func (g *Glue) GetTags(input *glue.GetTagsInput) (*glue.GetTagsOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("GetTags"); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	for key, value := range g.tags[aws.StringValue(input.ResourceArn)] {
+		tags[key] = value
+	}
+	return &glue.GetTagsOutput{Tags: aws.StringMap(tags)}, nil
+}
+
+// TagResource adds tags to a resource ARN
+func (g *Glue) TagResource(input *glue.TagResourceInput) (*glue.TagResourceOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("TagResource"); err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.ResourceArn)
+	if g.tags[arn] == nil {
+		g.tags[arn] = make(map[string]string)
+	}
+	for key, value := range input.TagsToAdd {
+		g.tags[arn][key] = aws.StringValue(value)
+	}
+	return &glue.TagResourceOutput{}, nil
+}
+
+// UntagResource removes tags from a resource ARN
+func (g *Glue) UntagResource(input *glue.UntagResourceInput) (*glue.UntagResourceOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("UntagResource"); err != nil {
+		return nil, err
+	}
+
+	for _, key := range input.TagsToRemove {
+		delete(g.tags[aws.StringValue(input.ResourceArn)], aws.StringValue(key))
+	}
+	return &glue.UntagResourceOutput{}, nil
+}
+
+// lookupRegistry finds a registry by name. The caller must hold g.mu.
+func (g *Glue) lookupRegistry(id *glue.RegistryId) (*registry, error) {
+	if id == nil {
+		return nil, awserr.New(glue.ErrCodeInvalidInputException, "RegistryId is required", nil)
+	}
+	r, ok := g.registries[aws.StringValue(id.RegistryName)]
+	if !ok {
+		return nil, notFound("Registry is not found. RegistryName: %s", aws.StringValue(id.RegistryName))
+	}
+	return r, nil
+}
+
+// CreateSchema creates a schema together with its first version
+func (g *Glue) CreateSchema(input *glue.CreateSchemaInput) (*glue.CreateSchemaOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("CreateSchema"); err != nil {
+		return nil, err
+	}
+
+	registryName := aws.StringValue(input.RegistryId.RegistryName)
+	key := schemaKey(registryName, aws.StringValue(input.SchemaName))
+	if _, exists := g.schemas[key]; exists {
+		return nil, awserr.New(glue.ErrCodeAlreadyExistsException,
+			fmt.Sprintf("Schema already exists. SchemaName: %s", aws.StringValue(input.SchemaName)), nil)
+	}
+
+	s := &schema{
+		registryName:  registryName,
+		schemaName:    aws.StringValue(input.SchemaName),
+		dataFormat:    aws.StringValue(input.DataFormat),
+		compatibility: aws.StringValue(input.Compatibility),
+		description:   aws.StringValue(input.Description),
+	}
+	v := &schemaVersion{
+		id:         g.newVersionID(),
+		number:     1,
+		definition: aws.StringValue(input.SchemaDefinition),
+		status:     glue.SchemaVersionStatusAvailable,
+	}
+	s.versions = append(s.versions, v)
+	g.schemas[key] = s
+
+	return &glue.CreateSchemaOutput{
+		Compatibility:       aws.String(s.compatibility),
+		DataFormat:          aws.String(s.dataFormat),
+		LatestSchemaVersion: aws.Int64(v.number),
+		NextSchemaVersion:   aws.Int64(v.number + 1),
+		RegistryName:        aws.String(registryName),
+		SchemaArn:           aws.String(s.arn()),
+		SchemaName:          aws.String(s.schemaName),
+		SchemaStatus:        aws.String(glue.SchemaStatusAvailable),
+		SchemaVersionId:     aws.String(v.id),
+		SchemaVersionStatus: aws.String(v.status),
+	}, nil
+}
+
+// GetSchema returns the schema metadata
+func (g *Glue) GetSchema(input *glue.GetSchemaInput) (*glue.GetSchemaOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("GetSchema"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &glue.GetSchemaOutput{
+		Compatibility: aws.String(s.compatibility),
+		DataFormat:    aws.String(s.dataFormat),
+		RegistryName:  aws.String(s.registryName),
+		SchemaArn:     aws.String(s.arn()),
+		SchemaName:    aws.String(s.schemaName),
+		SchemaStatus:  aws.String(glue.SchemaStatusAvailable),
+	}
+	if s.description != "" {
+		output.Description = aws.String(s.description)
+	}
+	if latest := s.latest(); latest != nil {
+		output.LatestSchemaVersion = aws.Int64(latest.number)
+		output.NextSchemaVersion = aws.Int64(latest.number + 1)
+	}
+	return output, nil
+}
+
+// GetSchemaVersion returns a version selected by id, by number or as the latest version
+func (g *Glue) GetSchemaVersion(input *glue.GetSchemaVersionInput) (*glue.GetSchemaVersionOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("GetSchemaVersion"); err != nil {
+		return nil, err
+	}
+
+	s, v, err := g.findVersion(input.SchemaVersionId, input.SchemaId, input.SchemaVersionNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.output(v), nil
+}
+
+// GetSchemaByDefinition returns the version of a schema whose definition matches exactly
+func (g *Glue) GetSchemaByDefinition(input *glue.GetSchemaByDefinitionInput) (*glue.GetSchemaByDefinitionOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("GetSchemaByDefinition"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range s.versions {
+		if v.definition == aws.StringValue(input.SchemaDefinition) {
+			return &glue.GetSchemaByDefinitionOutput{
+				DataFormat:      aws.String(s.dataFormat),
+				SchemaArn:       aws.String(s.arn()),
+				SchemaVersionId: aws.String(v.id),
+				Status:          aws.String(v.status),
+			}, nil
+		}
+	}
+	return nil, notFound("Schema is not found. RegistryName: %s, SchemaName: %s",
+		s.registryName, s.schemaName)
+}
+
+// findVersion selects a version by id, or by schema and number or latest version.
+// The caller must hold g.mu.
+func (g *Glue) findVersion(versionID *string, schemaID *glue.SchemaId, number *glue.SchemaVersionNumber) (*schema, *schemaVersion, error) {
+	if versionID != nil {
+		for _, s := range g.schemas {
+			for _, v := range s.versions {
+				if v.id == aws.StringValue(versionID) {
+					return s, v, nil
+				}
+			}
+		}
+		return nil, nil, notFound("Schema version is not found. SchemaVersionId: %s", aws.StringValue(versionID))
+	}
+
+	s, err := g.lookup(schemaID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if number == nil {
+		return nil, nil, awserr.New(glue.ErrCodeInvalidInputException, "SchemaVersionNumber is required", nil)
+	}
+	if aws.BoolValue(number.LatestVersion) {
+		if latest := s.latest(); latest != nil {
+			return s, latest, nil
+		}
+	}
+	for _, v := range s.versions {
+		if v.number == aws.Int64Value(number.VersionNumber) {
+			return s, v, nil
+		}
+	}
+	return nil, nil, notFound("Schema version is not found. SchemaName: %s, VersionNumber: %d",
+		s.schemaName, aws.Int64Value(number.VersionNumber))
+}
+
+// RegisterSchemaVersion adds a version, or returns the existing version with an identical definition
+func (g *Glue) RegisterSchemaVersion(input *glue.RegisterSchemaVersionInput) (*glue.RegisterSchemaVersionOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("RegisterSchemaVersion"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+
+	definition := aws.StringValue(input.SchemaDefinition)
+	for _, v := range s.versions {
+		if v.definition == definition {
+			return &glue.RegisterSchemaVersionOutput{
+				SchemaVersionId: aws.String(v.id),
+				Status:          aws.String(v.status),
+				VersionNumber:   aws.Int64(v.number),
+			}, nil
+		}
+	}
+
+	number := int64(1)
+	if latest := s.latest(); latest != nil {
+		number = latest.number + 1
+	}
+	v := &schemaVersion{
+		id:         g.newVersionID(),
+		number:     number,
+		definition: definition,
+		status:     glue.SchemaVersionStatusAvailable,
+	}
+	s.versions = append(s.versions, v)
+
+	return &glue.RegisterSchemaVersionOutput{
+		SchemaVersionId: aws.String(v.id),
+		Status:          aws.String(v.status),
+		VersionNumber:   aws.Int64(v.number),
+	}, nil
+}
+
+// ListSchemas lists the schemas of a registry
+func (g *Glue) ListSchemas(input *glue.ListSchemasInput) (*glue.ListSchemasOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("ListSchemas"); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(g.schemas))
+	for key, s := range g.schemas {
+		if s.registryName == aws.StringValue(input.RegistryId.RegistryName) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start, end, next, err := page(len(keys), input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &glue.ListSchemasOutput{NextToken: next}
+	for _, key := range keys[start:end] {
+		s := g.schemas[key]
+		output.Schemas = append(output.Schemas, &glue.SchemaListItem{
+			RegistryName: aws.String(s.registryName),
+			SchemaArn:    aws.String(s.arn()),
+			SchemaName:   aws.String(s.schemaName),
+			SchemaStatus: aws.String(glue.SchemaStatusAvailable),
+		})
+	}
+	return output, nil
+}
+
+// defaultMaxResults is the page size Glue uses when a List call does not set MaxResults
+const defaultMaxResults = 100
+
+// page picks the [start, end) window of n sorted items for one List call. The token
+// is the offset of the next item, which keeps pages stable as long as nothing is
+// added or removed between calls.
+func page(n int, maxResults *int64, token *string) (start, end int, next *string, err error) {
+	if t := aws.StringValue(token); t != "" {
+		if start, err = strconv.Atoi(t); err != nil || start < 0 || start > n {
+			return 0, 0, nil, awserr.New(glue.ErrCodeInvalidInputException, fmt.Sprintf("Invalid next token: %s", t), nil)
+		}
+	}
+	size := int(aws.Int64Value(maxResults))
+	if size <= 0 {
+		size = defaultMaxResults
+	}
+	end = start + size
+	if end >= n {
+		return start, n, nil, nil
+	}
+	return start, end, aws.String(strconv.Itoa(end)), nil
+}
+
+// UpdateSchema updates the compatibility mode and description of a schema
+func (g *Glue) UpdateSchema(input *glue.UpdateSchemaInput) (*glue.UpdateSchemaOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("UpdateSchema"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+	if input.Compatibility != nil {
+		s.compatibility = aws.StringValue(input.Compatibility)
+	}
+	if input.Description != nil {
+		s.description = aws.StringValue(input.Description)
+	}
+
+	return &glue.UpdateSchemaOutput{
+		RegistryName: aws.String(s.registryName),
+		SchemaArn:    aws.String(s.arn()),
+		SchemaName:   aws.String(s.schemaName),
+	}, nil
+}
+
+// ListSchemaVersions lists the versions of a schema in ascending version order
+func (g *Glue) ListSchemaVersions(input *glue.ListSchemaVersionsInput) (*glue.ListSchemaVersionsOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("ListSchemaVersions"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, next, err := page(len(s.versions), input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &glue.ListSchemaVersionsOutput{NextToken: next}
+	for _, v := range s.versions[start:end] {
+		output.Schemas = append(output.Schemas, &glue.SchemaVersionListItem{
+			SchemaArn:       aws.String(s.arn()),
+			SchemaVersionId: aws.String(v.id),
+			Status:          aws.String(v.status),
+			VersionNumber:   aws.Int64(v.number),
+		})
+	}
+	return output, nil
+}
+
+// DeleteSchemaVersions hard-deletes a single version ("5") or a range of versions ("5-8").
+// Like Glue, it refuses to delete the first version and reports each version it could not
+// delete in SchemaVersionErrors.
+func (g *Glue) DeleteSchemaVersions(input *glue.DeleteSchemaVersionsInput) (*glue.DeleteSchemaVersionsOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("DeleteSchemaVersions"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := parseVersionRange(aws.StringValue(input.Versions))
+	if err != nil {
+		return nil, err
+	}
+
+	output := &glue.DeleteSchemaVersionsOutput{}
+	for number := from; number <= to; number++ {
+		index := -1
+		for i, v := range s.versions {
+			if v.number == number {
+				index = i
+			}
+		}
+
+		var message string
+		switch {
+		case index < 0:
+			message = "Schema version is not found"
+		case number == 1:
+			message = "The first schema version can only be deleted with DeleteSchema"
+		default:
+			s.versions = append(s.versions[:index], s.versions[index+1:]...)
+			continue
+		}
+		output.SchemaVersionErrors = append(output.SchemaVersionErrors, &glue.SchemaVersionErrorItem{
+			VersionNumber: aws.Int64(number),
+			ErrorDetails: &glue.ErrorDetails{
+				ErrorCode:    aws.String(glue.ErrCodeInvalidInputException),
+				ErrorMessage: aws.String(message),
+			},
+		})
+	}
+	return output, nil
+}
+
+func parseVersionRange(versions string) (from, to int64, err error) {
+	first, last, isRange := strings.Cut(versions, "-")
+	if from, err = strconv.ParseInt(first, 10, 64); err == nil {
+		to = from
+		if isRange {
+			to, err = strconv.ParseInt(last, 10, 64)
+		}
+	}
+	if err != nil || from < 1 || to < from {
+		return 0, 0, awserr.New(glue.ErrCodeInvalidInputException, fmt.Sprintf("Invalid version range: %s", versions), nil)
+	}
+	return from, to, nil
+}
+
+// PutSchemaVersionMetadata sets a metadata key on a version, replacing any previous value
+func (g *Glue) PutSchemaVersionMetadata(input *glue.PutSchemaVersionMetadataInput) (*glue.PutSchemaVersionMetadataOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("PutSchemaVersionMetadata"); err != nil {
+		return nil, err
+	}
+
+	s, v, err := g.findVersion(input.SchemaVersionId, input.SchemaId, input.SchemaVersionNumber)
+	if err != nil {
+		return nil, err
+	}
+	if input.MetadataKeyValue == nil {
+		return nil, awserr.New(glue.ErrCodeInvalidInputException, "MetadataKeyValue is required", nil)
+	}
+	if v.metadata == nil {
+		v.metadata = make(map[string]string)
+	}
+	key, value := aws.StringValue(input.MetadataKeyValue.MetadataKey), aws.StringValue(input.MetadataKeyValue.MetadataValue)
+	v.metadata[key] = value
+
+	return &glue.PutSchemaVersionMetadataOutput{
+		LatestVersion:   aws.Bool(v == s.latest()),
+		MetadataKey:     aws.String(key),
+		MetadataValue:   aws.String(value),
+		RegistryName:    aws.String(s.registryName),
+		SchemaArn:       aws.String(s.arn()),
+		SchemaName:      aws.String(s.schemaName),
+		SchemaVersionId: aws.String(v.id),
+		VersionNumber:   aws.Int64(v.number),
+	}, nil
+}
+
+// QuerySchemaVersionMetadata returns the metadata of a version. When MetadataList is set,
+// only the entries matching one of its key/value pairs are returned.
+func (g *Glue) QuerySchemaVersionMetadata(input *glue.QuerySchemaVersionMetadataInput) (*glue.QuerySchemaVersionMetadataOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("QuerySchemaVersionMetadata"); err != nil {
+		return nil, err
+	}
+
+	_, v, err := g.findVersion(input.SchemaVersionId, input.SchemaId, input.SchemaVersionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &glue.QuerySchemaVersionMetadataOutput{
+		MetadataInfoMap: make(map[string]*glue.MetadataInfo),
+		SchemaVersionId: aws.String(v.id),
+	}
+	for key, value := range v.metadata {
+		matches := len(input.MetadataList) == 0
+		for _, pair := range input.MetadataList {
+			if aws.StringValue(pair.MetadataKey) == key && aws.StringValue(pair.MetadataValue) == value {
+				matches = true
+			}
+		}
+		if matches {
+			output.MetadataInfoMap[key] = &glue.MetadataInfo{MetadataValue: aws.String(value)}
+		}
+	}
+	return output, nil
+}
+
+// DeleteSchema removes a schema and its versions
+func (g *Glue) DeleteSchema(input *glue.DeleteSchemaInput) (*glue.DeleteSchemaOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("DeleteSchema"); err != nil {
+		return nil, err
+	}
+
+	s, err := g.lookup(input.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+	delete(g.schemas, schemaKey(s.registryName, s.schemaName))
+
+	return &glue.DeleteSchemaOutput{
+		SchemaArn:  aws.String(s.arn()),
+		SchemaName: aws.String(s.schemaName),
+		Status:     aws.String(glue.SchemaStatusDeleting),
+	}, nil
+}
+
+// CheckSchemaVersionValidity reports Avro definitions that goavro cannot parse and JSON
+// Schema definitions that are not JSON as invalid. Protobuf definitions are not checked.
+func (g *Glue) CheckSchemaVersionValidity(input *glue.CheckSchemaVersionValidityInput) (*glue.CheckSchemaVersionValidityOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.begin("CheckSchemaVersionValidity"); err != nil {
+		return nil, err
+	}
+
+	definition := aws.StringValue(input.SchemaDefinition)
+	var reason string
+	switch aws.StringValue(input.DataFormat) {
+	case glue.DataFormatAvro:
+		if _, err := goavro.NewCodec(definition); err != nil {
+			reason = err.Error()
+		}
+	case glue.DataFormatJson:
+		if !json.Valid([]byte(definition)) {
+			reason = "Schema definition is not valid JSON"
+		}
+	case glue.DataFormatProtobuf:
+	default:
+		return nil, awserr.New(glue.ErrCodeInvalidInputException,
+			fmt.Sprintf("Unsupported data format: %s", aws.StringValue(input.DataFormat)), nil)
+	}
+
+	output := &glue.CheckSchemaVersionValidityOutput{Valid: aws.Bool(reason == "")}
+	if reason != "" {
+		output.Error = aws.String(reason)
+	}
+	return output, nil
+}
@@ -0,0 +1,223 @@
+// Package inventory periodically snapshots what a schema registry contains and exposes it
+// as gauges in the Prometheus text exposition format.
+//
+// Two gauges are reported for every schema:
+//
+//	glue_schema_versions_total{schema="..."}           number of registered versions
+//	glue_schema_compatibility{schema="...",mode="..."} 1 for the schema's compatibility mode
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// Gauge names reported by the Collector
+const (
+	VersionsTotalGauge = "glue_schema_versions_total"
+	CompatibilityGauge = "glue_schema_compatibility"
+)
+
+// DefaultRefreshInterval is how often a Collector refreshes unless WithRefreshInterval is used
+const DefaultRefreshInterval = time.Minute
+
+var gaugeHelp = map[string]string{
+	VersionsTotalGauge: "Number of registered versions of a schema.",
+	CompatibilityGauge: "Compatibility mode of a schema; 1 for the current mode.",
+}
+
+// Source is the part of the registry client the Collector reads from.
+// *client.GlueSchemaRegistryClient implements it.
+type Source interface {
+	ListSchemasWithContext(ctx context.Context) ([]*glue.SchemaListItem, error)
+	GetSchemaWithContext(ctx context.Context, schemaName string) (*glue.GetSchemaOutput, error)
+	ListSchemaVersionsWithContext(ctx context.Context, schemaName string) ([]*glue.SchemaVersionListItem, error)
+}
+
+// Label is a single name/value pair of a Gauge
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Gauge is one sample of the inventory
+type Gauge struct {
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// Option configures a Collector
+type Option func(*Collector)
+
+// WithRefreshInterval sets how often the Collector refreshes the inventory. A value of zero
+// or less is ignored, keeping DefaultRefreshInterval.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(c *Collector) {
+		if d > 0 {
+			c.interval = d
+		}
+	}
+}
+
+// WithErrorHandler receives errors from background refreshes. The previous inventory is
+// kept when a refresh fails.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *Collector) {
+		c.onError = fn
+	}
+}
+
+// Collector keeps a periodically refreshed inventory of a registry. It is safe for
+// concurrent use and serves the inventory over HTTP in the Prometheus text format.
+type Collector struct {
+	source   Source
+	interval time.Duration
+	onError  func(error)
+
+	mu     sync.RWMutex
+	gauges []Gauge
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCollector creates a Collector and starts refreshing in the background, beginning
+// immediately. Call Close to stop it.
+func NewCollector(source Source, opts ...Option) *Collector {
+	c := &Collector{
+		source:   source,
+		interval: DefaultRefreshInterval,
+		onError:  func(error) {},
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx)
+	return c
+}
+
+func (c *Collector) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			c.onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh rebuilds the inventory now. On error the previous inventory is kept.
+func (c *Collector) Refresh(ctx context.Context) error {
+	schemas, err := c.source.ListSchemasWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var gauges []Gauge
+	for _, item := range schemas {
+		schemaName := aws.StringValue(item.SchemaName)
+
+		schema, err := c.source.GetSchemaWithContext(ctx, schemaName)
+		if err != nil {
+			return err
+		}
+		versions, err := c.source.ListSchemaVersionsWithContext(ctx, schemaName)
+		if err != nil {
+			return err
+		}
+
+		gauges = append(gauges,
+			Gauge{
+				Name:   VersionsTotalGauge,
+				Labels: []Label{{"schema", schemaName}},
+				Value:  float64(len(versions)),
+			},
+			Gauge{
+				Name:   CompatibilityGauge,
+				Labels: []Label{{"schema", schemaName}, {"mode", aws.StringValue(schema.Compatibility)}},
+				Value:  1,
+			})
+	}
+	sort.SliceStable(gauges, func(i, j int) bool { return gauges[i].Name < gauges[j].Name })
+
+	c.mu.Lock()
+	c.gauges = gauges
+	c.mu.Unlock()
+	return nil
+}
+
+// Gauges returns the most recent inventory, grouped by gauge name
+func (c *Collector) Gauges() []Gauge {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Gauge(nil), c.gauges...)
+}
+
+// WriteText writes the most recent inventory in the Prometheus text exposition format
+func (c *Collector) WriteText(w io.Writer) error {
+	var b strings.Builder
+	name := ""
+	for _, g := range c.Gauges() {
+		if g.Name != name {
+			name = g.Name
+			fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, gaugeHelp[name], name)
+		}
+		b.WriteString(g.Name)
+		if len(g.Labels) > 0 {
+			b.WriteByte('{')
+			for i, l := range g.Labels {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				fmt.Fprintf(&b, "%s=\"%s\"", l.Name, escapeLabelValue(l.Value))
+			}
+			b.WriteByte('}')
+		}
+		fmt.Fprintf(&b, " %g\n", g.Value)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the most recent inventory so the Collector can be mounted as a scrape target
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteText(w)
+}
+
+// Close stops background refreshes, cancelling one in flight, and waits for them to finish
+func (c *Collector) Close() error {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		<-c.done
+	})
+	return nil
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
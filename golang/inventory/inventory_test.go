@@ -0,0 +1,104 @@
+package inventory_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/inventory"
+)
+
+var _ inventory.Source = (*client.GlueSchemaRegistryClient)(nil)
+
+func TestCollectorGauges(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withSource); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	if _, err := c.CreateSchema("SalesforceAuditJson", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	collector := inventory.NewCollector(c, inventory.WithRefreshInterval(time.Hour))
+	defer collector.Close()
+	if err := collector.Refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh inventory: %v", err)
+	}
+
+	var text strings.Builder
+	if err := collector.WriteText(&text); err != nil {
+		t.Fatalf("Failed to write inventory: %v", err)
+	}
+	expected := `# HELP glue_schema_compatibility Compatibility mode of a schema; 1 for the current mode.
+# TYPE glue_schema_compatibility gauge
+glue_schema_compatibility{schema="SalesforceAudit",mode="BACKWARD"} 1
+glue_schema_compatibility{schema="SalesforceAuditJson",mode="NONE"} 1
+# HELP glue_schema_versions_total Number of registered versions of a schema.
+# TYPE glue_schema_versions_total gauge
+glue_schema_versions_total{schema="SalesforceAudit"} 2
+glue_schema_versions_total{schema="SalesforceAuditJson"} 1
+`
+	if text.String() != expected {
+		t.Errorf("Inventory mismatch: expected\n%s\ngot\n%s", expected, text.String())
+	}
+}
+
+func TestCollectorClose(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	collector := inventory.NewCollector(c, inventory.WithRefreshInterval(time.Millisecond))
+	deadline := time.Now().Add(5 * time.Second)
+	for fake.Calls("ListSchemas") < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for background refreshes")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := collector.Close(); err != nil {
+		t.Fatalf("Failed to close collector: %v", err)
+	}
+	calls := fake.Calls("ListSchemas")
+	time.Sleep(20 * time.Millisecond)
+	if after := fake.Calls("ListSchemas"); after != calls {
+		t.Errorf("Expected no refreshes after Close, got %d more", after-calls)
+	}
+}
+
+func TestCollectorInvalidRefreshInterval(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	// Intervals of zero or less keep the default instead of making the ticker panic
+	for _, interval := range []time.Duration{0, -time.Second} {
+		calls := fake.Calls("ListSchemas")
+		collector := inventory.NewCollector(c, inventory.WithRefreshInterval(interval))
+		deadline := time.Now().Add(5 * time.Second)
+		for fake.Calls("ListSchemas") == calls {
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out waiting for the first refresh")
+			}
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+		if refreshes := fake.Calls("ListSchemas") - calls; refreshes != 1 {
+			t.Errorf("Refreshes mismatch for interval %v: expected 1, got %d", interval, refreshes)
+		}
+		if err := collector.Close(); err != nil {
+			t.Fatalf("Failed to close collector: %v", err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package kafka adapts the Avro serializer to Kafka producers and consumers using the
+// Glue wire format, in which every message value starts with a header naming the schema
+// version it was written with.
+//
+// Encoder has the Encode and Length methods of sarama.Encoder, so it can be set directly
+// as the Value or Key of a sarama.ProducerMessage without this package depending on
+// sarama. Values read from a sarama.ConsumerMessage are decoded with Codec.Decode.
+//
+// For confluent-kafka-go, TopicSerde provides the Serialize and Deserialize methods of its
+// serde interfaces, naming the schema after the topic.
+package kafka
+
+import (
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+// Codec encodes and decodes Kafka message values against one registry. It is safe for
+// concurrent use, and should be created once and shared so that compiled schemas are reused.
+type Codec struct {
+	registry   client.Registry
+	serializer *serializer.AvroSerializer
+}
+
+// NewCodec creates a Codec that serializes with s against the registry c. A nil s uses a
+// default-configured AvroSerializer.
+func NewCodec(c client.Registry, s *serializer.AvroSerializer) *Codec {
+	if s == nil {
+		s = serializer.NewAvroSerializer()
+	}
+	return &Codec{registry: c, serializer: s}
+}
+
+// Encoder serializes v against the latest version of schemaName, with the Glue
+// wire-format header, and returns it ready to be used as a message value. v may be any
+// value accepted by AvroSerializer.Serialize, such as a *model.SalesforceAudit. The value
+// is serialized immediately, so schema and encoding errors are returned here rather than
+// from the producer.
+func (k *Codec) Encoder(schemaName string, v interface{}) (*Encoder, error) {
+	data, err := k.serializer.SerializeWithHeader(k.registry, schemaName, v)
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder{data: data}, nil
+}
+
+// Decode deserializes a message value written with the Glue wire-format header into out,
+// which must be a non-nil pointer, using the schema version named by the header. With a
+// *client.GlueSchemaRegistryClient the header may name any version of schemaName, which
+// is fetched by its ID; other registries can decode the versions listed for
+// AvroSerializer.DeserializeParts.
+func (k *Codec) Decode(schemaName string, value []byte, out interface{}) error {
+	return k.serializer.DeserializeWithHeader(k.registry, schemaName, value, out)
+}
+
+// Encoder is a serialized message value. It implements sarama.Encoder.
+type Encoder struct {
+	data []byte
+}
+
+// Encode returns the serialized value, header included
+func (e *Encoder) Encode() ([]byte, error) {
+	return e.data, nil
+}
+
+// Length returns the length of the serialized value, header included
+func (e *Encoder) Length() int {
+	return len(e.data)
+}
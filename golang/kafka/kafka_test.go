@@ -0,0 +1,185 @@
+package kafka_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/kafka"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+// saramaEncoder has the method set of sarama.Encoder
+type saramaEncoder interface {
+	Encode() ([]byte, error)
+	Length() int
+}
+
+var _ saramaEncoder = (*kafka.Encoder)(nil)
+
+func TestCodec(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	created, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	codec := kafka.NewCodec(c, nil)
+	auditEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	var value saramaEncoder
+	value, err = codec.Encoder("SalesforceAudit", auditEvent)
+	if err != nil {
+		t.Fatalf("Failed to create encoder: %v", err)
+	}
+	data, err := value.Encode()
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if value.Length() != len(data) {
+		t.Errorf("Length mismatch: expected %d, got %d", len(data), value.Length())
+	}
+	header, body, err := serializer.NewAvroSerializer().SerializeParts(c, "SalesforceAudit", auditEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize parts: %v", err)
+	}
+	if !bytes.Equal(data, append(header, body...)) {
+		t.Errorf("Expected value to be the header of version %s followed by the Avro body", *created.SchemaVersionId)
+	}
+
+	var decoded model.SalesforceAudit
+	if err := codec.Decode("SalesforceAudit", data, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded != *auditEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *auditEvent, decoded)
+	}
+
+	if _, err := codec.Encoder("SalesforceAudit", map[string]interface{}{"eventId": "event-12345"}); err == nil {
+		t.Error("Expected error encoding a record that does not match the schema")
+	}
+	if err := codec.Decode("SalesforceAudit", data[serializer.HeaderSize:], &decoded); !errors.Is(err, serializer.ErrInvalidHeader) {
+		t.Errorf("Expected ErrInvalidHeader for a value without header, got %v", err)
+	}
+}
+
+func TestTopicSerde(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("audit-events-value", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	serde := kafka.NewTopicSerde(kafka.NewCodec(c, nil), func(topic string) string { return topic + "-value" })
+	auditEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+	value, err := serde.Serialize("audit-events", auditEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	var decoded model.SalesforceAudit
+	if err := serde.DeserializeInto("audit-events", value, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if decoded != *auditEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *auditEvent, decoded)
+	}
+	record, err := serde.Deserialize("audit-events", value)
+	if err != nil {
+		t.Fatalf("Failed to deserialize into a native record: %v", err)
+	}
+	if got := record.(map[string]interface{})["eventId"]; got != "event-12345" {
+		t.Errorf("eventId mismatch: expected event-12345, got %v", got)
+	}
+	if _, err := serde.Serialize("other-topic", auditEvent); err == nil {
+		t.Error("Expected error serializing to a topic without a schema")
+	}
+
+	// Tombstones on compacted topics pass through as nil values
+	if value, err := serde.Serialize("audit-events", nil); value != nil || err != nil {
+		t.Errorf("Expected nil value for a tombstone, got %v (error %v)", value, err)
+	}
+	if record, err := serde.Deserialize("audit-events", nil); record != nil || err != nil {
+		t.Errorf("Expected nil record for a tombstone, got %v (error %v)", record, err)
+	}
+	decoded = model.SalesforceAudit{EventID: "unchanged"}
+	if err := serde.DeserializeInto("audit-events", []byte{}, &decoded); err != nil || decoded.EventID != "unchanged" {
+		t.Errorf("Expected a tombstone to leave the target unchanged, got %+v (error %v)", decoded, err)
+	}
+}
+
+func TestNamingStrategies(t *testing.T) {
+	const recordName = "com.aws.glue.schema.registry.SalesforceAudit"
+	auditEvent := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	tests := []struct {
+		naming     kafka.NamingStrategy
+		schemaName string
+	}{
+		{nil, "audit-events"},
+		{kafka.TopicName, "audit-events"},
+		{kafka.RecordName, recordName},
+		{kafka.TopicRecordName, "audit-events-" + recordName},
+	}
+	for _, tt := range tests {
+		naming := tt.naming
+		if naming == nil {
+			naming = kafka.TopicName
+		}
+		schemaName, err := naming.SchemaName("audit-events", recordName)
+		if err != nil || schemaName != tt.schemaName {
+			t.Errorf("Schema name mismatch: expected %s, got %s (error %v)", tt.schemaName, schemaName, err)
+		}
+
+		c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+		if _, err := c.CreateSchema(tt.schemaName, "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+		serde := kafka.NewTopicSerdeWithNaming(kafka.NewCodec(c, nil), tt.naming)
+		value, err := serde.Serialize("audit-events", auditEvent)
+		if err != nil {
+			t.Fatalf("Failed to serialize with schema %s: %v", tt.schemaName, err)
+		}
+		var decoded model.SalesforceAudit
+		if err := serde.DeserializeInto("audit-events", value, &decoded); err != nil {
+			t.Fatalf("Failed to deserialize with schema %s: %v", tt.schemaName, err)
+		}
+		if decoded != *auditEvent {
+			t.Errorf("Event mismatch: expected %+v, got %+v", *auditEvent, decoded)
+		}
+		c.Close()
+	}
+
+	// A message passed by value is named by the AvroRecordName of its pointer type
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema(recordName, "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	byValue := kafka.NewTopicSerdeWithNaming(kafka.NewCodec(c, nil), kafka.RecordName)
+	if _, err := byValue.Serialize("audit-events", *auditEvent); err != nil {
+		t.Errorf("Failed to serialize a message passed by value: %v", err)
+	}
+
+	// Native records have no record type to name the schema after
+	serde := kafka.NewTopicSerdeWithNaming(kafka.NewCodec(client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry"), nil), kafka.RecordName)
+	if _, err := serde.Deserialize("audit-events", []byte{3, 0, 1}); err == nil {
+		t.Error("Expected error deserializing into a native record with RecordName")
+	}
+	if _, err := kafka.TopicRecordName.SchemaName("audit-events", ""); err == nil {
+		t.Error("Expected error from TopicRecordName without a record name")
+	}
+}
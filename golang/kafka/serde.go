@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NamingStrategy derives the name of the Glue schema of a message from the topic it is
+// produced to or consumed from and the name of its record, as the subject name strategies
+// of Confluent serializers do. recordName is empty when the record type is unknown, as when
+// TopicSerde.Deserialize decodes into a native record.
+type NamingStrategy interface {
+	SchemaName(topic, recordName string) (string, error)
+}
+
+// The naming strategies of Confluent serializers
+var (
+	// TopicName names the schema after the topic, ignoring the record
+	TopicName NamingStrategy = SchemaNameStrategy(TopicNameStrategy)
+	// RecordName names the schema after the record, so one topic can carry several record types
+	RecordName NamingStrategy = recordNameStrategy{}
+	// TopicRecordName names the schema "<topic>-<record name>"
+	TopicRecordName NamingStrategy = topicRecordNameStrategy{}
+)
+
+// RecordNamer is implemented by messages that know the fully qualified name of their Avro
+// record, such as *model.SalesforceAudit. Other messages are named after their Go type.
+type RecordNamer interface {
+	AvroRecordName() string
+}
+
+// SchemaNameStrategy derives the name of the Glue schema from the topic a message is
+// produced to or consumed from. It is a NamingStrategy that ignores the record.
+type SchemaNameStrategy func(topic string) string
+
+// SchemaName returns f(topic)
+func (f SchemaNameStrategy) SchemaName(topic, recordName string) (string, error) {
+	return f(topic), nil
+}
+
+// TopicNameStrategy uses the topic name as the schema name, as the AWS Glue Schema
+// Registry serializers for Java do by default
+func TopicNameStrategy(topic string) string {
+	return topic
+}
+
+type recordNameStrategy struct{}
+
+func (recordNameStrategy) SchemaName(topic, recordName string) (string, error) {
+	if recordName == "" {
+		return "", fmt.Errorf("record name strategy needs the record type of messages on topic %s", topic)
+	}
+	return recordName, nil
+}
+
+type topicRecordNameStrategy struct{}
+
+func (topicRecordNameStrategy) SchemaName(topic, recordName string) (string, error) {
+	if recordName == "" {
+		return "", fmt.Errorf("topic record name strategy needs the record type of messages on topic %s", topic)
+	}
+	return topic + "-" + recordName, nil
+}
+
+// recordName returns the record name of msg: its AvroRecordName if it or a pointer to it
+// is a RecordNamer, otherwise the name of its type with pointers removed. The pointer is
+// checked because AvroRecordName usually has a pointer receiver, as on
+// *model.SalesforceAudit, and messages are often passed by value.
+func recordName(msg interface{}) string {
+	if namer, ok := msg.(RecordNamer); ok {
+		return namer.AvroRecordName()
+	}
+	t := reflect.TypeOf(msg)
+	if t != nil && t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(reflect.TypeOf((*RecordNamer)(nil)).Elem()) {
+		ptr := reflect.New(t)
+		ptr.Elem().Set(reflect.ValueOf(msg))
+		return ptr.Interface().(RecordNamer).AvroRecordName()
+	}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
+
+// TopicSerde serializes and deserializes message values with the Glue wire format,
+// resolving the schema from the topic. Its Serialize, Deserialize, DeserializeInto and
+// Close methods have the signatures of the confluent-kafka-go serde.Serializer and
+// serde.Deserializer interfaces, so it can replace a Confluent Avro serde in producer and
+// consumer code; it has no Configure methods because the schema registry is the Glue
+// registry of its Codec.
+//
+// A nil message serializes to a nil value and a nil or empty value deserializes to nil,
+// so tombstones on compacted topics pass through unchanged.
+type TopicSerde struct {
+	codec  *Codec
+	naming NamingStrategy
+}
+
+// NewTopicSerde creates a TopicSerde that serializes with codec and resolves schema names
+// with strategy. A nil strategy uses TopicNameStrategy.
+func NewTopicSerde(codec *Codec, strategy SchemaNameStrategy) *TopicSerde {
+	if strategy == nil {
+		return NewTopicSerdeWithNaming(codec, nil)
+	}
+	return NewTopicSerdeWithNaming(codec, strategy)
+}
+
+// NewTopicSerdeWithNaming creates a TopicSerde that serializes with codec and resolves
+// schema names from the topic and the message's record name with naming, such as
+// RecordName. A nil naming uses TopicName.
+func NewTopicSerdeWithNaming(codec *Codec, naming NamingStrategy) *TopicSerde {
+	if naming == nil {
+		naming = TopicName
+	}
+	return &TopicSerde{codec: codec, naming: naming}
+}
+
+// Serialize serializes msg against the latest version of the topic's schema, with the
+// Glue wire-format header. msg may be any value accepted by Codec.Encoder; nil gives a
+// nil value for a tombstone.
+func (s *TopicSerde) Serialize(topic string, msg interface{}) ([]byte, error) {
+	if msg == nil {
+		return nil, nil
+	}
+	schemaName, err := s.naming.SchemaName(topic, recordName(msg))
+	if err != nil {
+		return nil, err
+	}
+	return s.codec.serializer.SerializeWithHeader(s.codec.registry, schemaName, msg)
+}
+
+// Deserialize deserializes a value of the topic into a native record, a
+// map[string]interface{} in goavro's native form. A tombstone gives nil. The record type
+// is not known, so record-based naming strategies fail; use DeserializeInto with them.
+func (s *TopicSerde) Deserialize(topic string, payload []byte) (interface{}, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+	schemaName, err := s.naming.SchemaName(topic, "")
+	if err != nil {
+		return nil, err
+	}
+	var record map[string]interface{}
+	if err := s.codec.Decode(schemaName, payload, &record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// DeserializeInto deserializes a value of the topic into msg, as Codec.Decode does. The
+// record name passed to the naming strategy is that of msg. A tombstone leaves msg
+// unchanged.
+func (s *TopicSerde) DeserializeInto(topic string, payload []byte, msg interface{}) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	schemaName, err := s.naming.SchemaName(topic, recordName(msg))
+	if err != nil {
+		return err
+	}
+	return s.codec.Decode(schemaName, payload, msg)
+}
+
+// Close does nothing; the Codec's registry client is owned by the caller
+func (s *TopicSerde) Close() error {
+	return nil
+}
@@ -1,5 +1,11 @@
 package model
 
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
 // SalesforceAudit represents a Salesforce audit event
 // Maps to the SalesforceAudit Avro/JSON schema
 type SalesforceAudit struct {
@@ -9,7 +15,16 @@ type SalesforceAudit struct {
 	EventDetails string `json:"eventDetails" avro:"eventDetails"`
 }
 
-// ToMap converts SalesforceAudit to a map
+// AvroRecordName returns the fully qualified name of the SalesforceAudit Avro record, as
+// the record-based naming strategies of package kafka expect
+func (s *SalesforceAudit) AvroRecordName() string {
+	return "com.aws.glue.schema.registry.SalesforceAudit"
+}
+
+// ToMap converts SalesforceAudit to a map of plain values. AvroSerializer wraps the
+// values of nullable union fields, such as an optional eventDetails, as the schema requires.
+// The timestamp is an int64 of epoch milliseconds, which goavro encodes both as a plain long
+// and as a long with logical type timestamp-millis.
 func (s *SalesforceAudit) ToMap() map[string]interface{} {
 	return map[string]interface{}{
 		"eventId":      s.EventID,
@@ -19,19 +34,75 @@ func (s *SalesforceAudit) ToMap() map[string]interface{} {
 	}
 }
 
-// FromMap creates SalesforceAudit from a map
+// FromMap creates SalesforceAudit from a map.
+// Values of nullable Avro union fields may arrive either as nil, when the field holds
+// its null default, or wrapped as map[string]interface{}{"<type>": value}; both forms
+// are accepted and nil leaves the field at its zero value.
+//
+// The timestamp may be an int64 as decoded from an Avro long, a time.Time as decoded from a
+// long with logical type timestamp-millis, an int or int32, a float64 as produced by
+// encoding/json, or a json.Number. A value that is not a whole number within the range of
+// int64 is ignored.
 func (s *SalesforceAudit) FromMap(data map[string]interface{}) {
-	if val, ok := data["eventId"].(string); ok {
+	if val, ok := unwrapUnion(data["eventId"]).(string); ok {
 		s.EventID = val
 	}
-	if val, ok := data["eventName"].(string); ok {
+	if val, ok := unwrapUnion(data["eventName"]).(string); ok {
 		s.EventName = val
 	}
-	if val, ok := data["timestamp"].(int64); ok {
+	if val, ok := toEpochMillis(unwrapUnion(data["timestamp"])); ok {
 		s.Timestamp = val
 	}
-	if val, ok := data["eventDetails"].(string); ok {
+	if val, ok := unwrapUnion(data["eventDetails"]).(string); ok {
 		s.EventDetails = val
 	}
 }
 
+// unwrapUnion returns the value held by a goavro union datum, which is decoded as a
+// single-entry map keyed by the branch type name. Other values are returned unchanged.
+func unwrapUnion(val interface{}) interface{} {
+	if wrapped, ok := val.(map[string]interface{}); ok && len(wrapped) == 1 {
+		for _, v := range wrapped {
+			return v
+		}
+	}
+	return val
+}
+
+// toEpochMillis converts a decoded timestamp to milliseconds since the epoch, as toInt64
+// does for numbers
+func toEpochMillis(val interface{}) (int64, bool) {
+	if t, ok := val.(time.Time); ok {
+		return t.UnixMilli(), true
+	}
+	return toInt64(val)
+}
+
+// toInt64 converts a decoded number to int64, reporting false if it is not a whole
+// number that int64 can represent exactly
+func toInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case float64:
+		// -2^63 is exact in float64, but 2^63 is one beyond the largest int64
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, true
+		}
+		if f, err := v.Float64(); err == nil {
+			return toInt64(f)
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,124 @@
+package model_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/linkedin/goavro/v2"
+)
+
+const nullableAuditSchema = `{
+	"type": "record",
+	"name": "SalesforceAudit",
+	"namespace": "com.aws.glue.schema.registry",
+	"fields": [
+		{"name": "eventId", "type": "string"},
+		{"name": "eventName", "type": "string"},
+		{"name": "timestamp", "type": "long"},
+		{"name": "eventDetails", "type": ["null", "string"], "default": null}
+	]
+}`
+
+func TestFromMapNullableDefault(t *testing.T) {
+	codec, err := goavro.NewCodec(nullableAuditSchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		eventDetails interface{}
+		omit         bool
+		expected     string
+	}{
+		{name: "present", eventDetails: goavro.Union("string", "User logged in successfully"), expected: "User logged in successfully"},
+		{name: "explicit null", eventDetails: nil, expected: ""},
+		{name: "absent", omit: true, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := map[string]interface{}{
+				"eventId":   "event-12345",
+				"eventName": "UserLogin",
+				"timestamp": int64(1704067200000),
+			}
+			if !tt.omit {
+				record["eventDetails"] = tt.eventDetails
+			}
+
+			data, err := codec.BinaryFromNative(nil, record)
+			if err != nil {
+				t.Fatalf("Failed to encode record: %v", err)
+			}
+			datum, _, err := codec.NativeFromBinary(data)
+			if err != nil {
+				t.Fatalf("Failed to decode record: %v", err)
+			}
+
+			audit := &model.SalesforceAudit{}
+			audit.FromMap(datum.(map[string]interface{}))
+
+			if audit.EventID != "event-12345" {
+				t.Errorf("EventID mismatch: expected event-12345, got %s", audit.EventID)
+			}
+			if audit.Timestamp != 1704067200000 {
+				t.Errorf("Timestamp mismatch: expected 1704067200000, got %d", audit.Timestamp)
+			}
+			if audit.EventDetails != tt.expected {
+				t.Errorf("EventDetails mismatch: expected %q, got %q", tt.expected, audit.EventDetails)
+			}
+		})
+	}
+}
+
+func TestFromMapTimestampTypes(t *testing.T) {
+	const expected int64 = 1704067200000
+	tests := []struct {
+		name      string
+		timestamp interface{}
+		expected  int64
+	}{
+		{name: "int64", timestamp: int64(1704067200000), expected: expected},
+		{name: "int", timestamp: int(1704067200000), expected: expected},
+		{name: "int32", timestamp: int32(1704067), expected: 1704067},
+		{name: "float64", timestamp: float64(1704067200000), expected: expected},
+		{name: "json.Number", timestamp: json.Number("1704067200000"), expected: expected},
+		{name: "json.Number exponent", timestamp: json.Number("1.7040672e12"), expected: expected},
+		{name: "union", timestamp: goavro.Union("long", int64(1704067200000)), expected: expected},
+		{name: "time.Time", timestamp: time.UnixMilli(1704067200000).UTC(), expected: expected},
+		{name: "timestamp-millis union", timestamp: goavro.Union("long.timestamp-millis", time.UnixMilli(1704067200000)), expected: expected},
+		{name: "fractional float64", timestamp: 1704067200000.5, expected: 0},
+		{name: "float64 out of range", timestamp: math.MaxFloat64, expected: 0},
+		{name: "invalid json.Number", timestamp: json.Number("soon"), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &model.SalesforceAudit{}
+			audit.FromMap(map[string]interface{}{"timestamp": tt.timestamp})
+			if audit.Timestamp != tt.expected {
+				t.Errorf("Timestamp mismatch: expected %d, got %d", tt.expected, audit.Timestamp)
+			}
+		})
+	}
+
+	// A round trip through encoding/json decodes the timestamp as float64
+	original := model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: expected, EventDetails: "User logged in successfully"}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	decoded := model.SalesforceAudit{}
+	decoded.FromMap(record)
+	if decoded != original {
+		t.Errorf("Round trip mismatch: expected %+v, got %+v", original, decoded)
+	}
+}
@@ -0,0 +1,242 @@
+// Package registrytest provides an in-memory schema registry for testing code that uses
+// the serializers, end to end and without AWS.
+//
+// InMemoryClient implements client.Registry with the version numbering of Glue: a schema
+// is created with version 1, every new definition gets the next number, and registering a
+// definition that is already registered returns its existing version. New versions of
+// Avro schemas are checked with package compat under the schema's compatibility mode.
+package registrytest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/compat"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// RegistryName is the registry name InMemoryClient reports in its results
+const RegistryName = "in-memory"
+
+// ErrIncompatibleSchema is matched by errors from RegisterSchemaVersion when the new
+// definition breaks the schema's compatibility mode, or the mode is DISABLED
+var ErrIncompatibleSchema = errors.New("schema definition is incompatible")
+
+// InMemoryClient is an in-memory client.Registry. Errors match client.IsNotFound and
+// client.IsAlreadyExists as those of *client.GlueSchemaRegistryClient do. It is safe for
+// concurrent use.
+type InMemoryClient struct {
+	mu      sync.Mutex
+	schemas map[string]*schema
+	nextID  int
+}
+
+type schema struct {
+	name          string
+	dataFormat    string
+	compatibility string
+	versions      []*glue.GetSchemaVersionOutput
+}
+
+func (s *schema) latest() *glue.GetSchemaVersionOutput {
+	return s.versions[len(s.versions)-1]
+}
+
+var _ client.Registry = (*InMemoryClient)(nil)
+
+// NewInMemoryClient returns an empty InMemoryClient
+func NewInMemoryClient() *InMemoryClient {
+	return &InMemoryClient{schemas: make(map[string]*schema)}
+}
+
+// CreateSchema creates a schema whose first version has schemaDefinition
+func (m *InMemoryClient) CreateSchema(schemaName string, dataFormat client.DataFormat, schemaDefinition string, compatibility client.Compatibility) (*glue.CreateSchemaOutput, error) {
+	switch dataFormat {
+	case client.DataFormatAvro, client.DataFormatJSON, client.DataFormatProtobuf:
+	default:
+		return nil, &client.SchemaRegistryException{
+			Message: fmt.Sprintf("Failed to create schema: %s", schemaName),
+			Err:     fmt.Errorf("%w: %q", client.ErrInvalidDataFormat, dataFormat),
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.schemas[schemaName]; exists {
+		return nil, failure(fmt.Sprintf("Failed to create schema: %s", schemaName),
+			awserr.New(glue.ErrCodeAlreadyExistsException, fmt.Sprintf("Schema already exists. SchemaName: %s", schemaName), nil))
+	}
+
+	if compatibility == "" {
+		// Glue's default
+		compatibility = client.CompatibilityBackward
+	}
+	s := &schema{name: schemaName, dataFormat: string(dataFormat), compatibility: string(compatibility)}
+	v := m.addVersion(s, schemaDefinition)
+	m.schemas[schemaName] = s
+	return &glue.CreateSchemaOutput{
+		Compatibility:       aws.String(s.compatibility),
+		DataFormat:          aws.String(s.dataFormat),
+		LatestSchemaVersion: v.VersionNumber,
+		NextSchemaVersion:   aws.Int64(aws.Int64Value(v.VersionNumber) + 1),
+		RegistryName:        aws.String(RegistryName),
+		SchemaArn:           v.SchemaArn,
+		SchemaName:          aws.String(schemaName),
+		SchemaStatus:        aws.String(glue.SchemaStatusAvailable),
+		SchemaVersionId:     v.SchemaVersionId,
+		SchemaVersionStatus: v.Status,
+	}, nil
+}
+
+// GetSchema gets a schema by name
+func (m *InMemoryClient) GetSchema(schemaName string) (*glue.GetSchemaOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.lookup(schemaName, fmt.Sprintf("Failed to get schema: %s", schemaName))
+	if err != nil {
+		return nil, err
+	}
+	latest := s.latest()
+	return &glue.GetSchemaOutput{
+		Compatibility:       aws.String(s.compatibility),
+		DataFormat:          aws.String(s.dataFormat),
+		LatestSchemaVersion: latest.VersionNumber,
+		NextSchemaVersion:   aws.Int64(aws.Int64Value(latest.VersionNumber) + 1),
+		RegistryName:        aws.String(RegistryName),
+		SchemaArn:           latest.SchemaArn,
+		SchemaName:          aws.String(schemaName),
+		SchemaStatus:        aws.String(glue.SchemaStatusAvailable),
+	}, nil
+}
+
+// GetDataFormat returns the data format of a schema
+func (m *InMemoryClient) GetDataFormat(schemaName string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.lookup(schemaName, fmt.Sprintf("Failed to get schema: %s", schemaName))
+	if err != nil {
+		return "", err
+	}
+	return s.dataFormat, nil
+}
+
+// GetSchemaVersion gets a specific version of a schema
+func (m *InMemoryClient) GetSchemaVersion(schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	message := fmt.Sprintf("Failed to get schema version: %s (version %d)", schemaName, versionNumber)
+	s, err := m.lookup(schemaName, message)
+	if err != nil {
+		return nil, err
+	}
+	if versionNumber < 1 || versionNumber > int64(len(s.versions)) {
+		return nil, failure(message, awserr.New(glue.ErrCodeEntityNotFoundException,
+			fmt.Sprintf("Schema version is not found. SchemaName: %s, VersionNumber: %d", schemaName, versionNumber), nil))
+	}
+	v := *s.versions[versionNumber-1]
+	return &v, nil
+}
+
+// RegisterSchemaVersion registers schemaDefinition as the next version of a schema, or
+// returns the version already registered with exactly this definition. A new Avro
+// definition that breaks the schema's compatibility mode is rejected with an error
+// matching ErrIncompatibleSchema.
+func (m *InMemoryClient) RegisterSchemaVersion(schemaName, schemaDefinition string) (*glue.RegisterSchemaVersionOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	message := fmt.Sprintf("Failed to register schema version: %s", schemaName)
+	s, err := m.lookup(schemaName, message)
+	if err != nil {
+		return nil, err
+	}
+
+	v := s.find(schemaDefinition)
+	if v == nil {
+		if err := s.checkCompatible(schemaDefinition); err != nil {
+			return nil, &client.SchemaRegistryException{Message: message, Err: err}
+		}
+		v = m.addVersion(s, schemaDefinition)
+	}
+	return &glue.RegisterSchemaVersionOutput{
+		SchemaVersionId: v.SchemaVersionId,
+		Status:          v.Status,
+		VersionNumber:   v.VersionNumber,
+	}, nil
+}
+
+// lookup returns the named schema. The caller must hold m.mu.
+func (m *InMemoryClient) lookup(schemaName, message string) (*schema, error) {
+	s, ok := m.schemas[schemaName]
+	if !ok {
+		return nil, failure(message, awserr.New(glue.ErrCodeEntityNotFoundException,
+			fmt.Sprintf("Schema is not found. RegistryName: %s, SchemaName: %s", RegistryName, schemaName), nil))
+	}
+	return s, nil
+}
+
+// addVersion appends definition to s as its next version. The caller must hold m.mu.
+func (m *InMemoryClient) addVersion(s *schema, definition string) *glue.GetSchemaVersionOutput {
+	m.nextID++
+	v := &glue.GetSchemaVersionOutput{
+		DataFormat:       aws.String(s.dataFormat),
+		SchemaArn:        aws.String(fmt.Sprintf("arn:aws:glue:us-east-1:123456789012:schema/%s/%s", RegistryName, s.name)),
+		SchemaDefinition: aws.String(definition),
+		SchemaVersionId:  aws.String(fmt.Sprintf("00000000-0000-4000-8000-%012d", m.nextID)),
+		Status:           aws.String(glue.SchemaVersionStatusAvailable),
+		VersionNumber:    aws.Int64(int64(len(s.versions) + 1)),
+	}
+	s.versions = append(s.versions, v)
+	return v
+}
+
+// find returns the version of s registered with exactly definition, if any
+func (s *schema) find(definition string) *glue.GetSchemaVersionOutput {
+	for _, v := range s.versions {
+		if aws.StringValue(v.SchemaDefinition) == definition {
+			return v
+		}
+	}
+	return nil
+}
+
+// checkCompatible reports an error matching ErrIncompatibleSchema if definition cannot be
+// registered as the next version of s. Only Avro definitions are checked against earlier
+// versions: the latest one, or all of them for the _ALL modes.
+func (s *schema) checkCompatible(definition string) error {
+	if s.compatibility == string(client.CompatibilityDisabled) {
+		return fmt.Errorf("%w: compatibility mode DISABLED allows no new versions", ErrIncompatibleSchema)
+	}
+	if s.dataFormat != string(client.DataFormatAvro) {
+		return nil
+	}
+
+	previous := s.versions[len(s.versions)-1:]
+	if strings.HasSuffix(s.compatibility, "_ALL") {
+		previous = s.versions
+	}
+	for _, v := range previous {
+		changes, err := compat.Check(aws.StringValue(v.SchemaDefinition), definition, s.compatibility)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrIncompatibleSchema, err)
+		}
+		if len(changes) > 0 {
+			reasons := make([]string, len(changes))
+			for i, change := range changes {
+				reasons[i] = change.String()
+			}
+			return fmt.Errorf("%w with version %d under %s: %s", ErrIncompatibleSchema,
+				aws.Int64Value(v.VersionNumber), s.compatibility, strings.Join(reasons, "; "))
+		}
+	}
+	return nil
+}
+
+// failure wraps an AWS error the way GlueSchemaRegistryClient does
+func failure(message string, err awserr.Error) error {
+	return &client.SchemaRegistryException{Message: message, Err: err, ErrorCode: err.Code()}
+}
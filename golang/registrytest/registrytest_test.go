@@ -0,0 +1,90 @@
+package registrytest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/registrytest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestInMemoryClientSerde(t *testing.T) {
+	c := registrytest.NewInMemoryClient()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	s := serializer.NewAvroSerializer()
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in"}
+	data, err := s.SerializeWithHeader(c, "SalesforceAudit", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	var decoded model.SalesforceAudit
+	if err := s.DeserializeWithHeader(c, "SalesforceAudit", data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if decoded != *event {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *event, decoded)
+	}
+}
+
+func TestInMemoryClientVersions(t *testing.T) {
+	c := registrytest.NewInMemoryClient()
+	if _, err := c.GetSchema("SalesforceAudit"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); !client.IsAlreadyExists(err) {
+		t.Errorf("Expected an already exists error, got %v", err)
+	}
+
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "source", "type": "string", "default": "salesforce"},`, 1)
+	for i := 0; i < 2; i++ {
+		registered, err := c.RegisterSchemaVersion("SalesforceAudit", withSource)
+		if err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+		if versionNumber := aws.Int64Value(registered.VersionNumber); versionNumber != 2 {
+			t.Errorf("VersionNumber mismatch: expected 2, got %d", versionNumber)
+		}
+	}
+	schema, err := c.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if latest := aws.Int64Value(schema.LatestSchemaVersion); latest != 2 {
+		t.Errorf("LatestSchemaVersion mismatch: expected 2, got %d", latest)
+	}
+	version, err := c.GetSchemaVersion("SalesforceAudit", 1)
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if definition := aws.StringValue(version.SchemaDefinition); definition != gluetest.SalesforceAuditAvroSchema {
+		t.Errorf("Definition mismatch: expected %s, got %s", gluetest.SalesforceAuditAvroSchema, definition)
+	}
+	if _, err := c.GetSchemaVersion("SalesforceAudit", 3); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error for version 3, got %v", err)
+	}
+
+	// A field added without a default cannot be read from old data
+	withRegion := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"fields": [`,
+		`"fields": [{"name": "region", "type": "string"},`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withRegion); !errors.Is(err, registrytest.ErrIncompatibleSchema) {
+		t.Errorf("Expected ErrIncompatibleSchema, got %v", err)
+	}
+	if _, err := c.CreateSchema("Unchecked", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.RegisterSchemaVersion("Unchecked", withRegion); err != nil {
+		t.Errorf("Expected compatibility NONE to accept any definition, got %v", err)
+	}
+}
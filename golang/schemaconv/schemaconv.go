@@ -0,0 +1,454 @@
+// Package schemaconv converts schema definitions between Avro and JSON Schema so that a
+// single hand-written definition can back both the Avro and JSON serialization paths.
+//
+// The conversions cover records/objects, primitives, enums, arrays, maps and unions.
+// They are lossy in both directions:
+//   - Avro namespaces, aliases, field order attributes and logical types are dropped.
+//   - Avro int and long both become JSON "integer", which converts back to long; float and
+//     double both become "number", which converts back to double.
+//   - Avro bytes and fixed become JSON "string", which converts back to string.
+//   - JSON Schema validation keywords (format, pattern, minimum, maxLength, ...) have no
+//     Avro equivalent and are ignored, as are $ref and allOf.
+//   - Optional JSON Schema properties become Avro unions with null and a null default.
+package schemaconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect emitted by AvroToJSONSchema
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// AvroToJSONSchema converts an Avro schema definition to an equivalent JSON Schema (draft-07).
+// Records become objects that disallow additional properties, fields without a default are
+// required, and unions become anyOf.
+func AvroToJSONSchema(avroDef string) (string, error) {
+	var avro interface{}
+	if err := json.Unmarshal([]byte(avroDef), &avro); err != nil {
+		return "", fmt.Errorf("failed to parse Avro schema: %w", err)
+	}
+
+	conv := &avroConverter{named: make(map[string]interface{}), inProgress: make(map[string]bool)}
+	jsonSchema, err := conv.convert(avro, "")
+	if err != nil {
+		return "", err
+	}
+	jsonSchema["$schema"] = jsonSchemaDraft
+
+	out, err := json.MarshalIndent(jsonSchema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON Schema: %w", err)
+	}
+	return string(out), nil
+}
+
+// avroConverter tracks named Avro types so later references to them can be resolved
+type avroConverter struct {
+	named      map[string]interface{}
+	inProgress map[string]bool
+}
+
+func (a *avroConverter) convert(avro interface{}, namespace string) (map[string]interface{}, error) {
+	switch t := avro.(type) {
+	case string:
+		return a.convertName(t, namespace)
+	case []interface{}:
+		branches := make([]interface{}, 0, len(t))
+		for _, branch := range t {
+			converted, err := a.convert(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, converted)
+		}
+		return map[string]interface{}{"anyOf": branches}, nil
+	case map[string]interface{}:
+		return a.convertComplex(t, namespace)
+	default:
+		return nil, fmt.Errorf("unsupported Avro schema: %v", avro)
+	}
+}
+
+func (a *avroConverter) convertName(name, namespace string) (map[string]interface{}, error) {
+	switch name {
+	case "null", "boolean", "string":
+		return map[string]interface{}{"type": name}, nil
+	case "int", "long":
+		return map[string]interface{}{"type": "integer"}, nil
+	case "float", "double":
+		return map[string]interface{}{"type": "number"}, nil
+	case "bytes":
+		return map[string]interface{}{"type": "string"}, nil
+	}
+
+	for _, candidate := range []string{qualify(name, namespace), name} {
+		if a.inProgress[candidate] {
+			return nil, fmt.Errorf("recursive Avro type %s cannot be expressed in JSON Schema", candidate)
+		}
+		if def, ok := a.named[candidate]; ok {
+			return a.convert(def, namespace)
+		}
+	}
+	return nil, fmt.Errorf("unknown Avro type: %s", name)
+}
+
+func (a *avroConverter) convertComplex(avro map[string]interface{}, namespace string) (map[string]interface{}, error) {
+	typeName, _ := avro["type"].(string)
+	if ns, ok := avro["namespace"].(string); ok {
+		namespace = ns
+	}
+	name, _ := avro["name"].(string)
+
+	switch typeName {
+	case "record", "error":
+		// Mark the record while its fields are converted so that self-references are rejected
+		qualified := qualify(name, namespace)
+		a.inProgress[qualified] = true
+
+		fields, _ := avro["fields"].([]interface{})
+		properties := make(map[string]interface{}, len(fields))
+		required := []interface{}{}
+		for _, f := range fields {
+			field, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid field in record %s", name)
+			}
+			fieldName, _ := field["name"].(string)
+			property, err := a.convert(field["type"], namespace)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", fieldName, err)
+			}
+			if doc, ok := field["doc"].(string); ok {
+				property["description"] = doc
+			}
+			if def, ok := field["default"]; ok {
+				property["default"] = def
+			} else {
+				required = append(required, fieldName)
+			}
+			properties[fieldName] = property
+		}
+
+		delete(a.inProgress, qualified)
+		a.named[qualified] = avro
+		out := map[string]interface{}{
+			"title":                name,
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+		if doc, ok := avro["doc"].(string); ok {
+			out["description"] = doc
+		}
+		return out, nil
+	case "enum":
+		a.named[qualify(name, namespace)] = avro
+		return map[string]interface{}{"type": "string", "enum": avro["symbols"]}, nil
+	case "fixed":
+		a.named[qualify(name, namespace)] = avro
+		return map[string]interface{}{"type": "string"}, nil
+	case "array":
+		items, err := a.convert(avro["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case "map":
+		values, err := a.convert(avro["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	default:
+		// Primitive written in its long form, possibly with a logical type
+		return a.convert(typeName, namespace)
+	}
+}
+
+func qualify(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// JSONSchemaToAvro converts a JSON Schema definition to an equivalent Avro schema. The root
+// must be an object; its title becomes the record name. Optional properties become unions
+// with null that default to null. Property names must be valid Avro field names: an ASCII
+// letter or underscore followed by ASCII letters, digits and underscores.
+func JSONSchemaToAvro(jsonDef string) (string, error) {
+	var jsonSchema map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonDef), &jsonSchema); err != nil {
+		return "", fmt.Errorf("failed to parse JSON Schema: %w", err)
+	}
+	if _, ok := jsonSchema["properties"]; !ok {
+		return "", fmt.Errorf("JSON Schema root must be an object with properties")
+	}
+
+	name, _ := jsonSchema["title"].(string)
+	if name == "" {
+		name = "Record"
+	}
+
+	conv := &jsonConverter{names: make(map[string]int)}
+	avro, err := conv.convert(jsonSchema, name)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.MarshalIndent(avro, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Avro schema: %w", err)
+	}
+	if _, err := goavro.NewCodec(string(out)); err != nil {
+		return "", fmt.Errorf("converted schema is not valid Avro: %w", err)
+	}
+	return string(out), nil
+}
+
+// jsonConverter generates unique Avro names for the records and enums it creates
+type jsonConverter struct {
+	names map[string]int
+}
+
+func (j *jsonConverter) uniqueName(hint string) string {
+	name := avroName(hint)
+	j.names[name]++
+	if n := j.names[name]; n > 1 {
+		return fmt.Sprintf("%s%d", name, n)
+	}
+	return name
+}
+
+func (j *jsonConverter) convert(jsonSchema map[string]interface{}, nameHint string) (interface{}, error) {
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		if branches, ok := jsonSchema[keyword].([]interface{}); ok {
+			return j.convertUnion(branches, nameHint)
+		}
+	}
+
+	switch t := jsonSchema["type"].(type) {
+	case []interface{}:
+		branches := make([]interface{}, 0, len(t))
+		for _, typeName := range t {
+			branch := make(map[string]interface{}, len(jsonSchema))
+			for k, v := range jsonSchema {
+				branch[k] = v
+			}
+			branch["type"] = typeName
+			branches = append(branches, branch)
+		}
+		return j.convertUnion(branches, nameHint)
+	case string:
+		return j.convertType(t, jsonSchema, nameHint)
+	case nil:
+		if _, ok := jsonSchema["properties"]; ok {
+			return j.convertType("object", jsonSchema, nameHint)
+		}
+		if _, ok := jsonSchema["enum"]; ok {
+			return j.convertType("string", jsonSchema, nameHint)
+		}
+	}
+	return nil, fmt.Errorf("unsupported JSON Schema for %s: %v", nameHint, jsonSchema)
+}
+
+func (j *jsonConverter) convertUnion(branches []interface{}, nameHint string) (interface{}, error) {
+	var union []interface{}
+	hasNull := false
+	for _, b := range branches {
+		branch, ok := b.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid union branch for %s", nameHint)
+		}
+		converted, err := j.convert(branch, nameHint)
+		if err != nil {
+			return nil, err
+		}
+		if converted == "null" {
+			hasNull = true
+			continue
+		}
+		union = append(union, converted)
+	}
+	// Avro requires the default's branch to come first, so null leads nullable unions
+	if hasNull {
+		union = append([]interface{}{"null"}, union...)
+	}
+	if len(union) == 1 {
+		return union[0], nil
+	}
+	return union, nil
+}
+
+func (j *jsonConverter) convertType(typeName string, jsonSchema map[string]interface{}, nameHint string) (interface{}, error) {
+	switch typeName {
+	case "null", "boolean":
+		return typeName, nil
+	case "integer":
+		return "long", nil
+	case "number":
+		return "double", nil
+	case "string":
+		if symbols, ok := jsonSchema["enum"].([]interface{}); ok {
+			return map[string]interface{}{
+				"type":    "enum",
+				"name":    j.uniqueName(nameHint),
+				"symbols": symbols,
+			}, nil
+		}
+		return "string", nil
+	case "array":
+		items, ok := jsonSchema["items"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("array %s must declare items", nameHint)
+		}
+		converted, err := j.convert(items, nameHint+"Item")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": converted}, nil
+	case "object":
+		if _, ok := jsonSchema["properties"]; ok {
+			return j.convertRecord(jsonSchema, nameHint)
+		}
+		values, ok := jsonSchema["additionalProperties"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("object %s must declare properties or an additionalProperties schema", nameHint)
+		}
+		converted, err := j.convert(values, nameHint+"Value")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "map", "values": converted}, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON Schema type %q for %s", typeName, nameHint)
+	}
+}
+
+func (j *jsonConverter) convertRecord(jsonSchema map[string]interface{}, nameHint string) (interface{}, error) {
+	properties, _ := jsonSchema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := jsonSchema["required"].([]interface{}); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	record := map[string]interface{}{
+		"type": "record",
+		"name": j.uniqueName(nameHint),
+	}
+	if description, ok := jsonSchema["description"].(string); ok {
+		record["doc"] = description
+	}
+
+	// JSON object properties are unordered; sort them so the output is deterministic,
+	// listing required properties in their declared order first
+	var order []string
+	if list, ok := jsonSchema["required"].([]interface{}); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok {
+				if _, defined := properties[name]; defined {
+					order = append(order, name)
+				}
+			}
+		}
+	}
+	var optional []string
+	for name := range properties {
+		if !required[name] {
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(optional)
+	order = append(order, optional...)
+
+	fields := make([]interface{}, 0, len(order))
+	for _, name := range order {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid property %s", name)
+		}
+		if !validAvroName(name) {
+			return nil, fmt.Errorf("property %q is not a valid Avro field name", name)
+		}
+		fieldType, err := j.convert(property, name)
+		if err != nil {
+			return nil, err
+		}
+
+		field := map[string]interface{}{"name": name, "type": fieldType}
+		if description, ok := property["description"].(string); ok {
+			field["doc"] = description
+		}
+		if required[name] {
+			if def, ok := property["default"]; ok {
+				field["default"] = def
+			}
+		} else {
+			field["type"] = nullable(fieldType)
+			field["default"] = nil
+		}
+		fields = append(fields, field)
+	}
+	record["fields"] = fields
+	return record, nil
+}
+
+// nullable returns avroType as a union whose first branch is null
+func nullable(avroType interface{}) interface{} {
+	if union, ok := avroType.([]interface{}); ok {
+		if len(union) > 0 && union[0] == "null" {
+			return union
+		}
+		return append([]interface{}{"null"}, union...)
+	}
+	return []interface{}{"null", avroType}
+}
+
+// validAvroName reports whether name is a valid Avro name: an ASCII letter or underscore
+// followed by ASCII letters, digits and underscores
+func validAvroName(name string) bool {
+	for i, r := range name {
+		if !isAvroNameRune(r) || (i == 0 && unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// isAvroNameRune reports whether r may appear in an Avro name. Avro names are ASCII only.
+func isAvroNameRune(r rune) bool {
+	return r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// avroName turns a JSON Schema title or property name into a valid Avro name
+func avroName(hint string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range hint {
+		if !isAvroNameRune(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Record" + name
+	}
+	return name
+}
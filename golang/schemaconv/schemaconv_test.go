@@ -0,0 +1,191 @@
+package schemaconv_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/schemaconv"
+	"github.com/linkedin/goavro/v2"
+)
+
+var auditRecord = map[string]interface{}{
+	"eventId":      "event-12345",
+	"eventName":    "UserLogin",
+	"timestamp":    int64(1704067200000),
+	"eventDetails": "User logged in successfully",
+}
+
+// assertBinaryCompatible checks that a record written with writerDef decodes unchanged with readerDef
+func assertBinaryCompatible(t *testing.T, writerDef, readerDef string, record map[string]interface{}) {
+	t.Helper()
+	writer, err := goavro.NewCodec(writerDef)
+	if err != nil {
+		t.Fatalf("Failed to create writer codec: %v", err)
+	}
+	reader, err := goavro.NewCodec(readerDef)
+	if err != nil {
+		t.Fatalf("Failed to create reader codec from %s: %v", readerDef, err)
+	}
+
+	data, err := writer.BinaryFromNative(nil, record)
+	if err != nil {
+		t.Fatalf("Failed to encode record: %v", err)
+	}
+	decoded, _, err := reader.NativeFromBinary(data)
+	if err != nil {
+		t.Fatalf("Failed to decode record: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, record) {
+		t.Errorf("Record mismatch: expected %v, got %v", record, decoded)
+	}
+}
+
+func TestAvroToJSONSchemaSalesforceAudit(t *testing.T) {
+	jsonDef, err := schemaconv.AvroToJSONSchema(gluetest.SalesforceAuditAvroSchema)
+	if err != nil {
+		t.Fatalf("Failed to convert: %v", err)
+	}
+
+	var jsonSchema struct {
+		Title                string                       `json:"title"`
+		Type                 string                       `json:"type"`
+		Properties           map[string]map[string]string `json:"properties"`
+		Required             []string                     `json:"required"`
+		AdditionalProperties bool                         `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal([]byte(jsonDef), &jsonSchema); err != nil {
+		t.Fatalf("Failed to parse converted schema: %v", err)
+	}
+
+	if jsonSchema.Title != "SalesforceAudit" || jsonSchema.Type != "object" || jsonSchema.AdditionalProperties {
+		t.Errorf("Unexpected root: %s", jsonDef)
+	}
+	expectedTypes := map[string]string{"eventId": "string", "eventName": "string", "timestamp": "integer", "eventDetails": "string"}
+	for name, typeName := range expectedTypes {
+		if got := jsonSchema.Properties[name]["type"]; got != typeName {
+			t.Errorf("Property %s: expected type %s, got %s", name, typeName, got)
+		}
+	}
+	if !reflect.DeepEqual(jsonSchema.Required, []string{"eventId", "eventName", "timestamp", "eventDetails"}) {
+		t.Errorf("Unexpected required list: %v", jsonSchema.Required)
+	}
+
+	avroDef, err := schemaconv.JSONSchemaToAvro(jsonDef)
+	if err != nil {
+		t.Fatalf("Failed to convert back: %v", err)
+	}
+	assertBinaryCompatible(t, gluetest.SalesforceAuditAvroSchema, avroDef, auditRecord)
+}
+
+func TestJSONSchemaToAvroSalesforceAudit(t *testing.T) {
+	avroDef, err := schemaconv.JSONSchemaToAvro(gluetest.SalesforceAuditJSONSchema)
+	if err != nil {
+		t.Fatalf("Failed to convert: %v", err)
+	}
+	assertBinaryCompatible(t, gluetest.SalesforceAuditAvroSchema, avroDef, auditRecord)
+
+	jsonDef, err := schemaconv.AvroToJSONSchema(avroDef)
+	if err != nil {
+		t.Fatalf("Failed to convert back: %v", err)
+	}
+	var original, roundTripped map[string]interface{}
+	if err := json.Unmarshal([]byte(gluetest.SalesforceAuditJSONSchema), &original); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(jsonDef), &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"title", "type", "properties", "required", "additionalProperties"} {
+		if !reflect.DeepEqual(original[key], roundTripped[key]) {
+			t.Errorf("%s mismatch: expected %v, got %v", key, original[key], roundTripped[key])
+		}
+	}
+}
+
+func TestConvertComplexTypes(t *testing.T) {
+	avroDef := `{
+		"type": "record",
+		"name": "Order",
+		"fields": [
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["OPEN", "CLOSED"]}},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "attributes", "type": {"type": "map", "values": "long"}},
+			{"name": "note", "type": ["null", "string"], "default": null}
+		]
+	}`
+
+	jsonDef, err := schemaconv.AvroToJSONSchema(avroDef)
+	if err != nil {
+		t.Fatalf("Failed to convert: %v", err)
+	}
+	var jsonSchema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(jsonDef), &jsonSchema); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"status":     `{"enum":["OPEN","CLOSED"],"type":"string"}`,
+		"tags":       `{"items":{"type":"string"},"type":"array"}`,
+		"attributes": `{"additionalProperties":{"type":"integer"},"type":"object"}`,
+		"note":       `{"anyOf":[{"type":"null"},{"type":"string"}],"default":null}`,
+	}
+	for name, want := range expected {
+		var compact map[string]interface{}
+		if err := json.Unmarshal(jsonSchema.Properties[name], &compact); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := json.Marshal(compact)
+		if string(got) != want {
+			t.Errorf("Property %s: expected %s, got %s", name, want, got)
+		}
+	}
+	if !reflect.DeepEqual(jsonSchema.Required, []string{"status", "tags", "attributes"}) {
+		t.Errorf("Unexpected required list: %v", jsonSchema.Required)
+	}
+
+	roundTripped, err := schemaconv.JSONSchemaToAvro(jsonDef)
+	if err != nil {
+		t.Fatalf("Failed to convert back: %v", err)
+	}
+	assertBinaryCompatible(t, avroDef, roundTripped, map[string]interface{}{
+		"status":     "OPEN",
+		"tags":       []interface{}{"priority"},
+		"attributes": map[string]interface{}{"quantity": int64(3)},
+		"note":       goavro.Union("string", "gift"),
+	})
+}
+
+func TestAvroToJSONSchemaRejectsRecursiveTypes(t *testing.T) {
+	avroDef := `{"type": "record", "name": "Node", "fields": [
+		{"name": "value", "type": "string"},
+		{"name": "next", "type": ["null", "Node"], "default": null}
+	]}`
+	if _, err := schemaconv.AvroToJSONSchema(avroDef); err == nil {
+		t.Fatal("Expected error for recursive type")
+	}
+}
+
+func TestJSONSchemaToAvroRejectsInvalidNames(t *testing.T) {
+	for _, property := range []string{"event-id", "1st", "événement"} {
+		jsonDef := fmt.Sprintf(`{"type": "object", "properties": {%q: {"type": "string"}}}`, property)
+		_, err := schemaconv.JSONSchemaToAvro(jsonDef)
+		if err == nil || !strings.Contains(err.Error(), property) {
+			t.Errorf("Expected an error naming property %s, got %v", property, err)
+		}
+	}
+
+	// Titles are turned into ASCII record names rather than rejected
+	avroDef, err := schemaconv.JSONSchemaToAvro(`{"title": "café order", "type": "object", "properties": {"id": {"type": "string"}}}`)
+	if err != nil {
+		t.Fatalf("Failed to convert: %v", err)
+	}
+	if !strings.Contains(avroDef, `"name": "CafOrder"`) {
+		t.Errorf("Record name mismatch: expected CafOrder, got %s", avroDef)
+	}
+}
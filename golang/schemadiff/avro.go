@@ -0,0 +1,132 @@
+package schemadiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaDiff is the field-level difference between two Avro record schemas or two JSON
+// Schemas, with changed fields classified by what changed. A field whose type and default
+// both changed is listed in TypeChanges and DefaultChanges.
+type SchemaDiff struct {
+	// Added and Removed are the fields only the new or only the old schema has
+	Added   []FieldChange
+	Removed []FieldChange
+	// TypeChanges and DefaultChanges hold the normalized "type" and "default" of fields
+	// present in both schemas. Old or New is empty when the field has no default.
+	TypeChanges    []ValueChange
+	DefaultChanges []ValueChange
+	// Other lists fields that changed only in other attributes, such as doc, aliases or order
+	Other []FieldChange
+}
+
+// ValueChange is one attribute of a field that differs between an old and a new definition
+type ValueChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// DiffAvroSchemas reports the fields that differ between two Avro record schemas, as
+// DiffFields does, classified into a SchemaDiff. Each list is sorted by path.
+func DiffAvroSchemas(oldDefinition, newDefinition string) (SchemaDiff, error) {
+	for _, definition := range []string{oldDefinition, newDefinition} {
+		value, err := parse(definition)
+		if err != nil {
+			return SchemaDiff{}, err
+		}
+		if record, ok := value.(map[string]interface{}); !ok || record["type"] != "record" {
+			return SchemaDiff{}, fmt.Errorf("schema definition is not an Avro record: %s", encode(value))
+		}
+	}
+	return diffSchemas(oldDefinition, newDefinition, "AVRO")
+}
+
+// DiffSchemas reports the fields that differ between two definitions of dataFormat, AVRO
+// or JSON, classified into a SchemaDiff. Avro definitions must be records, as for
+// DiffAvroSchemas; the type and default of a JSON Schema property are its "type" and
+// "default" keywords.
+func DiffSchemas(oldDefinition, newDefinition, dataFormat string) (SchemaDiff, error) {
+	if strings.EqualFold(dataFormat, "AVRO") {
+		return DiffAvroSchemas(oldDefinition, newDefinition)
+	}
+	return diffSchemas(oldDefinition, newDefinition, dataFormat)
+}
+
+func diffSchemas(oldDefinition, newDefinition, dataFormat string) (SchemaDiff, error) {
+	changes, err := DiffFields(oldDefinition, newDefinition, dataFormat)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+
+	var d SchemaDiff
+	for _, change := range changes {
+		switch change.Kind {
+		case FieldAdded:
+			d.Added = append(d.Added, change)
+		case FieldRemoved:
+			d.Removed = append(d.Removed, change)
+		case FieldChanged:
+			// Old and New came from encode, so they parse
+			oldField, _ := parse(change.Old)
+			newField, _ := parse(change.New)
+			oldType, newType := attribute(oldField, "type"), attribute(newField, "type")
+			oldDefault, newDefault := attribute(oldField, "default"), attribute(newField, "default")
+			if oldType != newType {
+				d.TypeChanges = append(d.TypeChanges, ValueChange{Path: change.Path, Old: oldType, New: newType})
+			}
+			if oldDefault != newDefault {
+				d.DefaultChanges = append(d.DefaultChanges, ValueChange{Path: change.Path, Old: oldDefault, New: newDefault})
+			}
+			if oldType == newType && oldDefault == newDefault {
+				d.Other = append(d.Other, change)
+			}
+		}
+	}
+	return d, nil
+}
+
+// Empty reports whether the schemas have no field differences
+func (d SchemaDiff) Empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.TypeChanges)+len(d.DefaultChanges)+len(d.Other) == 0
+}
+
+// String describes the differences one per line, for example
+// "type of actor.name changed from "string" to ["null","string"]"
+func (d SchemaDiff) String() string {
+	var lines []string
+	for _, c := range d.Added {
+		lines = append(lines, fmt.Sprintf("field %s added", c.Path))
+	}
+	for _, c := range d.Removed {
+		lines = append(lines, fmt.Sprintf("field %s removed", c.Path))
+	}
+	for _, c := range d.TypeChanges {
+		lines = append(lines, fmt.Sprintf("type of %s changed from %s to %s", c.Path, c.Old, c.New))
+	}
+	for _, c := range d.DefaultChanges {
+		switch {
+		case c.Old == "":
+			lines = append(lines, fmt.Sprintf("default of %s added: %s", c.Path, c.New))
+		case c.New == "":
+			lines = append(lines, fmt.Sprintf("default of %s removed, was %s", c.Path, c.Old))
+		default:
+			lines = append(lines, fmt.Sprintf("default of %s changed from %s to %s", c.Path, c.Old, c.New))
+		}
+	}
+	for _, c := range d.Other {
+		lines = append(lines, fmt.Sprintf("field %s changed from %s to %s", c.Path, c.Old, c.New))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attribute returns the normalized value of key in a field definition, or "" if it has
+// none
+func attribute(field interface{}, key string) string {
+	f, _ := field.(map[string]interface{})
+	value, ok := f[key]
+	if !ok {
+		return ""
+	}
+	return encode(value)
+}
@@ -0,0 +1,182 @@
+// Package schemadiff normalizes schema definitions and reports field-level differences
+// between two definitions of the same schema, for AVRO and JSON data formats.
+//
+// Normalization only removes formatting differences: insignificant whitespace and the
+// order of object keys. Everything else, including documentation, is significant.
+//
+// Field differences are reported for Avro record fields and JSON Schema object properties,
+// descending into records and objects that are defined inline. Other changes, such as a
+// reordering of Avro fields or a change to a JSON Schema "required" list, make the
+// normalized definitions differ without producing a field change. DiffAvroSchemas
+// and DiffSchemas additionally classify the field changes into type and default changes.
+package schemadiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how a field differs between two definitions
+type ChangeKind string
+
+const (
+	FieldAdded   ChangeKind = "added"
+	FieldRemoved ChangeKind = "removed"
+	FieldChanged ChangeKind = "changed"
+)
+
+// FieldChange is one field that differs between an old and a new definition. Path is the
+// dot-separated field name from the root, and Old and New are the normalized definitions
+// of the field; Old is empty for added fields and New for removed fields.
+type FieldChange struct {
+	Path string
+	Kind ChangeKind
+	Old  string
+	New  string
+}
+
+// Normalize returns definition with insignificant whitespace removed and object keys sorted,
+// so that definitions differing only in formatting normalize to the same string
+func Normalize(definition string) (string, error) {
+	value, err := parse(definition)
+	if err != nil {
+		return "", err
+	}
+	return encode(value), nil
+}
+
+// DiffFields reports the fields that differ between oldDefinition and newDefinition, sorted
+// by path. dataFormat is AVRO or JSON.
+func DiffFields(oldDefinition, newDefinition, dataFormat string) ([]FieldChange, error) {
+	oldValue, err := parse(oldDefinition)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := parse(newDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields func(interface{}) []field
+	switch strings.ToUpper(dataFormat) {
+	case "AVRO":
+		fields = avroFields
+	case "JSON":
+		fields = jsonFields
+	default:
+		return nil, fmt.Errorf("unsupported data format for schema diff: %s", dataFormat)
+	}
+
+	var changes []FieldChange
+	diff("", oldValue, newValue, fields, &changes)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// field is a named child of a record or object. definition is compared for equality and
+// nested is the record or object the field defines inline, if any.
+type field struct {
+	name       string
+	definition interface{}
+	nested     interface{}
+}
+
+func diff(prefix string, oldValue, newValue interface{}, fields func(interface{}) []field, changes *[]FieldChange) {
+	oldFields := make(map[string]field)
+	for _, f := range fields(oldValue) {
+		oldFields[f.name] = f
+	}
+	seen := make(map[string]bool)
+
+	for _, n := range fields(newValue) {
+		seen[n.name] = true
+		path := prefix + n.name
+		o, ok := oldFields[n.name]
+		switch {
+		case !ok:
+			*changes = append(*changes, FieldChange{Path: path, Kind: FieldAdded, New: encode(n.definition)})
+		case o.nested != nil && n.nested != nil:
+			diff(path+".", o.nested, n.nested, fields, changes)
+		case encode(o.definition) != encode(n.definition):
+			*changes = append(*changes, FieldChange{Path: path, Kind: FieldChanged, Old: encode(o.definition), New: encode(n.definition)})
+		}
+	}
+	for _, o := range fields(oldValue) {
+		if !seen[o.name] {
+			*changes = append(*changes, FieldChange{Path: prefix + o.name, Kind: FieldRemoved, Old: encode(o.definition)})
+		}
+	}
+}
+
+// avroFields returns the fields of an Avro record schema
+func avroFields(value interface{}) []field {
+	record, ok := value.(map[string]interface{})
+	if !ok || record["type"] != "record" {
+		return nil
+	}
+	list, _ := record["fields"].([]interface{})
+
+	var fields []field
+	for _, item := range list {
+		f, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := f["name"].(string)
+		var nested interface{}
+		if t, ok := f["type"].(map[string]interface{}); ok && t["type"] == "record" {
+			// Descend into the inline record rather than comparing it whole
+			nested = t
+		}
+		fields = append(fields, field{name: name, definition: f, nested: nested})
+	}
+	return fields
+}
+
+// jsonFields returns the properties of a JSON Schema object
+func jsonFields(value interface{}) []field {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	properties, _ := object["properties"].(map[string]interface{})
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]field, 0, len(names))
+	for _, name := range names {
+		var nested interface{}
+		if p, ok := properties[name].(map[string]interface{}); ok && p["properties"] != nil {
+			nested = p
+		}
+		fields = append(fields, field{name: name, definition: properties[name], nested: nested})
+	}
+	return fields
+}
+
+func parse(definition string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(definition))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse schema definition: %w", err)
+	}
+	return value, nil
+}
+
+// encode writes value as compact JSON with sorted object keys
+func encode(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// value came from parse, so it always encodes
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,117 @@
+package schemadiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/schemadiff"
+)
+
+func TestNormalizeIgnoresFormatting(t *testing.T) {
+	a, err := schemadiff.Normalize(`{"type": "record", "name": "A", "fields": []}`)
+	if err != nil {
+		t.Fatalf("Failed to normalize: %v", err)
+	}
+	b, err := schemadiff.Normalize("{\n  \"fields\": [],\n  \"name\": \"A\",\n  \"type\": \"record\"\n}")
+	if err != nil {
+		t.Fatalf("Failed to normalize: %v", err)
+	}
+	if a != b {
+		t.Errorf("Normalized mismatch: expected %s, got %s", a, b)
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataFormat string
+		old, new   string
+		expected   []schemadiff.FieldChange
+	}{
+		{
+			name:       "avro nested record",
+			dataFormat: "AVRO",
+			old: `{"type": "record", "name": "Event", "fields": [
+				{"name": "id", "type": "string"},
+				{"name": "actor", "type": {"type": "record", "name": "Actor", "fields": [
+					{"name": "name", "type": "string"},
+					{"name": "email", "type": "string"}
+				]}}
+			]}`,
+			new: `{"type": "record", "name": "Event", "fields": [
+				{"name": "id", "type": "string"},
+				{"name": "actor", "type": {"type": "record", "name": "Actor", "fields": [
+					{"name": "name", "type": ["null", "string"], "default": null}
+				]}}
+			]}`,
+			expected: []schemadiff.FieldChange{
+				{Path: "actor.email", Kind: schemadiff.FieldRemoved, Old: `{"name":"email","type":"string"}`},
+				{Path: "actor.name", Kind: schemadiff.FieldChanged, Old: `{"name":"name","type":"string"}`, New: `{"default":null,"name":"name","type":["null","string"]}`},
+			},
+		},
+		{
+			name:       "json properties",
+			dataFormat: "JSON",
+			old:        `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			new:        `{"type": "object", "properties": {"id": {"type": "string"}, "count": {"type": "integer"}}}`,
+			expected: []schemadiff.FieldChange{
+				{Path: "count", Kind: schemadiff.FieldAdded, New: `{"type":"integer"}`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := schemadiff.DiffFields(tt.old, tt.new, tt.dataFormat)
+			if err != nil {
+				t.Fatalf("Failed to diff: %v", err)
+			}
+			if len(changes) != len(tt.expected) {
+				t.Fatalf("Expected %d changes, got %d: %+v", len(tt.expected), len(changes), changes)
+			}
+			for i := range changes {
+				if changes[i] != tt.expected[i] {
+					t.Errorf("Change %d mismatch: expected %+v, got %+v", i, tt.expected[i], changes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffAvroSchemas(t *testing.T) {
+	old := `{"type": "record", "name": "Event", "fields": [
+		{"name": "id", "type": "string"},
+		{"name": "count", "type": "int", "default": 0},
+		{"name": "source", "type": "string", "doc": "Origin"},
+		{"name": "legacy", "type": "string"}
+	]}`
+	new := `{"type": "record", "name": "Event", "fields": [
+		{"name": "id", "type": ["null", "string"], "default": null},
+		{"name": "count", "type": "int", "default": 1},
+		{"name": "source", "type": "string", "doc": "Where the event came from"},
+		{"name": "region", "type": "string"}
+	]}`
+
+	d, err := schemadiff.DiffAvroSchemas(old, new)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	expected := strings.Join([]string{
+		"field region added",
+		"field legacy removed",
+		`type of id changed from "string" to ["null","string"]`,
+		"default of count changed from 0 to 1",
+		"default of id added: null",
+		`field source changed from {"doc":"Origin","name":"source","type":"string"} to {"doc":"Where the event came from","name":"source","type":"string"}`,
+	}, "\n")
+	if got := d.String(); got != expected {
+		t.Errorf("Diff mismatch:\nexpected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if d, err := schemadiff.DiffAvroSchemas(old, old); err != nil || !d.Empty() {
+		t.Errorf("Expected no differences between identical schemas, got %+v (error %v)", d, err)
+	}
+	if _, err := schemadiff.DiffAvroSchemas(old, `{"type": "object"}`); err == nil {
+		t.Error("Expected an error for a definition that is not an Avro record")
+	}
+}
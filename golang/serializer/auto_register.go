@@ -0,0 +1,144 @@
+package serializer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// AutoRegistration configures WithAutoRegistration
+type AutoRegistration struct {
+	// AutoRegister must be true for anything to be registered, so that production
+	// configuration can switch auto-registration off while keeping the definitions
+	AutoRegister bool
+	// Definitions maps schema names to the definition to register for them. Schemas
+	// without a definition are used as they are.
+	Definitions map[string]string
+	// Compatibility is the mode of schemas created by auto-registration. The zero value
+	// uses client.CompatibilityBackward.
+	Compatibility client.Compatibility
+}
+
+// WithAutoRegistration makes the first Serialize of a schema listed in cfg.Definitions,
+// and of its variants such as SerializeWithHeader, register the listed definition:
+// CreateSchema if the schema does not exist, otherwise RegisterSchemaVersion, which adds
+// a version if the definition changed and is a no-op if it is already registered. Later
+// calls only serialize. A version Glue reports as FAILURE, for example because it breaks
+// the compatibility mode, fails the Serialize and is registered again by the next one; a
+// PENDING version is waited for until it is AVAILABLE. Two producers registering at the
+// same time converge on the same schema: losing the race to create it falls back to
+// registering the version.
+//
+// The data format of created schemas is that of the serializer. Deserialize never registers.
+func WithAutoRegistration(cfg AutoRegistration) Option {
+	return func(o *options) {
+		if !cfg.AutoRegister || len(cfg.Definitions) == 0 {
+			o.registrar = nil
+			return
+		}
+		compatibility := cfg.Compatibility
+		if compatibility == "" {
+			compatibility = client.CompatibilityBackward
+		}
+		o.registrar = &registrar{definitions: cfg.Definitions, compatibility: compatibility}
+	}
+}
+
+// registrar registers the definitions of WithAutoRegistration once per schema
+type registrar struct {
+	definitions   map[string]string
+	compatibility client.Compatibility
+
+	mu         sync.Mutex
+	registered map[string]bool
+}
+
+// ensureRegistered registers the configured definition of schemaName, if any, unless a
+// previous call did
+func (o *options) ensureRegistered(c client.Registry, schemaName string, dataFormat client.DataFormat) error {
+	r := o.registrar
+	if r == nil {
+		return nil
+	}
+	definition, ok := r.definitions[schemaName]
+	if !ok {
+		return nil
+	}
+
+	r.mu.Lock()
+	done := r.registered[schemaName]
+	r.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	if err := r.register(c, schemaName, dataFormat, definition); err != nil {
+		return fmt.Errorf("failed to auto-register schema %s: %w", schemaName, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.registered == nil {
+		r.registered = make(map[string]bool)
+	}
+	r.registered[schemaName] = true
+	return nil
+}
+
+func (r *registrar) register(c client.Registry, schemaName string, dataFormat client.DataFormat, definition string) error {
+	registered, err := c.RegisterSchemaVersion(schemaName, definition)
+	if err == nil {
+		return settle(c, schemaName, aws.Int64Value(registered.VersionNumber), aws.StringValue(registered.Status))
+	}
+	if !client.IsNotFound(err) {
+		return err
+	}
+
+	created, err := c.CreateSchema(schemaName, dataFormat, definition, r.compatibility)
+	if client.IsAlreadyExists(err) {
+		// Another producer created the schema since RegisterSchemaVersion failed
+		if registered, err = c.RegisterSchemaVersion(schemaName, definition); err != nil {
+			return err
+		}
+		return settle(c, schemaName, aws.Int64Value(registered.VersionNumber), aws.StringValue(registered.Status))
+	}
+	if err != nil {
+		return err
+	}
+	return settle(c, schemaName, aws.Int64Value(created.LatestSchemaVersion), aws.StringValue(created.SchemaVersionStatus))
+}
+
+// versionWaiter is implemented by registries that can wait for a schema version to
+// settle, as GlueSchemaRegistryClient does
+type versionWaiter interface {
+	WaitForSchemaVersionAvailableWithContext(ctx context.Context, schemaName string, versionNumber int64) error
+}
+
+// autoRegisterWaitTimeout bounds the wait for a PENDING version registered by
+// auto-registration to become AVAILABLE
+const autoRegisterWaitTimeout = 30 * time.Second
+
+// settle returns nil once the registered version of schemaName with status can be
+// serialized against: an error for a FAILURE version, and after waiting for a PENDING one
+// to become AVAILABLE. Registries that cannot wait fail for a PENDING version, so that the
+// next Serialize registers again.
+func settle(c client.Registry, schemaName string, versionNumber int64, status string) error {
+	switch status {
+	case glue.SchemaVersionStatusFailure:
+		return fmt.Errorf("version %d failed: %w", versionNumber, client.ErrSchemaVersionFailed)
+	case glue.SchemaVersionStatusPending:
+		w, ok := c.(versionWaiter)
+		if !ok {
+			return fmt.Errorf("version %d is still %s", versionNumber, status)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), autoRegisterWaitTimeout)
+		defer cancel()
+		return w.WaitForSchemaVersionAvailableWithContext(ctx, schemaName, versionNumber)
+	}
+	return nil
+}
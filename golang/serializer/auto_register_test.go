@@ -0,0 +1,163 @@
+package serializer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+func TestAutoRegistration(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	definitions := map[string]string{"SalesforceAudit": gluetest.SalesforceAuditAvroSchema}
+
+	disabled := serializer.NewAvroSerializer(serializer.WithAutoRegistration(serializer.AutoRegistration{Definitions: definitions}))
+	if _, err := disabled.Serialize(c, "SalesforceAudit", event); !client.IsNotFound(err) {
+		t.Fatalf("Expected not found error without AutoRegister, got %v", err)
+	}
+	if n := fake.Calls("CreateSchema") + fake.Calls("RegisterSchemaVersion"); n != 0 {
+		t.Errorf("Registrations mismatch: expected 0, got %d", n)
+	}
+
+	s := serializer.NewAvroSerializer(serializer.WithAutoRegistration(serializer.AutoRegistration{
+		AutoRegister: true,
+		Definitions:  definitions,
+	}))
+	for i := 0; i < 2; i++ {
+		if _, err := s.Serialize(c, "SalesforceAudit", event); err != nil {
+			t.Fatalf("Failed to serialize: %v", err)
+		}
+	}
+	if n := fake.Calls("CreateSchema"); n != 1 {
+		t.Errorf("CreateSchema calls mismatch: expected 1, got %d", n)
+	}
+	if n := fake.Calls("RegisterSchemaVersion"); n != 1 {
+		t.Errorf("RegisterSchemaVersion calls mismatch: expected 1, got %d", n)
+	}
+	schema, err := c.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if *schema.DataFormat != "AVRO" || *schema.Compatibility != string(client.CompatibilityBackward) {
+		t.Errorf("Schema mismatch: expected AVRO with BACKWARD, got %s with %s", *schema.DataFormat, *schema.Compatibility)
+	}
+
+	// A new serializer with a changed definition registers it as a new version
+	s = serializer.NewAvroSerializer(serializer.WithAutoRegistration(serializer.AutoRegistration{
+		AutoRegister: true,
+		Definitions:  map[string]string{"SalesforceAudit": auditSchemaV2},
+	}))
+	if _, err := s.Serialize(c, "SalesforceAudit", event); err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	schema, err = c.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if *schema.LatestSchemaVersion != 2 {
+		t.Errorf("Latest version mismatch: expected 2, got %d", *schema.LatestSchemaVersion)
+	}
+}
+
+// racingRegistry creates each schema itself just before the caller's CreateSchema, as a
+// second producer auto-registering it at the same time would
+type racingRegistry struct {
+	*client.GlueSchemaRegistryClient
+}
+
+func (r racingRegistry) CreateSchema(schemaName string, dataFormat client.DataFormat, schemaDefinition string, compatibility client.Compatibility) (*glue.CreateSchemaOutput, error) {
+	if _, err := r.GlueSchemaRegistryClient.CreateSchema(schemaName, dataFormat, schemaDefinition, compatibility); err != nil {
+		return nil, err
+	}
+	return r.GlueSchemaRegistryClient.CreateSchema(schemaName, dataFormat, schemaDefinition, compatibility)
+}
+
+func TestAutoRegistrationRace(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	s := serializer.NewJsonSerializer(serializer.WithAutoRegistration(serializer.AutoRegistration{
+		AutoRegister: true,
+		Definitions:  map[string]string{"SalesforceAudit": gluetest.SalesforceAuditJSONSchema},
+	}))
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	if _, err := s.Serialize(racingRegistry{c}, "SalesforceAudit", event); err != nil {
+		t.Fatalf("Failed to serialize after losing the registration race: %v", err)
+	}
+	if n := fake.Calls("RegisterSchemaVersion"); n != 2 {
+		t.Errorf("RegisterSchemaVersion calls mismatch: expected 2, got %d", n)
+	}
+	schema, err := c.GetSchema("SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if *schema.LatestSchemaVersion != 1 {
+		t.Errorf("Latest version mismatch: expected 1, got %d", *schema.LatestSchemaVersion)
+	}
+}
+
+// statusRegistry reports every version it registers with a fixed status, as Glue does for
+// a version that fails the compatibility check
+type statusRegistry struct {
+	*client.GlueSchemaRegistryClient
+	status string
+}
+
+func (r statusRegistry) RegisterSchemaVersion(schemaName, schemaDefinition string) (*glue.RegisterSchemaVersionOutput, error) {
+	output, err := r.GlueSchemaRegistryClient.RegisterSchemaVersion(schemaName, schemaDefinition)
+	if err == nil {
+		output.Status = aws.String(r.status)
+	}
+	return output, err
+}
+
+func TestAutoRegistrationFailedVersion(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	s := serializer.NewAvroSerializer(serializer.WithAutoRegistration(serializer.AutoRegistration{
+		AutoRegister: true,
+		Definitions:  map[string]string{"SalesforceAudit": auditSchemaV2},
+	}))
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	failing := statusRegistry{c, glue.SchemaVersionStatusFailure}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Serialize(failing, "SalesforceAudit", event); !errors.Is(err, client.ErrSchemaVersionFailed) {
+			t.Errorf("Expected ErrSchemaVersionFailed, got %v", err)
+		}
+	}
+	// The failed version is not remembered as registered, so every Serialize retries it
+	if n := fake.Calls("RegisterSchemaVersion"); n != 2 {
+		t.Errorf("RegisterSchemaVersion calls mismatch: expected 2, got %d", n)
+	}
+	if _, err := s.Serialize(c, "SalesforceAudit", event); err != nil {
+		t.Fatalf("Failed to serialize once the version is AVAILABLE: %v", err)
+	}
+	if n := fake.Calls("RegisterSchemaVersion"); n != 3 {
+		t.Errorf("RegisterSchemaVersion calls mismatch: expected 3, got %d", n)
+	}
+
+	// A PENDING version is waited for; the fake reports it AVAILABLE on the first check
+	pending := statusRegistry{c, glue.SchemaVersionStatusPending}
+	s = serializer.NewAvroSerializer(serializer.WithAutoRegistration(serializer.AutoRegistration{
+		AutoRegister: true,
+		Definitions:  map[string]string{"SalesforceAudit": auditSchemaV2},
+	}))
+	if _, err := s.Serialize(pending, "SalesforceAudit", event); err != nil {
+		t.Fatalf("Failed to serialize after waiting for a PENDING version: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package serializer
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+)
+
+// SerializeBatch serializes a slice of records into a single Avro payload. Each element
+// is converted as Serialize converts a single value. The schema must be an Avro array of
+// records, for example {"type": "array", "items": {"type": "record", "name": "SalesforceAudit", ...}}.
+func (s *AvroSerializer) SerializeBatch(c client.Registry, schemaName string, values interface{}) ([]byte, error) {
+	start := time.Now()
+	binary, err := s.serializeBatch(c, schemaName, values)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return binary, err
+}
+
+func (s *AvroSerializer) serializeBatch(c client.Registry, schemaName string, values interface{}) ([]byte, error) {
+	cs, err := s.writeCompiled(c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSchema, ok := cs.schema["items"].(map[string]interface{})
+	if cs.schema["type"] != "array" || !ok {
+		return nil, fmt.Errorf("schema definition is not an Avro array of records")
+	}
+
+	native, err := cs.toNative(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T to an Avro array: %w", values, err)
+	}
+	items := native.([]interface{})
+	for i, item := range items {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("batch item %d: unexpected datum type: %T", i, item)
+		}
+		if err := validateRecordKeys(recordSchema, record); err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+	}
+
+	var binary []byte
+	if s.sortMapKeys {
+		binary, err = cs.sorted.BinaryFromNative(nil, items)
+	} else {
+		binary, err = binaryFromNative(cs.codec, nil, items)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode batch: %w", err)
+	}
+	return binary, nil
+}
+
+// SerializeEach serializes every element of values, a slice such as
+// []*model.SalesforceAudit, to its own Avro payload, exactly as Serialize would serialize
+// it. Unlike calling Serialize in a loop, the latest schema version is looked up once for
+// the whole slice. The schema is the record schema used with Serialize, not the array
+// schema used with SerializeBatch. The first element that fails to serialize stops the
+// batch, and the error names its index.
+func (s *AvroSerializer) SerializeEach(c client.Registry, schemaName string, values interface{}) ([][]byte, error) {
+	start := time.Now()
+	payloads, err := s.serializeEach(c, schemaName, values)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return payloads, err
+}
+
+func (s *AvroSerializer) serializeEach(c client.Registry, schemaName string, values interface{}) ([][]byte, error) {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("values must be a slice, got %T", values)
+	}
+
+	cs, err := s.writeCompiled(c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([][]byte, rv.Len())
+	for i := range payloads {
+		record, err := cs.toRecord(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		if payloads[i], err = s.encode(cs, record); err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+	}
+	return payloads, nil
+}
+
+// DeserializeBatch deserializes a payload written by SerializeBatch into out, which must
+// be a non-nil pointer to a slice
+func (s *AvroSerializer) DeserializeBatch(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	start := time.Now()
+	err := s.deserializeBatch(c, schemaName, data, out)
+	s.recordSerde(opDeserialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+func (s *AvroSerializer) deserializeBatch(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	if err := checkOut(out); err != nil {
+		return err
+	}
+	if err := s.checkPayloadSize(data); err != nil {
+		return err
+	}
+
+	cs, err := s.latestCompiled(c, schemaName)
+	if err != nil {
+		return err
+	}
+
+	datum, remaining, err := cs.codec.NativeFromBinary(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode batch: %w", err)
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("failed to decode batch: %d trailing bytes after the batch", len(remaining))
+	}
+	if _, ok := datum.([]interface{}); !ok {
+		return fmt.Errorf("unexpected datum type: %T", datum)
+	}
+
+	return cs.fromNative(datum, out)
+}
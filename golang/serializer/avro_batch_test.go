@@ -0,0 +1,148 @@
+package serializer_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestAvroBatchRoundTrip(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	batchSchema := `{"type": "array", "items": ` + gluetest.SalesforceAuditAvroSchema + `}`
+	if _, err := c.CreateSchema("SalesforceAuditBatch", "AVRO", batchSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	var many []*model.SalesforceAudit
+	for i := 0; i < 50; i++ {
+		many = append(many, &model.SalesforceAudit{
+			EventID:      fmt.Sprintf("event-%d", i),
+			EventName:    "UserLogin",
+			Timestamp:    1704067200000 + int64(i),
+			EventDetails: "User logged in successfully",
+		})
+	}
+
+	avroSerializer := serializer.NewAvroSerializer()
+	for _, events := range [][]*model.SalesforceAudit{{}, many[:1], many} {
+		t.Run(fmt.Sprintf("%d events", len(events)), func(t *testing.T) {
+			serializedData, err := avroSerializer.SerializeBatch(c, "SalesforceAuditBatch", events)
+			if err != nil {
+				t.Fatalf("Failed to serialize batch: %v", err)
+			}
+
+			var deserialized []*model.SalesforceAudit
+			err = avroSerializer.DeserializeBatch(c, "SalesforceAuditBatch", serializedData, &deserialized)
+			if err != nil {
+				t.Fatalf("Failed to deserialize batch: %v", err)
+			}
+			if len(deserialized) != len(events) {
+				t.Fatalf("Expected %d events, got %d", len(events), len(deserialized))
+			}
+			for i := range events {
+				if *deserialized[i] != *events[i] {
+					t.Errorf("Event %d mismatch: expected %+v, got %+v", i, *events[i], *deserialized[i])
+				}
+			}
+
+			var trailing []*model.SalesforceAudit
+			if err := avroSerializer.DeserializeBatch(c, "SalesforceAuditBatch", append(serializedData, 0), &trailing); err == nil {
+				t.Error("Expected an error for bytes left over after the batch")
+			}
+		})
+	}
+}
+
+func TestAvroSerializeBatchRejectsRecordSchema(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	if _, err := serializer.NewAvroSerializer().SerializeBatch(c, "SalesforceAudit", nil); err == nil {
+		t.Fatal("Expected error serializing a batch against a record schema")
+	}
+}
+
+func auditEvents(n int) []*model.SalesforceAudit {
+	events := make([]*model.SalesforceAudit, n)
+	for i := range events {
+		events[i] = &model.SalesforceAudit{
+			EventID:      fmt.Sprintf("event-%d", i),
+			EventName:    "UserLogin",
+			Timestamp:    1704067200000 + int64(i),
+			EventDetails: "User logged in successfully",
+		}
+	}
+	return events
+}
+
+func TestAvroSerializeEach(t *testing.T) {
+	fake, c := newCodecCacheClient(t)
+	defer c.Close()
+
+	avroSerializer := serializer.NewAvroSerializer()
+	events := auditEvents(20)
+	payloads, err := avroSerializer.SerializeEach(c, "SalesforceAudit", events)
+	if err != nil {
+		t.Fatalf("Failed to serialize each: %v", err)
+	}
+	if calls := fake.Calls("GetSchema"); calls != 1 {
+		t.Errorf("GetSchema call count mismatch: expected 1, got %d", calls)
+	}
+	if len(payloads) != len(events) {
+		t.Fatalf("Expected %d payloads, got %d", len(events), len(payloads))
+	}
+	for i, payload := range payloads {
+		var decoded model.SalesforceAudit
+		if err := avroSerializer.Deserialize(c, "SalesforceAudit", payload, &decoded); err != nil {
+			t.Fatalf("Failed to deserialize payload %d: %v", i, err)
+		}
+		if decoded != *events[i] {
+			t.Errorf("Event %d mismatch: expected %+v, got %+v", i, *events[i], decoded)
+		}
+	}
+
+	invalid := []interface{}{events[0], map[string]interface{}{"eventId": "event-1"}}
+	if _, err := avroSerializer.SerializeEach(c, "SalesforceAudit", invalid); err == nil || !strings.Contains(err.Error(), "batch item 1") {
+		t.Errorf("Expected error naming batch item 1, got %v", err)
+	}
+	if _, err := avroSerializer.SerializeEach(c, "SalesforceAudit", events[0]); err == nil {
+		t.Error("Expected error for a value that is not a slice")
+	}
+}
+
+func BenchmarkAvroSerializeEach(b *testing.B) {
+	events := auditEvents(100)
+
+	b.Run("SerializeLoop", func(b *testing.B) {
+		_, c := newCodecCacheClient(b)
+		avroSerializer := serializer.NewAvroSerializer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, event := range events {
+				if _, err := avroSerializer.Serialize(c, "SalesforceAudit", event); err != nil {
+					b.Fatalf("Failed to serialize: %v", err)
+				}
+			}
+		}
+	})
+
+	b.Run("SerializeEach", func(b *testing.B) {
+		_, c := newCodecCacheClient(b)
+		avroSerializer := serializer.NewAvroSerializer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := avroSerializer.SerializeEach(c, "SalesforceAudit", events); err != nil {
+				b.Fatalf("Failed to serialize each: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package serializer
+
+import "strings"
+
+// avroNames indexes the named types (records, enums and fixed) of an Avro schema by
+// full name, so that later references to them can be resolved
+type avroNames map[string]interface{}
+
+// newAvroNames collects the named types of a parsed schema
+func newAvroNames(schema interface{}) avroNames {
+	names := make(avroNames)
+	names.collect(schema, "")
+	return names
+}
+
+// collect records every named type so that later references can be resolved
+func (n avroNames) collect(schema interface{}, namespace string) {
+	switch s := schema.(type) {
+	case []interface{}:
+		for _, branch := range s {
+			n.collect(branch, namespace)
+		}
+	case map[string]interface{}:
+		typeName, _ := s["type"].(string)
+		switch typeName {
+		case "record", "error", "enum", "fixed":
+			name := fullName(s, namespace)
+			n[name] = s
+			namespace = namespaceOf(name)
+		}
+		if fields, ok := s["fields"].([]interface{}); ok {
+			for _, f := range fields {
+				if field, ok := f.(map[string]interface{}); ok {
+					n.collect(field["type"], namespace)
+				}
+			}
+		}
+		for _, key := range []string{"items", "values"} {
+			if nested, ok := s[key]; ok {
+				n.collect(nested, namespace)
+			}
+		}
+		if nested, ok := s["type"].(map[string]interface{}); ok {
+			n.collect(nested, namespace)
+		}
+	}
+}
+
+func (n avroNames) resolve(name, namespace string) (interface{}, bool) {
+	if !strings.Contains(name, ".") && namespace != "" {
+		if s, ok := n[namespace+"."+name]; ok {
+			return s, true
+		}
+	}
+	s, ok := n[name]
+	return s, ok
+}
+
+// branchMatches reports whether name is the goavro union key for branch
+func (n avroNames) branchMatches(branch interface{}, namespace, name string) bool {
+	switch b := branch.(type) {
+	case string:
+		if resolved, ok := n.resolve(b, namespace); ok {
+			full := fullName(resolved.(map[string]interface{}), namespace)
+			return name == full || name == b
+		}
+		return name == b
+	case map[string]interface{}:
+		typeName, _ := b["type"].(string)
+		switch typeName {
+		case "record", "error", "enum", "fixed":
+			full := fullName(b, namespace)
+			return name == full || name == b["name"]
+		}
+		if logicalType, ok := b["logicalType"].(string); ok {
+			return name == typeName+"."+logicalType || name == typeName
+		}
+		return name == typeName
+	}
+	return false
+}
+
+// branchKey returns the key goavro uses for branch when wrapping a union value
+func (n avroNames) branchKey(branch interface{}, namespace string) string {
+	switch b := branch.(type) {
+	case string:
+		if resolved, ok := n.resolve(b, namespace); ok {
+			return fullName(resolved.(map[string]interface{}), namespace)
+		}
+		return b
+	case map[string]interface{}:
+		typeName, _ := b["type"].(string)
+		switch typeName {
+		case "record", "error", "enum", "fixed":
+			return fullName(b, namespace)
+		}
+		if logicalType, ok := b["logicalType"].(string); ok {
+			return typeName + "." + logicalType
+		}
+		return typeName
+	}
+	return ""
+}
+
+func fullName(schema map[string]interface{}, namespace string) string {
+	name, _ := schema["name"].(string)
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if ns, ok := schema["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func namespaceOf(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
@@ -0,0 +1,557 @@
+package serializer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// recordMapper is implemented by model types that convert themselves to a goavro native
+// record, such as model.SalesforceAudit. It is used in preference to reflection.
+type recordMapper interface {
+	ToMap() map[string]interface{}
+}
+
+// recordUnmapper is the decoding counterpart of recordMapper
+type recordUnmapper interface {
+	FromMap(map[string]interface{})
+}
+
+// avroConverter converts between Go values and goavro's native form. Struct fields are
+// matched to record fields by their avro tag, then their json tag, then their name. The
+// conversion is guided by the schema so that union values are wrapped and unwrapped the
+// way goavro expects.
+type avroConverter struct {
+	names avroNames
+}
+
+func (cv avroConverter) toNative(schema interface{}, namespace string, v reflect.Value) (interface{}, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.Value{}
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch s := schema.(type) {
+	case string:
+		if resolved, ok := cv.names.resolve(s, namespace); ok {
+			return cv.toNative(resolved, namespaceOf(fullName(resolved.(map[string]interface{}), namespace)), v)
+		}
+		return primitiveToNative(s, v)
+	case []interface{}:
+		return cv.unionToNative(s, namespace, v)
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record", "error":
+			return cv.recordToNative(s, namespace, v)
+		case "enum":
+			return primitiveToNative("string", v)
+		case "fixed":
+			return primitiveToNative("bytes", v)
+		case "array":
+			return cv.arrayToNative(s, namespace, v)
+		case "map":
+			return cv.mapToNative(s, namespace, v)
+		}
+		if nested, ok := s["type"].(map[string]interface{}); ok {
+			return cv.toNative(nested, namespace, v)
+		}
+		if nested, ok := s["type"].([]interface{}); ok {
+			return cv.toNative(nested, namespace, v)
+		}
+		typeName, _ := s["type"].(string)
+		if _, ok := s["logicalType"]; ok && v.IsValid() {
+			// goavro converts logical types such as time.Time itself
+			return v.Interface(), nil
+		}
+		return primitiveToNative(typeName, v)
+	}
+	return nil, fmt.Errorf("unsupported Avro schema: %v", schema)
+}
+
+func (cv avroConverter) unionToNative(branches []interface{}, namespace string, v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	// Values already in goavro's wrapped form are passed through
+	if wrapped, ok := v.Interface().(map[string]interface{}); ok && len(wrapped) == 1 {
+		for key := range wrapped {
+			for _, branch := range branches {
+				if cv.names.branchMatches(branch, namespace, key) {
+					return wrapped, nil
+				}
+			}
+		}
+	}
+	for _, branch := range branches {
+		if branch == "null" {
+			continue
+		}
+		if native, err := cv.toNative(branch, namespace, v); err == nil {
+			return goavro.Union(cv.names.branchKey(branch, namespace), native), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot convert %s to any member of union %v", v.Type(), branches)
+}
+
+func (cv avroConverter) recordToNative(schema map[string]interface{}, namespace string, v reflect.Value) (interface{}, error) {
+	name := fullName(schema, namespace)
+	if !v.IsValid() {
+		return nil, fmt.Errorf("cannot convert nil to record %s", name)
+	}
+	if mapper, ok := asMapper(v); ok {
+		// ToMap gives plain values; converting them as a map wraps those of union fields
+		v = reflect.ValueOf(mapper.ToMap())
+	}
+
+	fields, _ := schema["fields"].([]interface{})
+	switch {
+	case v.Kind() == reflect.Struct:
+		index := structFields(v.Type())
+		record := make(map[string]interface{}, len(index))
+		for _, f := range fields {
+			field := f.(map[string]interface{})
+			fieldName, _ := field["name"].(string)
+			path, ok := index[fieldName]
+			if !ok {
+				continue
+			}
+			native, err := cv.toNative(field["type"], namespaceOf(name), fieldByIndex(v, path))
+			if err != nil {
+				return nil, fmt.Errorf("record %s field %q: %w", name, fieldName, err)
+			}
+			record[fieldName] = native
+		}
+		// Struct fields the schema does not define are kept so that they are reported as unexpected
+		for key, path := range index {
+			if _, ok := record[key]; !ok {
+				record[key] = nil
+				if fv := fieldByIndex(v, path); fv.IsValid() {
+					record[key] = fv.Interface()
+				}
+			}
+		}
+		return record, nil
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		record := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			record[k.String()] = v.MapIndex(k).Interface()
+		}
+		for _, f := range fields {
+			field := f.(map[string]interface{})
+			fieldName, _ := field["name"].(string)
+			value, ok := record[fieldName]
+			if !ok {
+				continue
+			}
+			native, err := cv.toNative(field["type"], namespaceOf(name), reflect.ValueOf(value))
+			if err != nil {
+				return nil, fmt.Errorf("record %s field %q: %w", name, fieldName, err)
+			}
+			record[fieldName] = native
+		}
+		return record, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to record %s", v.Type(), name)
+}
+
+func (cv avroConverter) arrayToNative(schema map[string]interface{}, namespace string, v reflect.Value) (interface{}, error) {
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("cannot convert %s to Avro array", typeOf(v))
+	}
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		item, err := cv.toNative(schema["items"], namespace, v.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("array item %d: %w", i, err)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func (cv avroConverter) mapToNative(schema map[string]interface{}, namespace string, v reflect.Value) (interface{}, error) {
+	if !v.IsValid() || v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("cannot convert %s to Avro map", typeOf(v))
+	}
+	entries := make(map[string]interface{}, v.Len())
+	for _, k := range v.MapKeys() {
+		value, err := cv.toNative(schema["values"], namespace, v.MapIndex(k))
+		if err != nil {
+			return nil, fmt.Errorf("map value for key %q: %w", k.String(), err)
+		}
+		entries[k.String()] = value
+	}
+	return entries, nil
+}
+
+// primitiveToNative converts v to the native form of a primitive Avro type. Only Go kinds
+// that hold the type without loss are accepted, which lets unions pick the right branch.
+func primitiveToNative(typeName string, v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		if typeName == "null" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot convert nil to Avro %s", typeName)
+	}
+
+	switch typeName {
+	case "boolean":
+		if v.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+	case "int", "long":
+		limit := int64(math.MaxInt64)
+		if typeName == "int" {
+			limit = math.MaxInt32
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n := v.Int()
+			if n > limit || n < -limit-1 {
+				return nil, fmt.Errorf("value %d overflows Avro %s", n, typeName)
+			}
+			if typeName == "int" {
+				return int32(n), nil
+			}
+			return n, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u := v.Uint()
+			if u > uint64(limit) {
+				return nil, fmt.Errorf("value %d overflows Avro %s", u, typeName)
+			}
+			if typeName == "int" {
+				return int32(u), nil
+			}
+			return int64(u), nil
+		}
+	case "float":
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			return float32(v.Float()), nil
+		}
+	case "double":
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			return v.Float(), nil
+		}
+	case "string":
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+	case "bytes":
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot convert %s to Avro %s", v.Type(), typeName)
+}
+
+func (cv avroConverter) fromNative(schema interface{}, namespace string, native interface{}, dst reflect.Value) error {
+	// Unions are unwrapped first so that a null value leaves a pointer field nil
+	if branches, ok := schema.([]interface{}); ok {
+		if native == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		if wrapped, ok := native.(map[string]interface{}); ok && len(wrapped) == 1 {
+			for key, value := range wrapped {
+				for _, branch := range branches {
+					if cv.names.branchMatches(branch, namespace, key) {
+						return cv.fromNative(branch, namespace, value, dst)
+					}
+				}
+			}
+		}
+		return fmt.Errorf("cannot decode %T as a member of union %v", native, branches)
+	}
+	if native == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return cv.fromNative(schema, namespace, native, dst.Elem())
+	case reflect.Interface:
+		if dst.NumMethod() == 0 {
+			dst.Set(reflect.ValueOf(native))
+			return nil
+		}
+	}
+
+	switch s := schema.(type) {
+	case string:
+		if resolved, ok := cv.names.resolve(s, namespace); ok {
+			return cv.fromNative(resolved, namespaceOf(fullName(resolved.(map[string]interface{}), namespace)), native, dst)
+		}
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record", "error":
+			return cv.recordFromNative(s, namespace, native, dst)
+		case "array":
+			return cv.arrayFromNative(s, namespace, native, dst)
+		case "map":
+			return cv.mapFromNative(s, namespace, native, dst)
+		}
+		if nested, ok := s["type"].(map[string]interface{}); ok {
+			return cv.fromNative(nested, namespace, native, dst)
+		}
+		if nested, ok := s["type"].([]interface{}); ok {
+			return cv.fromNative(nested, namespace, native, dst)
+		}
+	}
+	return setLeaf(dst, native)
+}
+
+func (cv avroConverter) recordFromNative(schema map[string]interface{}, namespace string, native interface{}, dst reflect.Value) error {
+	name := fullName(schema, namespace)
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("cannot decode %T as record %s", native, name)
+	}
+	if dst.CanAddr() {
+		if unmapper, ok := dst.Addr().Interface().(recordUnmapper); ok {
+			unmapper.FromMap(record)
+			return nil
+		}
+	}
+
+	fields, _ := schema["fields"].([]interface{})
+	switch {
+	case dst.Kind() == reflect.Struct:
+		index := structFields(dst.Type())
+		for _, f := range fields {
+			field := f.(map[string]interface{})
+			fieldName, _ := field["name"].(string)
+			path, ok := index[fieldName]
+			value, present := record[fieldName]
+			if !ok || !present {
+				continue
+			}
+			if err := cv.fromNative(field["type"], namespaceOf(name), value, fieldForSet(dst, path)); err != nil {
+				return fmt.Errorf("record %s field %q: %w", name, fieldName, err)
+			}
+		}
+		return nil
+	case dst.Kind() == reflect.Map && dst.Type().Key().Kind() == reflect.String:
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(record)))
+		}
+		for _, f := range fields {
+			field := f.(map[string]interface{})
+			fieldName, _ := field["name"].(string)
+			value, present := record[fieldName]
+			if !present {
+				continue
+			}
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := cv.fromNative(field["type"], namespaceOf(name), value, elem); err != nil {
+				return fmt.Errorf("record %s field %q: %w", name, fieldName, err)
+			}
+			dst.SetMapIndex(reflect.ValueOf(fieldName).Convert(dst.Type().Key()), elem)
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot decode record %s into %s", name, dst.Type())
+}
+
+func (cv avroConverter) arrayFromNative(schema map[string]interface{}, namespace string, native interface{}, dst reflect.Value) error {
+	items, ok := native.([]interface{})
+	if !ok || dst.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot decode %T into %s", native, dst.Type())
+	}
+	out := reflect.MakeSlice(dst.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := cv.fromNative(schema["items"], namespace, item, out.Index(i)); err != nil {
+			return fmt.Errorf("array item %d: %w", i, err)
+		}
+	}
+	dst.Set(out)
+	return nil
+}
+
+func (cv avroConverter) mapFromNative(schema map[string]interface{}, namespace string, native interface{}, dst reflect.Value) error {
+	entries, ok := native.(map[string]interface{})
+	if !ok || dst.Kind() != reflect.Map || dst.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("cannot decode %T into %s", native, dst.Type())
+	}
+	out := reflect.MakeMapWithSize(dst.Type(), len(entries))
+	for k, value := range entries {
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		if err := cv.fromNative(schema["values"], namespace, value, elem); err != nil {
+			return fmt.Errorf("map value for key %q: %w", k, err)
+		}
+		out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+	}
+	dst.Set(out)
+	return nil
+}
+
+// setLeaf stores a decoded primitive, enum, fixed or logical value in dst, converting
+// between numeric kinds when the value fits
+func setLeaf(dst reflect.Value, native interface{}) error {
+	nv := reflect.ValueOf(native)
+	if b, ok := native.([]byte); ok && dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+		dst.SetBytes(append([]byte(nil), b...))
+		return nil
+	}
+	if nv.Type().AssignableTo(dst.Type()) {
+		dst.Set(nv)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if nv.CanInt() && !dst.OverflowInt(nv.Int()) {
+			dst.SetInt(nv.Int())
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if nv.CanInt() && nv.Int() >= 0 && !dst.OverflowUint(uint64(nv.Int())) {
+			dst.SetUint(uint64(nv.Int()))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if nv.CanFloat() {
+			dst.SetFloat(nv.Float())
+			return nil
+		}
+	case reflect.String:
+		if nv.Kind() == reflect.String {
+			dst.SetString(nv.String())
+			return nil
+		}
+	case reflect.Bool:
+		if nv.Kind() == reflect.Bool {
+			dst.SetBool(nv.Bool())
+			return nil
+		}
+	case reflect.Array:
+		if b, ok := native.([]byte); ok && dst.Type().Elem().Kind() == reflect.Uint8 && dst.Len() == len(b) {
+			reflect.Copy(dst, nv)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot decode %T into %s", native, dst.Type())
+}
+
+// asMapper returns v as a recordMapper, taking its address or a copy when ToMap has a
+// pointer receiver
+func asMapper(v reflect.Value) (recordMapper, bool) {
+	if v.CanInterface() {
+		if mapper, ok := v.Interface().(recordMapper); ok {
+			return mapper, true
+		}
+	}
+	if v.Kind() == reflect.Struct && reflect.PointerTo(v.Type()).Implements(reflect.TypeOf((*recordMapper)(nil)).Elem()) {
+		if v.CanAddr() {
+			return v.Addr().Interface().(recordMapper), true
+		}
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(recordMapper), true
+	}
+	return nil, false
+}
+
+var structFieldCache sync.Map // reflect.Type -> map[string][]int
+
+// structFields maps the Avro field name of every exported field of struct type t to its
+// index path. Fields of embedded structs without a tag are promoted, with shallower
+// fields taking precedence, as encoding/json does.
+func structFields(t reflect.Type) map[string][]int {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	fields := make(map[string][]int)
+	collectStructFields(t, nil, fields)
+	structFieldCache.Store(t, fields)
+	return fields
+}
+
+func collectStructFields(t reflect.Type, prefix []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, tagged := fieldTagName(sf)
+		if name == "-" {
+			continue
+		}
+		path := append(append([]int(nil), prefix...), i)
+		if sf.Anonymous && !tagged {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectStructFields(ft, path, fields)
+				continue
+			}
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if existing, ok := fields[name]; !ok || len(path) < len(existing) {
+			fields[name] = path
+		}
+	}
+}
+
+// fieldTagName returns the name given to a struct field by its avro or json tag
+func fieldTagName(sf reflect.StructField) (name string, tagged bool) {
+	for _, key := range []string{"avro", "json"} {
+		if tag, ok := sf.Tag.Lookup(key); ok {
+			name, _, _ = strings.Cut(tag, ",")
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// fieldByIndex returns the field at path, or the zero Value if an embedded pointer on the
+// way is nil
+func fieldByIndex(v reflect.Value, path []int) reflect.Value {
+	for i, x := range path {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// fieldForSet returns the field at path, allocating nil embedded pointers on the way
+func fieldForSet(v reflect.Value, path []int) reflect.Value {
+	for i, x := range path {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func typeOf(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return v.Type().String()
+}
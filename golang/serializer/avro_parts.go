@@ -0,0 +1,162 @@
+package serializer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// SerializeParts serializes v like Serialize, but returns the Glue wire-format header,
+// which identifies the schema version, separately from the Avro body. Concatenating
+// header and body gives the framed message. With WithZlibCompression the body is
+// compressed and the header says so.
+func (s *AvroSerializer) SerializeParts(c client.Registry, schemaName string, v interface{}) (header []byte, body []byte, err error) {
+	start := time.Now()
+	header, body, err = s.serializeParts(c, schemaName, v)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return header, body, err
+}
+
+func (s *AvroSerializer) serializeParts(c client.Registry, schemaName string, v interface{}) ([]byte, []byte, error) {
+	message, err := s.appendMessage(c, schemaName, v, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return message[:HeaderSize:HeaderSize], message[HeaderSize:], nil
+}
+
+// DeserializeParts deserializes a header and body produced by SerializeParts into out,
+// as Deserialize does. With a *client.GlueSchemaRegistryClient the header may name any
+// version of the schema. Other registries must be able to serve the version by number: it
+// must be the latest version of the schema, one of the versions configured with
+// WithDecodeFallbackVersions, or a version the serializer has used before, such as a
+// previous latest version or one passed to SerializeWithVersion.
+func (s *AvroSerializer) DeserializeParts(c client.Registry, schemaName string, header, body []byte, out interface{}) error {
+	start := time.Now()
+	err := s.deserializeParts(c, schemaName, header, body, out)
+	s.recordSerde(opDeserialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+func (s *AvroSerializer) deserializeParts(c client.Registry, schemaName string, header, body []byte, out interface{}) error {
+	if err := checkOut(out); err != nil {
+		return err
+	}
+	if err := s.checkPayloadSize(body); err != nil {
+		return err
+	}
+	schemaVersionID, compression, err := decodeHeader(header)
+	if err != nil {
+		return err
+	}
+	if compression == compressionZlib {
+		if body, err = s.decompressBody(body); err != nil {
+			return err
+		}
+	}
+
+	cs, err := s.compiledForID(c, schemaName, schemaVersionID)
+	if err != nil {
+		return err
+	}
+
+	record, err := decodeRecord(cs.codec, body)
+	if err != nil {
+		return err
+	}
+	return cs.fromNative(record, out)
+}
+
+// compiledForID returns the compiled schema version with the given version ID. Registries
+// that look versions up by ID, such as *client.GlueSchemaRegistryClient, can decode any
+// version of the schema. On other registries only the latest version, the versions
+// configured with WithDecodeFallbackVersions and versions the serializer has already
+// compiled, for example for SerializeWithVersion, are considered.
+func (s *AvroSerializer) compiledForID(c client.Registry, schemaName, schemaVersionID string) (*compiledSchema, error) {
+	if cs, ok := s.codecs.getID(schemaVersionID); ok {
+		return cs, checkVersionSchema(cs.version, schemaName)
+	}
+	version, ok, err := s.versionByID(c, schemaVersionID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err := checkVersionSchema(version, schemaName); err != nil {
+			return nil, err
+		}
+		cs, err := compileSchema(version)
+		if err != nil {
+			return nil, err
+		}
+		s.codecs.putID(cs)
+		return cs, nil
+	}
+
+	cs, err := s.latestCompiled(c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	latestID := aws.StringValue(cs.version.SchemaVersionId)
+	if latestID == schemaVersionID {
+		return cs, nil
+	}
+
+	for _, number := range s.decodeFallbackVersions {
+		cs, err := s.versionCompiled(c, schemaName, number)
+		if err != nil || aws.StringValue(cs.version.SchemaVersionId) != schemaVersionID {
+			continue
+		}
+		return cs, nil
+	}
+	return nil, fmt.Errorf("payload was written with schema version %s, but the latest version is %s", schemaVersionID, latestID)
+}
+
+// checkVersionSchema returns an error if version, looked up by ID, belongs to a schema
+// other than schemaName. Versions without an ARN are not checked.
+func checkVersionSchema(version *glue.GetSchemaVersionOutput, schemaName string) error {
+	if arn := aws.StringValue(version.SchemaArn); arn != "" && !strings.HasSuffix(arn, "/"+schemaName) {
+		return fmt.Errorf("payload was written with schema version %s of %s, not of schema %s",
+			aws.StringValue(version.SchemaVersionId), arn, schemaName)
+	}
+	return nil
+}
+
+// SerializeWithHeader serializes v like Serialize and prepends the Glue wire-format
+// header, as the AWS Glue Schema Registry serializers for Java and Python do, so that
+// consumers can tell which schema version the payload was written with
+func (s *AvroSerializer) SerializeWithHeader(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	start := time.Now()
+	header, body, err := s.serializeParts(c, schemaName, v)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return append(header, body...), nil
+}
+
+// DeserializeWithHeader deserializes a payload framed with the Glue wire-format header,
+// such as one written by SerializeWithHeader or by the AWS Glue Schema Registry
+// serializers for Java and Python, into out. Which versions can be decoded is as for
+// DeserializeParts. Bodies compressed with zlib, as WithZlibCompression and the Java
+// serializers write them, are decompressed transparently; other compression bytes return
+// ErrInvalidHeader.
+func (s *AvroSerializer) DeserializeWithHeader(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	start := time.Now()
+	err := s.deserializeWithHeader(c, schemaName, data, out)
+	s.recordSerde(opDeserialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+func (s *AvroSerializer) deserializeWithHeader(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	if err := s.checkPayloadSize(data); err != nil {
+		return err
+	}
+	if len(data) < HeaderSize {
+		return fmt.Errorf("%w: payload is %d bytes, shorter than the header", ErrInvalidHeader, len(data))
+	}
+	return s.deserializeParts(c, schemaName, data[:HeaderSize], data[HeaderSize:], out)
+}
@@ -0,0 +1,220 @@
+package serializer_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestAvroSerializePartsRoundTrip(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	created, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	avroSerializer := serializer.NewAvroSerializer()
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	header, body, err := avroSerializer.SerializeParts(c, "SalesforceAudit", originalEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize parts: %v", err)
+	}
+	if len(header) != serializer.HeaderSize || header[0] != 3 || header[1] != 0 {
+		t.Fatalf("Header mismatch: got %x", header)
+	}
+	versionID, err := hex.DecodeString(strings.ReplaceAll(*created.SchemaVersionId, "-", ""))
+	if err != nil {
+		t.Fatalf("Failed to decode schema version ID: %v", err)
+	}
+	if !bytes.Equal(header[2:], versionID) {
+		t.Errorf("Header schema version mismatch: expected %x, got %x", versionID, header[2:])
+	}
+
+	// Storing the parts apart must not matter: copy them into independent slices
+	storedHeader := append([]byte(nil), header...)
+	storedBody := append([]byte(nil), body...)
+	deserializedEvent := &model.SalesforceAudit{}
+	err = avroSerializer.DeserializeParts(c, "SalesforceAudit", storedHeader, storedBody, deserializedEvent)
+	if err != nil {
+		t.Fatalf("Failed to deserialize parts: %v", err)
+	}
+	if *deserializedEvent != *originalEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *originalEvent, *deserializedEvent)
+	}
+
+	if err := avroSerializer.DeserializeParts(c, "SalesforceAudit", header[:4], body, &model.SalesforceAudit{}); !errors.Is(err, serializer.ErrInvalidHeader) {
+		t.Errorf("Expected ErrInvalidHeader, got %v", err)
+	}
+}
+
+func TestAvroDeserializeWithHeaderAnyVersion(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	for _, name := range []string{"SalesforceAudit", "OtherAudit"} {
+		if _, err := c.CreateSchema(name, client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+			t.Fatalf("Failed to create schema %s: %v", name, err)
+		}
+	}
+
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	framed, err := serializer.NewAvroSerializer().SerializeWithHeader(c, "SalesforceAudit", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize with header: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.RegisterSchemaVersion("SalesforceAudit", strings.Replace(gluetest.SalesforceAuditAvroSchema,
+			"Schema for Salesforce audit events", fmt.Sprintf("Salesforce audit events, v%d", i+2), 1)); err != nil {
+			t.Fatalf("Failed to register schema version: %v", err)
+		}
+	}
+
+	// Version 1 is neither latest nor a fallback, but is found by the ID in the header
+	s := serializer.NewAvroSerializer()
+	before := fake.Calls("GetSchemaVersion")
+	for i := 0; i < 2; i++ {
+		var decoded model.SalesforceAudit
+		if err := s.DeserializeWithHeader(c, "SalesforceAudit", framed, &decoded); err != nil {
+			t.Fatalf("Failed to deserialize with header: %v", err)
+		}
+		if decoded != *event {
+			t.Errorf("Event mismatch: expected %+v, got %+v", *event, decoded)
+		}
+	}
+	if calls := fake.Calls("GetSchemaVersion") - before; calls != 1 {
+		t.Errorf("Expected the version to be fetched once, got %d GetSchemaVersion calls", calls)
+	}
+
+	if err := s.DeserializeWithHeader(c, "OtherAudit", framed, &model.SalesforceAudit{}); err == nil {
+		t.Error("Expected error decoding a payload written with a version of another schema")
+	}
+}
+
+func TestAvroSerializeWithHeader(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	created, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+	framed, err := serializer.NewAvroSerializer().SerializeWithHeader(c, "SalesforceAudit", originalEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize with header: %v", err)
+	}
+
+	// The framing is version byte 3, compression byte 0, then the schema version UUID
+	versionID, err := hex.DecodeString(strings.ReplaceAll(*created.SchemaVersionId, "-", ""))
+	if err != nil {
+		t.Fatalf("Failed to decode schema version ID: %v", err)
+	}
+	expectedHeader := append([]byte{3, 0}, versionID...)
+	if !bytes.HasPrefix(framed, expectedHeader) {
+		t.Fatalf("Header mismatch: expected %x, got %x", expectedHeader, framed[:serializer.HeaderSize])
+	}
+
+	// Version 2 becomes the latest; the framed payload still names version 1
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"type": "string", "doc": "Detailed information about the audit event"}`,
+		`"type": "string", "doc": "Detailed information about the audit event"}, {"name": "source", "type": "string", "default": "salesforce"}`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withSource); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	// A registry without version lookups by ID can only decode it with a fallback version
+	byNumberOnly := struct{ client.Registry }{c}
+	if err := serializer.NewAvroSerializer().DeserializeWithHeader(byNumberOnly, "SalesforceAudit", framed, &model.SalesforceAudit{}); err == nil {
+		t.Error("Expected error decoding a payload written with a version that is neither latest nor a fallback")
+	}
+
+	avroSerializer := serializer.NewAvroSerializer(serializer.WithDecodeFallbackVersions([]int64{1}))
+	deserializedEvent := &model.SalesforceAudit{}
+	if err := avroSerializer.DeserializeWithHeader(byNumberOnly, "SalesforceAudit", framed, deserializedEvent); err != nil {
+		t.Fatalf("Failed to deserialize with header: %v", err)
+	}
+	if *deserializedEvent != *originalEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *originalEvent, *deserializedEvent)
+	}
+
+	if err := avroSerializer.DeserializeWithHeader(c, "SalesforceAudit", framed[:10], &model.SalesforceAudit{}); !errors.Is(err, serializer.ErrInvalidHeader) {
+		t.Errorf("Expected ErrInvalidHeader for a truncated payload, got %v", err)
+	}
+	unsupported := append([]byte(nil), framed...)
+	unsupported[1] = 1
+	if err := avroSerializer.DeserializeWithHeader(c, "SalesforceAudit", unsupported, &model.SalesforceAudit{}); !errors.Is(err, serializer.ErrInvalidHeader) {
+		t.Errorf("Expected ErrInvalidHeader for an unsupported compression byte, got %v", err)
+	}
+}
+
+func TestAvroSerializeWithHeaderZlib(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: strings.Repeat("User logged in successfully. ", 40),
+	}
+	plain, err := serializer.NewAvroSerializer().SerializeWithHeader(c, "SalesforceAudit", originalEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize with header: %v", err)
+	}
+	compressed, err := serializer.NewAvroSerializer(serializer.WithZlibCompression()).SerializeWithHeader(c, "SalesforceAudit", originalEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize with zlib compression: %v", err)
+	}
+	if compressed[1] != 5 {
+		t.Errorf("Compression byte mismatch: expected 5, got %d", compressed[1])
+	}
+	if !bytes.Equal(compressed[2:serializer.HeaderSize], plain[2:serializer.HeaderSize]) {
+		t.Error("Expected the compressed payload to name the same schema version")
+	}
+	if len(compressed) >= len(plain) {
+		t.Errorf("Expected compression to shrink a repetitive record: %d bytes compressed, %d plain", len(compressed), len(plain))
+	}
+
+	// One deserializer reads both, as a consumer of a topic with mixed producers would
+	avroSerializer := serializer.NewAvroSerializer()
+	for name, framed := range map[string][]byte{"uncompressed": plain, "zlib": compressed} {
+		var decoded model.SalesforceAudit
+		if err := avroSerializer.DeserializeWithHeader(c, "SalesforceAudit", framed, &decoded); err != nil {
+			t.Fatalf("Failed to deserialize %s payload: %v", name, err)
+		}
+		if decoded != *originalEvent {
+			t.Errorf("Event mismatch for %s payload: expected %+v, got %+v", name, *originalEvent, decoded)
+		}
+	}
+
+	limited := serializer.NewAvroSerializer(serializer.WithMaxPayloadSize(len(compressed)))
+	if err := limited.DeserializeWithHeader(c, "SalesforceAudit", compressed, &model.SalesforceAudit{}); !errors.Is(err, serializer.ErrPayloadTooLarge) {
+		t.Errorf("Expected ErrPayloadTooLarge once the decompressed record exceeds the limit, got %v", err)
+	}
+	corrupt := append([]byte(nil), plain...)
+	corrupt[1] = 5
+	if err := avroSerializer.DeserializeWithHeader(c, "SalesforceAudit", corrupt, &model.SalesforceAudit{}); err == nil {
+		t.Error("Expected error decompressing a body that is not zlib data")
+	}
+}
@@ -1,83 +1,226 @@
 package serializer
 
 import (
-	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/aws-glue-schema-registry/golang/client"
-	"github.com/aws-glue-schema-registry/golang/model"
 	"github.com/linkedin/goavro/v2"
 )
 
-// AvroSerializer provides Avro serialization/deserialization
-type AvroSerializer struct{}
+// binaryFromNative encodes a native datum; it is a variable so tests can substitute a faulty encoder
+var binaryFromNative = (*goavro.Codec).BinaryFromNative
+
+// AvroSerializer provides Avro serialization/deserialization.
+// The zero value is ready to use; NewAvroSerializer applies options.
+//
+// An AvroSerializer caches the compiled codec of every schema version it has used, so it
+// should be created once and reused rather than created per message. It is safe for
+// concurrent use and must not be copied after first use.
+type AvroSerializer struct {
+	options
+	codecs codecCache
+}
 
-// Serialize serializes a SalesforceAudit object to Avro binary format
-func (s *AvroSerializer) Serialize(c *client.GlueSchemaRegistryClient, schemaName string, auditEvent *model.SalesforceAudit) ([]byte, error) {
-	// Get schema definition from Glue Schema Registry
-	schemaResponse, err := c.GetSchema(schemaName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema: %w", err)
+// NewAvroSerializer creates an AvroSerializer configured with the given options
+func NewAvroSerializer(opts ...Option) *AvroSerializer {
+	return &AvroSerializer{options: newOptions(opts)}
+}
+
+// NewAvroSerializerForSchema creates an AvroSerializer like NewAvroSerializer and
+// prefetches the latest version of schemaName, as Prefetch does, so that a missing schema
+// or an invalid definition fails at startup rather than on the first Serialize
+func NewAvroSerializerForSchema(c client.Registry, schemaName string, opts ...Option) (*AvroSerializer, error) {
+	s := NewAvroSerializer(opts...)
+	if err := s.Prefetch(c, schemaName); err != nil {
+		return nil, err
 	}
+	return s, nil
+}
 
-	latestVersion := *schemaResponse.LatestSchemaVersion
-	schemaVersionResponse, err := c.GetSchemaVersion(schemaName, latestVersion)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema version: %w", err)
+// Prefetch fetches and compiles the latest version of each schema into the serializer's
+// cache of compiled schemas, and returns the first error. Serialize and Deserialize then
+// use the cached codec for as long as the version stays the latest.
+func (s *AvroSerializer) Prefetch(c client.Registry, schemaNames ...string) error {
+	for _, schemaName := range schemaNames {
+		if _, err := s.latestCompiled(c, schemaName); err != nil {
+			return fmt.Errorf("failed to prefetch schema %s: %w", schemaName, err)
+		}
 	}
+	return nil
+}
 
-	schemaDefinition := *schemaVersionResponse.SchemaDefinition
+// Serialize serializes v to Avro binary format. v may be a struct or pointer to one whose
+// fields are matched to the record's fields by their avro or json tag, a type with a
+// ToMap method such as model.SalesforceAudit, or a native record as for SerializeMap.
+func (s *AvroSerializer) Serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	start := time.Now()
+	binary, err := s.serialize(c, schemaName, v)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return binary, err
+}
 
-	// Parse Avro schema
-	var schemaJSON map[string]interface{}
-	if err := json.Unmarshal([]byte(schemaDefinition), &schemaJSON); err != nil {
-		return nil, fmt.Errorf("failed to parse schema definition: %w", err)
-	}
+// SerializeMap serializes a native record to Avro binary format without going through a
+// model type. The record's keys must match the schema's fields; fields with a default may
+// be omitted.
+func (s *AvroSerializer) SerializeMap(c client.Registry, schemaName string, record map[string]interface{}) ([]byte, error) {
+	start := time.Now()
+	binary, err := s.serializeMap(c, schemaName, record)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return binary, err
+}
 
-	codec, err := goavro.NewCodec(schemaDefinition)
+func (s *AvroSerializer) serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	cs, err := s.writeCompiled(c, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Avro codec: %w", err)
+		return nil, err
+	}
+	record, err := cs.toRecord(v)
+	if err != nil {
+		return nil, err
 	}
+	return s.encode(cs, record)
+}
 
-	// Create a record
-	record := auditEvent.ToMap()
+func (s *AvroSerializer) serializeMap(c client.Registry, schemaName string, record map[string]interface{}) ([]byte, error) {
+	return s.serialize(c, schemaName, record)
+}
+
+// encode validates record against a compiled Avro record schema and encodes it
+func (s *AvroSerializer) encode(cs *compiledSchema, record map[string]interface{}) ([]byte, error) {
+	return s.appendEncoded(cs, nil, record)
+}
+
+// appendEncoded is like encode but appends the encoded record to dst
+func (s *AvroSerializer) appendEncoded(cs *compiledSchema, dst []byte, record map[string]interface{}) ([]byte, error) {
+	if err := validateRecordKeys(cs.schema, record); err != nil {
+		return nil, err
+	}
 
 	// Serialize to bytes using BinaryFromNative
-	binary, err := codec.BinaryFromNative(nil, record)
+	var binary []byte
+	var err error
+	if s.sortMapKeys {
+		binary, err = cs.sorted.BinaryFromNative(dst, record)
+	} else {
+		binary, err = binaryFromNative(cs.codec, dst, record)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode record: %w", err)
 	}
 
+	if s.verifyRoundTrip {
+		if err := verifyAvroRoundTrip(cs.codec, binary[len(dst):], record); err != nil {
+			return nil, err
+		}
+	}
+
 	return binary, nil
 }
 
-// Deserialize deserializes Avro binary data to a SalesforceAudit object
-func (s *AvroSerializer) Deserialize(c *client.GlueSchemaRegistryClient, schemaName string, data []byte) (*model.SalesforceAudit, error) {
-	// Get schema definition from Glue Schema Registry
-	schemaResponse, err := c.GetSchema(schemaName)
+// Deserialize deserializes Avro binary data into out, which must be a non-nil pointer to
+// a struct, to a type with a FromMap method such as model.SalesforceAudit, or to a
+// map[string]interface{} that receives the record in goavro's native form.
+func (s *AvroSerializer) Deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	start := time.Now()
+	err := s.deserialize(c, schemaName, data, out)
+	s.recordSerde(opDeserialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+func (s *AvroSerializer) deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	if err := checkOut(out); err != nil {
+		return err
+	}
+	if err := s.checkPayloadSize(data); err != nil {
+		return err
+	}
+
+	record, cs, err := s.decodeLatest(c, schemaName, data)
+	if err != nil {
+		record, cs, err = s.decodeFallback(c, schemaName, data, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cs.fromNative(record, out)
+}
+
+// DeserializeToMap deserializes Avro binary data like Deserialize and returns the record
+// as goavro decodes it, without binding it to a Go type: records are maps, union values
+// are single-entry maps keyed by the branch type, and longs are int64. It is meant for
+// inspecting payloads of unknown shape.
+func (s *AvroSerializer) DeserializeToMap(c client.Registry, schemaName string, data []byte) (map[string]interface{}, error) {
+	start := time.Now()
+	record, err := s.deserializeToMap(c, schemaName, data)
+	s.recordSerde(opDeserialize, schemaName, DataFormatAvro, start, err)
+	return record, err
+}
+
+func (s *AvroSerializer) deserializeToMap(c client.Registry, schemaName string, data []byte) (map[string]interface{}, error) {
+	if err := s.checkPayloadSize(data); err != nil {
+		return nil, err
+	}
+
+	record, _, err := s.decodeLatest(c, schemaName, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema: %w", err)
+		record, _, err = s.decodeFallback(c, schemaName, data, err)
 	}
+	return record, err
+}
 
-	latestVersion := *schemaResponse.LatestSchemaVersion
-	schemaVersionResponse, err := c.GetSchemaVersion(schemaName, latestVersion)
+// decodeLatest decodes data against the latest version of the schema
+func (s *AvroSerializer) decodeLatest(c client.Registry, schemaName string, data []byte) (map[string]interface{}, *compiledSchema, error) {
+	cs, err := s.latestCompiled(c, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema version: %w", err)
+		return nil, nil, err
 	}
+	record, err := decodeRecord(cs.codec, data)
+	return record, cs, err
+}
 
-	schemaDefinition := *schemaVersionResponse.SchemaDefinition
+// decodeFallback tries the configured fallback versions in order after decoding against
+// the primary version failed with cause. It returns cause if no fallback decodes data.
+// Like other versions, fallback versions are compiled once and cached.
+func (s *AvroSerializer) decodeFallback(c client.Registry, schemaName string, data []byte, cause error) (map[string]interface{}, *compiledSchema, error) {
+	for _, number := range s.decodeFallbackVersions {
+		cs, err := s.versionCompiled(c, schemaName, number)
+		if err != nil {
+			continue
+		}
+		if record, err := decodeRecord(cs.codec, data); err == nil {
+			return record, cs, nil
+		}
+	}
+	return nil, nil, cause
+}
 
+// DeserializeGeneric decodes Avro binary data against the given schema definition
+// and returns the decoded record as a native map. It does not contact the registry
+// and is not tied to any model type, which makes it suitable for inspecting payloads
+// of unknown shape.
+func DeserializeGeneric(definition string, data []byte) (map[string]interface{}, error) {
 	// Parse Avro schema
-	codec, err := goavro.NewCodec(schemaDefinition)
+	codec, err := goavro.NewCodec(definition)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Avro codec: %w", err)
 	}
+	return decodeRecord(codec, data)
+}
 
+// decodeRecord decodes Avro binary data that holds a single record. Bytes left over after
+// the record are an error: they mean data was written with a schema that has more fields,
+// which would otherwise be dropped without notice.
+func decodeRecord(codec *goavro.Codec, data []byte) (map[string]interface{}, error) {
 	// Deserialize from bytes using NativeFromBinary
-	datum, _, err := codec.NativeFromBinary(data)
+	datum, remaining, err := codec.NativeFromBinary(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode record: %w", err)
 	}
+	if len(remaining) > 0 {
+		return nil, fmt.Errorf("failed to decode record: %d trailing bytes after the record", len(remaining))
+	}
 
 	// Convert to map
 	record, ok := datum.(map[string]interface{})
@@ -85,9 +228,5 @@ func (s *AvroSerializer) Deserialize(c *client.GlueSchemaRegistryClient, schemaN
 		return nil, fmt.Errorf("unexpected datum type: %T", datum)
 	}
 
-	// Create SalesforceAudit object from record
-	auditEvent := &model.SalesforceAudit{}
-	auditEvent.FromMap(record)
-
-	return auditEvent, nil
+	return record, nil
 }
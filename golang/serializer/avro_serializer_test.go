@@ -1,15 +1,23 @@
 package serializer_test
 
 import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
 	"github.com/aws-glue-schema-registry/golang/model"
 	"github.com/aws-glue-schema-registry/golang/serializer"
 	"github.com/aws-glue-schema-registry/golang/testconfig"
+	"github.com/linkedin/goavro/v2"
 )
 
 func TestAvroSerialization(t *testing.T) {
+	testconfig.RequireAWSCredentials(t)
 	cfg := testconfig.LoadConfig()
 	c, err := client.NewGlueSchemaRegistryClient(cfg.AWSRegion, cfg.RegistryName)
 	if err != nil {
@@ -39,7 +47,8 @@ func TestAvroSerialization(t *testing.T) {
 	}
 
 	// Deserialize
-	deserializedEvent, err := avroSerializer.Deserialize(c, schemaName, serializedData)
+	deserializedEvent := &model.SalesforceAudit{}
+	err = avroSerializer.Deserialize(c, schemaName, serializedData, deserializedEvent)
 	if err != nil {
 		t.Fatalf("Failed to deserialize: %v", err)
 	}
@@ -59,3 +68,348 @@ func TestAvroSerialization(t *testing.T) {
 	}
 }
 
+func TestAvroSerializeNullableField(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditNullableAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	s := serializer.NewAvroSerializer()
+	for _, details := range []string{"User logged in successfully", ""} {
+		original := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: details}
+		data, err := s.Serialize(c, "SalesforceAudit", original)
+		if err != nil {
+			t.Fatalf("Failed to serialize with eventDetails %q: %v", details, err)
+		}
+		var decoded model.SalesforceAudit
+		if err := s.Deserialize(c, "SalesforceAudit", data, &decoded); err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+		if decoded != *original {
+			t.Errorf("Round trip mismatch: expected %+v, got %+v", *original, decoded)
+		}
+	}
+
+	// A record written with eventDetails null decodes to the zero value
+	codec, err := goavro.NewCodec(gluetest.SalesforceAuditNullableAvroSchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	data, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"eventId": "event-12345", "eventName": "UserLogin", "timestamp": int64(1704067200000), "eventDetails": nil,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	var decoded model.SalesforceAudit
+	if err := s.Deserialize(c, "SalesforceAudit", data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if decoded.EventDetails != "" || decoded.EventID != "event-12345" {
+		t.Errorf("Null eventDetails mismatch: got %+v", decoded)
+	}
+}
+
+func TestAvroSerializeTimestampMillis(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditTimestampMillisAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	s := serializer.NewAvroSerializer(serializer.WithVerifyRoundTrip())
+	original := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200123, EventDetails: "User logged in"}
+	data, err := s.Serialize(c, "SalesforceAudit", original)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	var decoded model.SalesforceAudit
+	if err := s.Deserialize(c, "SalesforceAudit", data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if decoded != *original {
+		t.Errorf("Round trip mismatch: expected %+v, got %+v", *original, decoded)
+	}
+
+	record, err := s.DeserializeToMap(c, "SalesforceAudit", data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize to map: %v", err)
+	}
+	if timestamp, ok := record["timestamp"].(time.Time); !ok || !timestamp.Equal(time.UnixMilli(1704067200123)) {
+		t.Errorf("Timestamp mismatch: expected %v, got %v", time.UnixMilli(1704067200123).UTC(), record["timestamp"])
+	}
+}
+
+func TestAvroDeserializeToMap(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditNullableAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	s := serializer.NewAvroSerializer(serializer.WithMaxPayloadSize(256))
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in"}
+	data, err := s.Serialize(c, "SalesforceAudit", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	record, err := s.DeserializeToMap(c, "SalesforceAudit", data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize to map: %v", err)
+	}
+	expected := map[string]interface{}{
+		"eventId":      "event-12345",
+		"eventName":    "UserLogin",
+		"timestamp":    int64(1704067200000),
+		"eventDetails": map[string]interface{}{"string": "User logged in"},
+	}
+	if !reflect.DeepEqual(record, expected) {
+		t.Errorf("Record mismatch: expected %v, got %v", expected, record)
+	}
+
+	if _, err := s.DeserializeToMap(c, "SalesforceAudit", make([]byte, 257)); !errors.Is(err, serializer.ErrPayloadTooLarge) {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+	if _, err := s.DeserializeToMap(c, "SalesforceAudit", []byte{0xff}); err == nil {
+		t.Error("Expected decode error")
+	}
+}
+
+func TestDeserializeGeneric(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		record     map[string]interface{}
+	}{
+		{
+			name: "SalesforceAudit",
+			definition: `{
+				"type": "record",
+				"name": "SalesforceAudit",
+				"fields": [
+					{"name": "eventId", "type": "string"},
+					{"name": "eventName", "type": "string"},
+					{"name": "timestamp", "type": "long"},
+					{"name": "eventDetails", "type": "string"}
+				]
+			}`,
+			record: map[string]interface{}{
+				"eventId":      "event-12345",
+				"eventName":    "UserLogin",
+				"timestamp":    int64(1704067200000),
+				"eventDetails": "User logged in successfully",
+			},
+		},
+		{
+			name: "OrderPlaced",
+			definition: `{
+				"type": "record",
+				"name": "OrderPlaced",
+				"fields": [
+					{"name": "orderId", "type": "string"},
+					{"name": "quantity", "type": "int"},
+					{"name": "tags", "type": {"type": "array", "items": "string"}},
+					{"name": "paid", "type": "boolean"}
+				]
+			}`,
+			record: map[string]interface{}{
+				"orderId":  "order-1",
+				"quantity": int32(3),
+				"tags":     []interface{}{"priority", "gift"},
+				"paid":     true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := goavro.NewCodec(tt.definition)
+			if err != nil {
+				t.Fatalf("Failed to create codec: %v", err)
+			}
+			data, err := codec.BinaryFromNative(nil, tt.record)
+			if err != nil {
+				t.Fatalf("Failed to encode record: %v", err)
+			}
+
+			decoded, err := serializer.DeserializeGeneric(tt.definition, data)
+			if err != nil {
+				t.Fatalf("Failed to deserialize: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.record, decoded) {
+				t.Errorf("Record mismatch: expected %v, got %v", tt.record, decoded)
+			}
+		})
+	}
+}
+
+func TestDeserializeGenericInvalidSchema(t *testing.T) {
+	if _, err := serializer.DeserializeGeneric(`{"type": "record"}`, []byte{0x00}); err == nil {
+		t.Fatal("Expected error for invalid schema definition")
+	}
+}
+
+func TestAvroDeserializeRejectsOversizedPayload(t *testing.T) {
+	c, err := client.NewGlueSchemaRegistryClient("us-east-1", "test-registry")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	avroSerializer := serializer.NewAvroSerializer(serializer.WithMaxPayloadSize(16))
+
+	err = avroSerializer.Deserialize(c, "SalesforceAudit", make([]byte, 17), &model.SalesforceAudit{})
+	if !errors.Is(err, serializer.ErrPayloadTooLarge) {
+		t.Fatalf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestAvroSerializeVerifyRoundTrip(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	avroSerializer := serializer.NewAvroSerializer(serializer.WithVerifyRoundTrip())
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	if _, err := avroSerializer.Serialize(c, "SalesforceAudit", originalEvent); err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	// Corrupt the encoder so that it silently writes a different event name
+	restore := serializer.SetBinaryFromNative(func(codec *goavro.Codec, buf []byte, datum interface{}) ([]byte, error) {
+		corrupted := make(map[string]interface{})
+		for k, v := range datum.(map[string]interface{}) {
+			corrupted[k] = v
+		}
+		corrupted["eventName"] = "UserLogout"
+		return codec.BinaryFromNative(buf, corrupted)
+	})
+	defer restore()
+
+	_, err := avroSerializer.Serialize(c, "SalesforceAudit", originalEvent)
+	if !errors.Is(err, serializer.ErrRoundTripMismatch) {
+		t.Fatalf("Expected ErrRoundTripMismatch, got %v", err)
+	}
+}
+
+func TestAvroVerifyRoundTripNumbers(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	schema := `{"type": "record", "name": "Reading", "fields": [
+		{"name": "id", "type": "long"},
+		{"name": "value", "type": "double"}
+	]}`
+	if _, err := c.CreateSchema("Reading", "AVRO", schema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// A NaN double and a long beyond 2^53 both round-trip exactly
+	avroSerializer := serializer.NewAvroSerializer(serializer.WithVerifyRoundTrip())
+	record := map[string]interface{}{"id": int64(1<<53 + 1), "value": math.NaN()}
+	if _, err := avroSerializer.SerializeMap(c, "Reading", record); err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	// Corrupt the encoder so that it writes the neighbouring long, which converts to the
+	// same float64
+	restore := serializer.SetBinaryFromNative(func(codec *goavro.Codec, buf []byte, datum interface{}) ([]byte, error) {
+		corrupted := make(map[string]interface{})
+		for k, v := range datum.(map[string]interface{}) {
+			corrupted[k] = v
+		}
+		corrupted["id"] = int64(1 << 53)
+		return codec.BinaryFromNative(buf, corrupted)
+	})
+	defer restore()
+	if _, err := avroSerializer.SerializeMap(c, "Reading", record); !errors.Is(err, serializer.ErrRoundTripMismatch) {
+		t.Fatalf("Expected ErrRoundTripMismatch, got %v", err)
+	}
+}
+
+func TestAvroDeserializeFallbackVersions(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+	serializedData, err := serializer.NewAvroSerializer().Serialize(c, "SalesforceAudit", originalEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	// Version 2 adds a trailing field without a default, so version 1 payloads no longer decode against it
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"type": "string", "doc": "Detailed information about the audit event"}`,
+		`"type": "string", "doc": "Detailed information about the audit event"}, {"name": "source", "type": "string"}`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withSource); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	if err := serializer.NewAvroSerializer().Deserialize(c, "SalesforceAudit", serializedData, &model.SalesforceAudit{}); err == nil {
+		t.Fatal("Expected error decoding against the latest version without fallbacks")
+	}
+
+	avroSerializer := serializer.NewAvroSerializer(serializer.WithDecodeFallbackVersions([]int64{7, 1}))
+	deserializedEvent := &model.SalesforceAudit{}
+	err = avroSerializer.Deserialize(c, "SalesforceAudit", serializedData, deserializedEvent)
+	if err != nil {
+		t.Fatalf("Failed to deserialize with fallback: %v", err)
+	}
+	if *deserializedEvent != *originalEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *originalEvent, *deserializedEvent)
+	}
+}
+
+func TestAvroDeserializeFallbackSkipsVersionsWithFewerFields(t *testing.T) {
+	const v1 = `{"type": "record", "name": "Event", "fields": [{"name": "a", "type": "string"}]}`
+	const v2 = `{"type": "record", "name": "Event", "fields": [{"name": "a", "type": "string"}, {"name": "b", "type": "string"}]}`
+	const v3 = `{"type": "record", "name": "Event", "fields": [{"name": "a", "type": "string"}, {"name": "b", "type": "string"}, {"name": "c", "type": "long"}]}`
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("Event", "AVRO", v1, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.RegisterSchemaVersion("Event", v2); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	data, err := serializer.NewAvroSerializer().Serialize(c, "Event", map[string]interface{}{"a": "x", "b": "y"})
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if _, err := c.RegisterSchemaVersion("Event", v3); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+
+	// Version 1 reads field a and would leave field b behind; it must not win over version 2
+	avroSerializer := serializer.NewAvroSerializer(serializer.WithDecodeFallbackVersions([]int64{1, 2}))
+	for i := 0; i < 2; i++ {
+		record, err := avroSerializer.DeserializeToMap(c, "Event", data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize with fallback: %v", err)
+		}
+		expected := map[string]interface{}{"a": "x", "b": "y"}
+		if !reflect.DeepEqual(record, expected) {
+			t.Errorf("Record mismatch: expected %v, got %v", expected, record)
+		}
+	}
+	if _, err := serializer.DeserializeGeneric(v1, data); err == nil {
+		t.Error("Expected an error for bytes left over after the record")
+	}
+}
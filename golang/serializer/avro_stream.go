@@ -0,0 +1,163 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// lengthPrefixSize is the length in bytes of the big-endian message length that precedes
+// each framed message in a stream
+const lengthPrefixSize = 4
+
+// maxPooledBufferSize is the capacity beyond which a buffer of encodeBuffers is dropped
+// after use instead of being kept for the next message, so that one large message does not
+// pin its memory
+const maxPooledBufferSize = 1 << 20
+
+// encodeBuffers holds the buffers SerializeToWriter and SerializeTo encode messages into
+var encodeBuffers = sync.Pool{New: func() interface{} {
+	buf := make([]byte, 0, 1024)
+	return &buf
+}}
+
+// SerializeToWriter serializes v like SerializeWithHeader and writes the header and Avro
+// body to w in a single Write. The output is encoded into a buffer reused across calls,
+// and compressed with a reused zlib writer under WithZlibCompression, rather than into a
+// new byte slice per message; w must not retain the slice passed to Write, as
+// bytes.Buffer and bufio.Writer do not.
+func (s *AvroSerializer) SerializeToWriter(c client.Registry, schemaName string, v interface{}, w io.Writer) error {
+	start := time.Now()
+	buf := encodeBuffers.Get().(*[]byte)
+	message, err := s.appendMessage(c, schemaName, v, (*buf)[:0])
+	if err == nil {
+		_, err = w.Write(message)
+		err = wrapWriteError(err)
+	}
+	putEncodeBuffer(buf, message)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+// SerializeTo serializes v like SerializeWithHeader and writes the framed message to w,
+// preceded by its length as a 4-byte big-endian integer, so that DeserializeFrom can read
+// it back from a stream of such messages. Like SerializeToWriter, it encodes into a reused
+// buffer.
+func (s *AvroSerializer) SerializeTo(c client.Registry, schemaName string, v interface{}, w io.Writer) error {
+	start := time.Now()
+	buf := encodeBuffers.Get().(*[]byte)
+	message, err := s.appendMessage(c, schemaName, v, append((*buf)[:0], make([]byte, lengthPrefixSize)...))
+	if err == nil {
+		binary.BigEndian.PutUint32(message, uint32(len(message)-lengthPrefixSize))
+		_, err = w.Write(message)
+		err = wrapWriteError(err)
+	}
+	putEncodeBuffer(buf, message)
+	s.recordSerde(opSerialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+// appendMessage appends v, serialized like SerializeWithHeader, to dst
+func (s *AvroSerializer) appendMessage(c client.Registry, schemaName string, v interface{}, dst []byte) ([]byte, error) {
+	cs, err := s.writeCompiled(c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	record, err := cs.toRecord(v)
+	if err != nil {
+		return nil, err
+	}
+
+	compression := compressionNone
+	if s.zlibCompression {
+		compression = compressionZlib
+	}
+	header, err := encodeHeader(aws.StringValue(cs.version.SchemaVersionId), compression)
+	if err != nil {
+		return nil, err
+	}
+	start := len(dst)
+	message, err := s.appendEncoded(cs, append(dst, header...), record)
+	if err != nil {
+		return nil, err
+	}
+	if s.zlibCompression {
+		if message, err = appendCompressed(message[:start+HeaderSize], message[start+HeaderSize:]); err != nil {
+			return nil, err
+		}
+	}
+	return message, nil
+}
+
+// putEncodeBuffer returns buf, which now holds message, to encodeBuffers unless it grew
+// too large to keep
+func putEncodeBuffer(buf *[]byte, message []byte) {
+	if cap(message) > cap(*buf) {
+		*buf = message
+	}
+	if cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	*buf = (*buf)[:0]
+	encodeBuffers.Put(buf)
+}
+
+func wrapWriteError(err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// DeserializeFrom reads one length-prefixed message, as written by SerializeTo, from r and
+// deserializes it into out like DeserializeWithHeader. Only that message is read from r,
+// so calling it in a loop processes a stream without holding more than one message in
+// memory. At the end of the stream it returns io.EOF; a stream that ends partway through
+// a message returns an error matching io.ErrUnexpectedEOF. With WithMaxPayloadSize, a
+// message longer than the limit fails with ErrPayloadTooLarge before it is read.
+func (s *AvroSerializer) DeserializeFrom(c client.Registry, schemaName string, r io.Reader, out interface{}) error {
+	start := time.Now()
+	data, err := s.readMessage(r)
+	if err == io.EOF {
+		return err
+	}
+	if err == nil {
+		err = s.deserializeWithHeader(c, schemaName, data, out)
+	}
+	s.recordSerde(opDeserialize, schemaName, DataFormatAvro, start, err)
+	return err
+}
+
+// readMessage reads one length-prefixed message from r. It returns io.EOF, unwrapped, only
+// if r is at the end of the stream before the message starts.
+func (s *AvroSerializer) readMessage(r io.Reader) ([]byte, error) {
+	var prefix [lengthPrefixSize]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read message length: %w", err)
+	}
+
+	n := binary.BigEndian.Uint32(prefix[:])
+	if s.maxPayloadSize > 0 && int64(n) > int64(s.maxPayloadSize) {
+		return nil, fmt.Errorf("%w: %d bytes (limit %d)", ErrPayloadTooLarge, n, s.maxPayloadSize)
+	}
+	// Read through a buffer rather than allocating n bytes up front, so that a corrupt
+	// length does not allocate more than the stream holds
+	var buf bytes.Buffer
+	read, err := buf.ReadFrom(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+	if read < int64(n) {
+		return nil, fmt.Errorf("failed to read message: %w after %d of %d bytes", io.ErrUnexpectedEOF, read, n)
+	}
+	return buf.Bytes(), nil
+}
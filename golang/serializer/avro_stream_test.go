@@ -0,0 +1,109 @@
+package serializer_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestAvroStreamRoundTrip(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	avroSerializer := serializer.NewAvroSerializer()
+	events := []model.SalesforceAudit{
+		{EventID: "event-1", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in"},
+		{EventID: "event-2", EventName: "UserLogout", Timestamp: 1704067260000},
+	}
+	var stream bytes.Buffer
+	for i := range events {
+		if err := avroSerializer.SerializeTo(c, "SalesforceAudit", &events[i], &stream); err != nil {
+			t.Fatalf("Failed to serialize event %d: %v", i, err)
+		}
+	}
+	framed := append([]byte(nil), stream.Bytes()...)
+
+	for i, expected := range events {
+		var decoded model.SalesforceAudit
+		if err := avroSerializer.DeserializeFrom(c, "SalesforceAudit", &stream, &decoded); err != nil {
+			t.Fatalf("Failed to deserialize event %d: %v", i, err)
+		}
+		if decoded != expected {
+			t.Errorf("Event %d mismatch: expected %+v, got %+v", i, expected, decoded)
+		}
+	}
+	if err := avroSerializer.DeserializeFrom(c, "SalesforceAudit", &stream, &model.SalesforceAudit{}); err != io.EOF {
+		t.Errorf("Expected io.EOF at the end of the stream, got %v", err)
+	}
+
+	truncated := bytes.NewReader(framed[:len(framed)-3])
+	if err := avroSerializer.DeserializeFrom(c, "SalesforceAudit", truncated, &model.SalesforceAudit{}); err != nil {
+		t.Fatalf("Failed to deserialize the first event: %v", err)
+	}
+	if err := avroSerializer.DeserializeFrom(c, "SalesforceAudit", truncated, &model.SalesforceAudit{}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF for a truncated message, got %v", err)
+	}
+
+	limited := serializer.NewAvroSerializer(serializer.WithMaxPayloadSize(serializer.HeaderSize))
+	if err := limited.DeserializeFrom(c, "SalesforceAudit", bytes.NewReader(framed), &model.SalesforceAudit{}); !errors.Is(err, serializer.ErrPayloadTooLarge) {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+// failingWriter fails every write with err
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestAvroSerializeToWriter(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in"}
+	for _, avroSerializer := range []*serializer.AvroSerializer{
+		serializer.NewAvroSerializer(),
+		serializer.NewAvroSerializer(serializer.WithZlibCompression()),
+	} {
+		expected, err := avroSerializer.SerializeWithHeader(c, "SalesforceAudit", event)
+		if err != nil {
+			t.Fatalf("Failed to serialize: %v", err)
+		}
+		// Writing twice reuses the pooled buffer of the first message
+		for i := 0; i < 2; i++ {
+			var out bytes.Buffer
+			if err := avroSerializer.SerializeToWriter(c, "SalesforceAudit", event, &out); err != nil {
+				t.Fatalf("Failed to serialize to writer: %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), expected) {
+				t.Errorf("Message mismatch: expected %x, got %x", expected, out.Bytes())
+			}
+			var decoded model.SalesforceAudit
+			if err := avroSerializer.DeserializeWithHeader(c, "SalesforceAudit", out.Bytes(), &decoded); err != nil {
+				t.Fatalf("Failed to deserialize: %v", err)
+			}
+			if decoded != *event {
+				t.Errorf("Event mismatch: expected %+v, got %+v", *event, decoded)
+			}
+		}
+	}
+
+	errClosed := errors.New("closed")
+	if err := serializer.NewAvroSerializer().SerializeToWriter(c, "SalesforceAudit", event, failingWriter{errClosed}); !errors.Is(err, errClosed) {
+		t.Errorf("Expected the write error, got %v", err)
+	}
+	if err := serializer.NewAvroSerializer().SerializeToWriter(c, "Missing", event, &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for a missing schema")
+	}
+}
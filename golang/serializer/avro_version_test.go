@@ -0,0 +1,68 @@
+package serializer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+// auditSchemaV2 adds an optional source field to the SalesforceAudit record
+var auditSchemaV2 = strings.Replace(gluetest.SalesforceAuditAvroSchema,
+	`{"name": "eventDetails", "type": "string", "doc": "Detailed information about the audit event"}`,
+	`{"name": "eventDetails", "type": "string", "doc": "Detailed information about the audit event"},
+    {"name": "source", "type": "string", "default": "web"}`, 1)
+
+func TestAvroSerializeWithVersion(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	avroSerializer := serializer.NewAvroSerializer()
+	auditEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+	framedV1, err := avroSerializer.SerializeWithHeader(c, "SalesforceAudit", auditEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize with header: %v", err)
+	}
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", auditSchemaV2); err != nil {
+		t.Fatalf("Failed to register version 2: %v", err)
+	}
+
+	pinned, err := avroSerializer.SerializeWithVersion(c, "SalesforceAudit", 1, auditEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize with version 1: %v", err)
+	}
+	var decoded model.SalesforceAudit
+	if err := avroSerializer.DeserializeWithVersion(c, "SalesforceAudit", 1, pinned, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize with version 1: %v", err)
+	}
+	if decoded != *auditEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *auditEvent, decoded)
+	}
+
+	if err := avroSerializer.Deserialize(c, "SalesforceAudit", pinned, &decoded); err == nil {
+		t.Error("Expected a version 1 payload not to decode against the latest version")
+	}
+	if _, err := avroSerializer.SerializeWithVersion(c, "SalesforceAudit", 3, auditEvent); err == nil {
+		t.Error("Expected error serializing with a version that does not exist")
+	}
+
+	// A message written while version 1 was the latest still decodes after version 2 is registered
+	decoded = model.SalesforceAudit{}
+	if err := avroSerializer.DeserializeWithHeader(c, "SalesforceAudit", framedV1, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize a version 1 message: %v", err)
+	}
+	if decoded != *auditEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *auditEvent, decoded)
+	}
+}
@@ -0,0 +1,88 @@
+package serializer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+)
+
+// Codec encodes and decodes the records of one schema in one registry
+type Codec interface {
+	// Encode encodes v using the latest version of the schema
+	Encode(v interface{}) ([]byte, error)
+	// Decode decodes data into out, which must be a non-nil pointer
+	Decode(data []byte, out interface{}) error
+}
+
+// CodecFactory creates the Codec for the schema named schemaName in c
+type CodecFactory func(c client.Registry, schemaName string) Codec
+
+// SerializerCodec returns a CodecFactory whose codecs encode with s.Serialize and decode
+// with s.Deserialize. The codecs share s, and so its caches.
+func SerializerCodec(s Serializer) CodecFactory {
+	return func(c client.Registry, schemaName string) Codec {
+		return serializerCodec{serializer: s, registry: c, schemaName: schemaName}
+	}
+}
+
+type serializerCodec struct {
+	serializer Serializer
+	registry   client.Registry
+	schemaName string
+}
+
+func (k serializerCodec) Encode(v interface{}) ([]byte, error) {
+	return k.serializer.Serialize(k.registry, k.schemaName, v)
+}
+
+func (k serializerCodec) Decode(data []byte, out interface{}) error {
+	return k.serializer.Deserialize(k.registry, k.schemaName, data, out)
+}
+
+// Codecs picks the Codec for a schema by the data format registered for it, so that the
+// same code can produce and consume schemas of any format. The zero value has no codecs
+// registered. A Codecs is safe for concurrent use.
+type Codecs struct {
+	mu        sync.RWMutex
+	factories map[client.DataFormat]CodecFactory
+}
+
+// NewCodecs creates a Codecs with a codec factory registered for each of AVRO, JSON and
+// PROTOBUF, backed by an AvroSerializer, JsonSerializer and ProtobufSerializer configured
+// with the given options
+func NewCodecs(opts ...Option) *Codecs {
+	r := &Codecs{}
+	r.Register(DataFormatAvro, SerializerCodec(NewAvroSerializer(opts...)))
+	r.Register(DataFormatJSON, SerializerCodec(NewJsonSerializer(opts...)))
+	r.Register(DataFormatProtobuf, SerializerCodec(NewProtobufSerializer(opts...)))
+	return r
+}
+
+// Register makes factory create the codecs of schemas with the given data format,
+// replacing any factory registered for it before
+func (r *Codecs) Register(dataFormat client.DataFormat, factory CodecFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.factories == nil {
+		r.factories = make(map[client.DataFormat]CodecFactory)
+	}
+	r.factories[dataFormat] = factory
+}
+
+// ForSchema returns the Codec for schemaName, created by the factory registered for the
+// schema's data format as reported by c.GetDataFormat
+func (r *Codecs) ForSchema(c client.Registry, schemaName string) (Codec, error) {
+	dataFormat, err := c.GetDataFormat(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data format: %w", err)
+	}
+
+	r.mu.RLock()
+	factory, ok := r.factories[client.DataFormat(dataFormat)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported data format %q for schema %s", dataFormat, schemaName)
+	}
+	return factory(c, schemaName), nil
+}
@@ -0,0 +1,222 @@
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/linkedin/goavro/v2"
+)
+
+// codecKey identifies one version of a schema. The registry name is included so that a
+// serializer shared between clients for different registries does not mix their schemas.
+type codecKey struct {
+	registryName string
+	schemaName   string
+	version      int64
+}
+
+// compiledSchema is a fetched schema version together with its compiled codec. Schema
+// versions are immutable in Glue, so an entry never goes stale.
+type compiledSchema struct {
+	version *glue.GetSchemaVersionOutput
+	codec   *goavro.Codec
+	schema  map[string]interface{}
+	names   avroNames
+	// sorted encodes for WithSortedMapKeys, caching the codecs of leaf types across calls
+	sorted *sortedMapEncoder
+}
+
+// definition returns the Avro definition the entry was compiled from
+func (cs *compiledSchema) definition() string {
+	return aws.StringValue(cs.version.SchemaDefinition)
+}
+
+// codecCache holds compiled schemas keyed by schema name and version number, and by
+// schema version ID. The zero value is an empty cache ready to use.
+type codecCache struct {
+	mu      sync.RWMutex
+	schemas map[codecKey]*compiledSchema
+	byID    map[string]*compiledSchema
+}
+
+func (cc *codecCache) get(key codecKey) (*compiledSchema, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	cs, ok := cc.schemas[key]
+	return cs, ok
+}
+
+func (cc *codecCache) put(key codecKey, cs *compiledSchema) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.schemas == nil {
+		cc.schemas = make(map[codecKey]*compiledSchema)
+	}
+	cc.schemas[key] = cs
+	cc.putIDLocked(cs)
+}
+
+// getID returns the compiled schema version with the given schema version ID
+func (cc *codecCache) getID(schemaVersionID string) (*compiledSchema, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	cs, ok := cc.byID[schemaVersionID]
+	return cs, ok
+}
+
+// putID caches cs by its schema version ID only
+func (cc *codecCache) putID(cs *compiledSchema) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.putIDLocked(cs)
+}
+
+func (cc *codecCache) putIDLocked(cs *compiledSchema) {
+	if cc.byID == nil {
+		cc.byID = make(map[string]*compiledSchema)
+	}
+	cc.byID[aws.StringValue(cs.version.SchemaVersionId)] = cs
+}
+
+func (cc *codecCache) clear() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.schemas = nil
+	cc.byID = nil
+}
+
+// ClearCodecCache drops every compiled schema, so that the next call fetches and compiles
+// schema definitions again
+func (s *AvroSerializer) ClearCodecCache() {
+	s.codecs.clear()
+}
+
+// writeCompiled returns the compiled latest version of a schema to serialize with, first
+// registering the definition configured with WithAutoRegistration, if any
+func (s *AvroSerializer) writeCompiled(c client.Registry, schemaName string) (*compiledSchema, error) {
+	if err := s.ensureRegistered(c, schemaName, DataFormatAvro); err != nil {
+		return nil, err
+	}
+	return s.latestCompiled(c, schemaName)
+}
+
+// latestCompiled returns the compiled latest version of a schema. The registry is still
+// asked for the latest version number on every call, so newly registered versions are
+// picked up immediately; only the version fetch and codec compilation are cached.
+func (s *AvroSerializer) latestCompiled(c client.Registry, schemaName string) (*compiledSchema, error) {
+	ctx, cancel := s.fetchContext()
+	defer cancel()
+
+	schemaResponse, err := s.getSchema(ctx, c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := latestVersionNumber(schemaName, schemaResponse)
+	if err != nil {
+		return nil, err
+	}
+	key := codecKey{
+		registryName: aws.StringValue(schemaResponse.RegistryName),
+		schemaName:   schemaName,
+		version:      latest,
+	}
+	cs, ok := s.codecs.get(key)
+	s.recordCacheHit(schemaName, ok)
+	if ok {
+		return cs, nil
+	}
+
+	version, err := s.fetchVersion(ctx, c, schemaName, key.version)
+	if err != nil {
+		return nil, err
+	}
+	cs, err = compileSchema(version)
+	if err != nil {
+		return nil, err
+	}
+	s.codecs.put(key, cs)
+	return cs, nil
+}
+
+// versionCompiled returns the compiled version versionNumber of a schema. The version is
+// fetched on every call, which the client can answer from its WithSchemaVersionCache
+// cache; compilation is cached by schema version ID.
+func (s *AvroSerializer) versionCompiled(c client.Registry, schemaName string, versionNumber int64) (*compiledSchema, error) {
+	version, err := s.version(c, schemaName, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	cs, ok := s.codecs.getID(aws.StringValue(version.SchemaVersionId))
+	s.recordCacheHit(schemaName, ok)
+	if ok {
+		return cs, nil
+	}
+
+	cs, err = compileSchema(version)
+	if err != nil {
+		return nil, err
+	}
+	s.codecs.putID(cs)
+	return cs, nil
+}
+
+func compileSchema(version *glue.GetSchemaVersionOutput) (*compiledSchema, error) {
+	definition := aws.StringValue(version.SchemaDefinition)
+	var schemaJSON map[string]interface{}
+	if err := json.Unmarshal([]byte(definition), &schemaJSON); err != nil {
+		return nil, fmt.Errorf("failed to parse schema definition: %w", err)
+	}
+	codec, err := goavro.NewCodec(definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Avro codec: %w", err)
+	}
+	return &compiledSchema{
+		version: version,
+		codec:   codec,
+		schema:  schemaJSON,
+		names:   newAvroNames(schemaJSON),
+		sorted:  newSortedMapEncoderForSchema(schemaJSON),
+	}, nil
+}
+
+// toNative converts v to the goavro native form of the schema
+func (cs *compiledSchema) toNative(v interface{}) (interface{}, error) {
+	return avroConverter{names: cs.names}.toNative(cs.schema, "", reflect.ValueOf(v))
+}
+
+// toRecord converts v to a goavro native record. Maps are taken to be in native form
+// already and are returned unchanged.
+func (cs *compiledSchema) toRecord(v interface{}) (map[string]interface{}, error) {
+	if record, ok := v.(map[string]interface{}); ok {
+		return record, nil
+	}
+	native, err := cs.toNative(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T to an Avro record: %w", v, err)
+	}
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to convert %T to an Avro record", v)
+	}
+	return record, nil
+}
+
+// fromNative stores a decoded native datum in out, which must be a non-nil pointer.
+// A *map[string]interface{} receives the datum in goavro's native form unchanged.
+func (cs *compiledSchema) fromNative(native interface{}, out interface{}) error {
+	if m, ok := out.(*map[string]interface{}); ok {
+		if record, ok := native.(map[string]interface{}); ok {
+			*m = record
+			return nil
+		}
+	}
+	if err := (avroConverter{names: cs.names}).fromNative(cs.schema, "", native, reflect.ValueOf(out).Elem()); err != nil {
+		return fmt.Errorf("failed to decode into %T: %w", out, err)
+	}
+	return nil
+}
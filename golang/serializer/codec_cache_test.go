@@ -0,0 +1,182 @@
+package serializer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func newCodecCacheClient(tb testing.TB) (*gluetest.Glue, *client.GlueSchemaRegistryClient) {
+	tb.Helper()
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		tb.Fatalf("Failed to create schema: %v", err)
+	}
+	return fake, c
+}
+
+func TestAvroCodecCache(t *testing.T) {
+	fake, c := newCodecCacheClient(t)
+	defer c.Close()
+
+	avroSerializer := serializer.NewAvroSerializer()
+	auditEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	for i := 0; i < 3; i++ {
+		data, err := avroSerializer.Serialize(c, "SalesforceAudit", auditEvent)
+		if err != nil {
+			t.Fatalf("Failed to serialize: %v", err)
+		}
+		if err := avroSerializer.Deserialize(c, "SalesforceAudit", data, &model.SalesforceAudit{}); err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 1 {
+		t.Errorf("GetSchemaVersion call count mismatch: expected 1, got %d", calls)
+	}
+
+	// A new latest version is picked up without clearing the cache
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"type": "string", "doc": "Detailed information about the audit event"}`,
+		`"type": "string", "doc": "Detailed information about the audit event"}, {"name": "source", "type": "string", "default": "salesforce"}`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withSource); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	record := auditEvent.ToMap()
+	record["source"] = "api"
+	if _, err := avroSerializer.SerializeMap(c, "SalesforceAudit", record); err != nil {
+		t.Fatalf("Failed to serialize against the new version: %v", err)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 2 {
+		t.Errorf("GetSchemaVersion call count mismatch: expected 2, got %d", calls)
+	}
+
+	avroSerializer.ClearCodecCache()
+	if _, err := avroSerializer.SerializeMap(c, "SalesforceAudit", record); err != nil {
+		t.Fatalf("Failed to serialize after clearing the cache: %v", err)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 3 {
+		t.Errorf("GetSchemaVersion call count mismatch after ClearCodecCache: expected 3, got %d", calls)
+	}
+}
+
+func BenchmarkAvroSerialize(b *testing.B) {
+	auditEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	b.Run("Cached", func(b *testing.B) {
+		_, c := newCodecCacheClient(b)
+		avroSerializer := serializer.NewAvroSerializer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := avroSerializer.Serialize(c, "SalesforceAudit", auditEvent); err != nil {
+				b.Fatalf("Failed to serialize: %v", err)
+			}
+		}
+	})
+
+	b.Run("Uncached", func(b *testing.B) {
+		_, c := newCodecCacheClient(b)
+		avroSerializer := serializer.NewAvroSerializer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			avroSerializer.ClearCodecCache()
+			if _, err := avroSerializer.Serialize(c, "SalesforceAudit", auditEvent); err != nil {
+				b.Fatalf("Failed to serialize: %v", err)
+			}
+		}
+	})
+}
+
+func TestNewAvroSerializerForSchema(t *testing.T) {
+	fake, c := newCodecCacheClient(t)
+	defer c.Close()
+
+	avroSerializer, err := serializer.NewAvroSerializerForSchema(c, "SalesforceAudit")
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 1 {
+		t.Errorf("GetSchemaVersion call count mismatch after construction: expected 1, got %d", calls)
+	}
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	data, err := avroSerializer.Serialize(c, "SalesforceAudit", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if err := avroSerializer.Deserialize(c, "SalesforceAudit", data, &model.SalesforceAudit{}); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 1 {
+		t.Errorf("Expected the prefetched codec to be used, got %d GetSchemaVersion calls", calls)
+	}
+
+	if _, err := c.CreateSchema("Broken", "AVRO", `{"type": "record", "name": "Broken"}`, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := serializer.NewAvroSerializerForSchema(c, "Broken"); err == nil || !strings.Contains(err.Error(), "Broken") {
+		t.Errorf("Expected an error naming the invalid schema, got %v", err)
+	}
+	if _, err := serializer.NewAvroSerializerForSchema(c, "Missing"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestLatestVersionCacheTTLServesProducers(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry",
+		client.WithSchemaVersionCache(true), client.WithLatestVersionCacheTTL(time.Hour))
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditEvent", "PROTOBUF", auditProtoSchema, client.CompatibilityNone); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	avroSerializer := serializer.NewAvroSerializer()
+	protobufSerializer := serializer.NewProtobufSerializer()
+	auditEvent := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	message := newProtoMessage(t, auditProtoSchema, "AuditEvent")
+	for i := 0; i < 5; i++ {
+		if _, err := avroSerializer.Serialize(c, "SalesforceAudit", auditEvent); err != nil {
+			t.Fatalf("Failed to serialize Avro: %v", err)
+		}
+		if _, err := protobufSerializer.Serialize(c, "AuditEvent", message); err != nil {
+			t.Fatalf("Failed to serialize Protobuf: %v", err)
+		}
+	}
+	// One lookup of the latest version per schema for the whole TTL
+	if calls := fake.Calls("GetSchema"); calls != 2 {
+		t.Errorf("GetSchema call count mismatch: expected 2, got %d", calls)
+	}
+
+	// A version registered through the client is picked up at once
+	withSource := strings.Replace(gluetest.SalesforceAuditAvroSchema, `"type": "string", "doc": "Detailed information about the audit event"}`,
+		`"type": "string", "doc": "Detailed information about the audit event"}, {"name": "source", "type": "string", "default": "salesforce"}`, 1)
+	if _, err := c.RegisterSchemaVersion("SalesforceAudit", withSource); err != nil {
+		t.Fatalf("Failed to register schema version: %v", err)
+	}
+	record := auditEvent.ToMap()
+	record["source"] = "api"
+	if _, err := avroSerializer.SerializeMap(c, "SalesforceAudit", record); err != nil {
+		t.Fatalf("Failed to serialize against the new version: %v", err)
+	}
+	if calls := fake.Calls("GetSchema"); calls != 3 {
+		t.Errorf("GetSchema call count mismatch after registering: expected 3, got %d", calls)
+	}
+}
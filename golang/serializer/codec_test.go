@@ -0,0 +1,97 @@
+package serializer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// upperCodec encodes records as their event ID in upper case
+type upperCodec struct{}
+
+func (upperCodec) Encode(v interface{}) ([]byte, error) {
+	return bytes.ToUpper([]byte(v.(*model.SalesforceAudit).EventID)), nil
+}
+
+func (upperCodec) Decode(data []byte, out interface{}) error {
+	out.(*model.SalesforceAudit).EventID = string(bytes.ToLower(data))
+	return nil
+}
+
+func TestCodecsByDataFormat(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("AuditAvro", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditJSON", client.DataFormatJSON, gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create JSON schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditEvent", client.DataFormatProtobuf, auditProtoSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Protobuf schema: %v", err)
+	}
+
+	codecs := serializer.NewCodecs()
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "details"}
+	for _, schemaName := range []string{"AuditAvro", "AuditJSON"} {
+		codec, err := codecs.ForSchema(c, schemaName)
+		if err != nil {
+			t.Fatalf("Failed to get codec for %s: %v", schemaName, err)
+		}
+		data, err := codec.Encode(event)
+		if err != nil {
+			t.Fatalf("Failed to encode %s: %v", schemaName, err)
+		}
+		if isJSON := bytes.HasPrefix(data, []byte("{")); isJSON != (schemaName == "AuditJSON") {
+			t.Errorf("Unexpected encoding for %s: %q", schemaName, data)
+		}
+		var decoded model.SalesforceAudit
+		if err := codec.Decode(data, &decoded); err != nil {
+			t.Fatalf("Failed to decode %s: %v", schemaName, err)
+		}
+		if decoded != *event {
+			t.Errorf("Event mismatch for %s: expected %+v, got %+v", schemaName, *event, decoded)
+		}
+	}
+
+	message := newProtoMessage(t, auditProtoSchema, "AuditEvent")
+	message.Set(message.Descriptor().Fields().ByName("event_id"), protoreflect.ValueOfString("event-12345"))
+	codec, err := codecs.ForSchema(c, "AuditEvent")
+	if err != nil {
+		t.Fatalf("Failed to get Protobuf codec: %v", err)
+	}
+	data, err := codec.Encode(message)
+	if err != nil {
+		t.Fatalf("Failed to encode Protobuf message: %v", err)
+	}
+	decoded := dynamicpb.NewMessage(message.Descriptor())
+	if err := codec.Decode(data, decoded); err != nil {
+		t.Fatalf("Failed to decode Protobuf message: %v", err)
+	}
+	if !proto.Equal(message, decoded) {
+		t.Errorf("Message mismatch: expected %v, got %v", message, decoded)
+	}
+
+	// A registered factory replaces the built-in codec of its data format
+	codecs.Register(serializer.DataFormatJSON, func(c client.Registry, schemaName string) serializer.Codec { return upperCodec{} })
+	if codec, err = codecs.ForSchema(c, "AuditJSON"); err != nil {
+		t.Fatalf("Failed to get codec: %v", err)
+	}
+	if data, err := codec.Encode(event); err != nil || string(data) != "EVENT-12345" {
+		t.Errorf("Expected the registered codec to encode EVENT-12345, got %q (error %v)", data, err)
+	}
+
+	if _, err := codecs.ForSchema(c, "Missing"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, err := (&serializer.Codecs{}).ForSchema(c, "AuditAvro"); err == nil {
+		t.Error("Expected an error for a data format without a codec")
+	}
+}
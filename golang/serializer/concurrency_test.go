@@ -0,0 +1,104 @@
+package serializer_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// TestConcurrentSerde shares one client and one set of serializers between many
+// goroutines while new schema versions are registered. Run it with -race.
+func TestConcurrentSerde(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry",
+		client.WithSchemaVersionCache(true), client.WithMetrics(client.NopMetricsRecorder{}))
+	defer c.Close()
+	if _, err := c.CreateSchema("AuditAvro", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditJSON", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create JSON schema: %v", err)
+	}
+
+	smart := serializer.NewSmartSerializer(serializer.WithStrictJSONValidation())
+	const goroutines = 16
+	const iterations = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines+1)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				event := &model.SalesforceAudit{
+					EventID:      fmt.Sprintf("event-%d-%d", g, i),
+					EventName:    "UserLogin",
+					Timestamp:    1704067200000 + int64(i),
+					EventDetails: "User logged in successfully",
+				}
+				for _, schemaName := range []string{"AuditAvro", "AuditJSON"} {
+					data, err := smart.Serialize(c, schemaName, event)
+					if err != nil {
+						errs <- fmt.Errorf("serialize %s: %w", schemaName, err)
+						return
+					}
+					var decoded model.SalesforceAudit
+					if err := smart.Deserialize(c, schemaName, data, &decoded); err != nil {
+						errs <- fmt.Errorf("deserialize %s: %w", schemaName, err)
+						return
+					}
+					if decoded != *event {
+						errs <- fmt.Errorf("%s: expected %+v, got %+v", schemaName, *event, decoded)
+						return
+					}
+				}
+				framed, err := smart.Avro.SerializeWithHeader(c, "AuditAvro", event)
+				if err != nil {
+					errs <- fmt.Errorf("serialize with header: %w", err)
+					return
+				}
+				var decoded model.SalesforceAudit
+				if err := smart.Avro.DeserializeWithHeader(c, "AuditAvro", framed, &decoded); err != nil {
+					errs <- fmt.Errorf("deserialize with header: %w", err)
+					return
+				}
+			}
+		}(g)
+	}
+
+	// Registering versions and invalidating caches while the serializers run
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 5; i++ {
+			// A distinct doc string per iteration makes each registration a new latest version
+			definition := strings.Replace(gluetest.SalesforceAuditAvroSchema, "Schema for Salesforce audit events",
+				fmt.Sprintf("Schema for Salesforce audit events, revision %d", i+2), 1)
+			if _, err := c.RegisterSchemaVersion("AuditAvro", definition); err != nil {
+				errs <- fmt.Errorf("register version: %w", err)
+				return
+			}
+			c.InvalidateSchemaCache("AuditJSON")
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	schema, err := c.GetSchema("AuditAvro")
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if latest := aws.Int64Value(schema.LatestSchemaVersion); latest != 6 {
+		t.Errorf("LatestSchemaVersion mismatch: expected 6, got %d", latest)
+	}
+}
@@ -0,0 +1,19 @@
+package serializer
+
+import "github.com/linkedin/goavro/v2"
+
+// SetBinaryFromNative replaces the Avro encoder used by AvroSerializer and returns a function restoring it
+func SetBinaryFromNative(encode func(*goavro.Codec, []byte, interface{}) ([]byte, error)) (restore func()) {
+	previous := binaryFromNative
+	binaryFromNative = encode
+	return func() { binaryFromNative = previous }
+}
+
+// EncodeWithSortedMapKeys encodes datum the way WithSortedMapKeys does
+func EncodeWithSortedMapKeys(definition string, datum interface{}) ([]byte, error) {
+	encoder, err := newSortedMapEncoder(definition)
+	if err != nil {
+		return nil, err
+	}
+	return encoder.BinaryFromNative(nil, datum)
+}
@@ -0,0 +1,186 @@
+package serializer_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+const orderAvroSchema = `{
+  "type": "record",
+  "name": "Order",
+  "namespace": "com.example",
+  "fields": [
+    {"name": "orderId", "type": "string"},
+    {"name": "quantity", "type": "int"},
+    {"name": "note", "type": ["null", "string"], "default": null},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "attributes", "type": {"type": "map", "values": "long"}},
+    {"name": "shipping", "type": ["null", {"type": "record", "name": "Address", "fields": [
+      {"name": "city", "type": "string"},
+      {"name": "zip", "type": ["null", "string"], "default": null}
+    ]}], "default": null},
+    {"name": "source", "type": "string"}
+  ]
+}`
+
+const orderJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "orderId": {"type": "string"},
+    "quantity": {"type": "integer"}
+  },
+  "required": ["orderId"]
+}`
+
+type Address struct {
+	City string  `avro:"city"`
+	Zip  *string `avro:"zip"`
+}
+
+type Origin struct {
+	Source string `avro:"source" json:"source"`
+}
+
+type Order struct {
+	Origin
+	OrderID    string           `avro:"orderId" json:"orderId"`
+	Quantity   int              `avro:"quantity" json:"quantity"`
+	Note       *string          `avro:"note" json:"note,omitempty"`
+	Tags       []string         `avro:"tags" json:"tags"`
+	Attributes map[string]int64 `avro:"attributes" json:"attributes"`
+	Shipping   *Address         `avro:"shipping" json:"-"`
+}
+
+func TestAvroSerializeStruct(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("Order", "AVRO", orderAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	note, zip := "leave at door", "98101"
+	tests := []struct {
+		name  string
+		order Order
+	}{
+		{
+			name: "AllFields",
+			order: Order{
+				Origin:     Origin{Source: "web"},
+				OrderID:    "order-1",
+				Quantity:   3,
+				Note:       &note,
+				Tags:       []string{"priority", "gift"},
+				Attributes: map[string]int64{"weight": 12},
+				Shipping:   &Address{City: "Seattle", Zip: &zip},
+			},
+		},
+		{
+			name: "NullUnions",
+			order: Order{
+				Origin:     Origin{Source: "api"},
+				OrderID:    "order-2",
+				Tags:       []string{},
+				Attributes: map[string]int64{},
+			},
+		},
+	}
+
+	avroSerializer := serializer.NewAvroSerializer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := avroSerializer.Serialize(c, "Order", &tt.order)
+			if err != nil {
+				t.Fatalf("Failed to serialize: %v", err)
+			}
+
+			var decoded Order
+			if err := avroSerializer.Deserialize(c, "Order", data, &decoded); err != nil {
+				t.Fatalf("Failed to deserialize: %v", err)
+			}
+			if !reflect.DeepEqual(tt.order, decoded) {
+				t.Errorf("Order mismatch: expected %+v, got %+v", tt.order, decoded)
+			}
+
+			// A map receives the record in goavro's native form, with unions still wrapped
+			var native map[string]interface{}
+			if err := avroSerializer.Deserialize(c, "Order", data, &native); err != nil {
+				t.Fatalf("Failed to deserialize into map: %v", err)
+			}
+			if tt.order.Note != nil && !reflect.DeepEqual(native["note"], map[string]interface{}{"string": note}) {
+				t.Errorf("Native note mismatch: got %v", native["note"])
+			}
+		})
+	}
+}
+
+func TestAvroSerializeStructErrors(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("Order", "AVRO", orderAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	avroSerializer := serializer.NewAvroSerializer()
+
+	// Struct fields the schema does not define are reported like unexpected map keys
+	type withExtra struct {
+		Order
+		Discount float64 `avro:"discount"`
+	}
+	_, err := avroSerializer.Serialize(c, "Order", withExtra{Order: Order{OrderID: "order-1"}})
+	if err == nil || !strings.Contains(err.Error(), "discount") {
+		t.Errorf("Expected error naming the unexpected field, got %v", err)
+	}
+
+	// Values that do not fit the schema type are rejected rather than truncated
+	type badQuantity struct {
+		Order
+		Quantity int64 `avro:"quantity"`
+	}
+	if _, err := avroSerializer.Serialize(c, "Order", badQuantity{Quantity: 1 << 40}); err == nil {
+		t.Error("Expected error for a quantity that overflows an Avro int")
+	}
+
+	data, err := avroSerializer.Serialize(c, "Order", &Order{OrderID: "order-1"})
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	var order Order
+	if err := avroSerializer.Deserialize(c, "Order", data, order); err == nil {
+		t.Error("Expected error deserializing into a non-pointer")
+	}
+}
+
+func TestJsonSerializeStruct(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("OrderJSON", "JSON", orderJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	original := Order{
+		Origin:     Origin{Source: "web"},
+		OrderID:    "order-1",
+		Quantity:   3,
+		Tags:       []string{"priority"},
+		Attributes: map[string]int64{"weight": 12},
+	}
+
+	var s serializer.Serializer = serializer.NewJsonSerializer(serializer.WithVerifyRoundTrip())
+	data, err := s.Serialize(c, "OrderJSON", original)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	var decoded Order
+	if err := s.Deserialize(c, "OrderJSON", data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if !reflect.DeepEqual(original, decoded) {
+		t.Errorf("Order mismatch: expected %+v, got %+v", original, decoded)
+	}
+}
@@ -0,0 +1,112 @@
+package serializer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// The Glue wire-format header precedes the encoded record: a version byte, a compression
+// byte and the 16-byte UUID of the schema version the record was written with. The
+// compression byte values are those of the AWS Glue Schema Registry serde for Java.
+const (
+	headerVersion   byte = 3
+	compressionNone byte = 0
+	compressionZlib byte = 5
+
+	// HeaderSize is the length in bytes of the Glue wire-format header
+	HeaderSize = 18
+)
+
+// ErrInvalidHeader is returned when a payload does not start with a valid Glue wire-format header
+var ErrInvalidHeader = errors.New("invalid Glue wire-format header")
+
+// encodeHeader builds the header for a record written with the given schema version ID
+// and compressed as the compression byte says
+func encodeHeader(schemaVersionID string, compression byte) ([]byte, error) {
+	id, err := hex.DecodeString(strings.ReplaceAll(schemaVersionID, "-", ""))
+	if err != nil || len(id) != 16 {
+		return nil, fmt.Errorf("schema version ID is not a UUID: %s", schemaVersionID)
+	}
+
+	header := make([]byte, 0, HeaderSize)
+	header = append(header, headerVersion, compression)
+	return append(header, id...), nil
+}
+
+// decodeHeader returns the schema version ID and compression byte recorded in a header
+func decodeHeader(header []byte) (string, byte, error) {
+	if len(header) != HeaderSize {
+		return "", 0, fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidHeader, len(header), HeaderSize)
+	}
+	if header[0] != headerVersion {
+		return "", 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, header[0])
+	}
+	if header[1] != compressionNone && header[1] != compressionZlib {
+		return "", 0, fmt.Errorf("%w: unsupported compression %d", ErrInvalidHeader, header[1])
+	}
+
+	id := hex.EncodeToString(header[2:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", id[0:8], id[8:12], id[12:16], id[16:20], id[20:32]), header[1], nil
+}
+
+// compressor is a zlib writer together with the buffer it compresses into, pooled in
+// compressors so that compressing a record does not allocate a new writer each time
+type compressor struct {
+	buf bytes.Buffer
+	w   *zlib.Writer
+}
+
+var compressors = sync.Pool{New: func() interface{} {
+	z := &compressor{}
+	z.w = zlib.NewWriter(&z.buf)
+	return z
+}}
+
+// appendCompressed appends an encoded record, compressed with zlib, to dst. body may
+// share its backing array with dst.
+func appendCompressed(dst, body []byte) ([]byte, error) {
+	z := compressors.Get().(*compressor)
+	defer func() {
+		if z.buf.Cap() <= maxPooledBufferSize {
+			compressors.Put(z)
+		}
+	}()
+	z.buf.Reset()
+	z.w.Reset(&z.buf)
+	if _, err := z.w.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress record: %w", err)
+	}
+	if err := z.w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress record: %w", err)
+	}
+	return append(dst, z.buf.Bytes()...), nil
+}
+
+// decompressBody decompresses a zlib-compressed record. With a maximum payload size,
+// decompression stops with ErrPayloadTooLarge once the record exceeds it.
+func (o *options) decompressBody(body []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress record: %w", err)
+	}
+	defer r.Close()
+
+	var src io.Reader = r
+	if o.maxPayloadSize > 0 {
+		src = io.LimitReader(r, int64(o.maxPayloadSize)+1)
+	}
+	decompressed, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress record: %w", err)
+	}
+	if err := o.checkPayloadSize(decompressed); err != nil {
+		return nil, err
+	}
+	return decompressed, nil
+}
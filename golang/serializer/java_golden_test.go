@@ -0,0 +1,88 @@
+package serializer_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/linkedin/goavro/v2"
+)
+
+// javaCorpus holds payloads written by the AWS Glue Schema Registry serializer for Java.
+// Each case is a triple: <case>.bin is the payload with its wire-format header, <case>.avsc
+// the Avro schema it was written with and <case>.json the expected record in Avro's JSON
+// encoding. The case name is used as the schema name.
+const javaCorpus = "testdata/java"
+
+func TestDeserializeJavaPayloads(t *testing.T) {
+	payloads, err := filepath.Glob(filepath.Join(javaCorpus, "*.bin"))
+	if err != nil {
+		t.Fatalf("Failed to list payloads: %v", err)
+	}
+	if len(payloads) == 0 {
+		t.Skipf("No Java payloads in %s", javaCorpus)
+	}
+
+	for _, payload := range payloads {
+		name := strings.TrimSuffix(filepath.Base(payload), ".bin")
+		t.Run(name, func(t *testing.T) {
+			data := readGolden(t, payload)
+			definition := string(readGolden(t, filepath.Join(javaCorpus, name+".avsc")))
+			expectedJSON := readGolden(t, filepath.Join(javaCorpus, name+".json"))
+
+			versionID, err := headerVersionID(data)
+			if err != nil {
+				t.Fatalf("Failed to read header of %s: %v", payload, err)
+			}
+			fake := gluetest.New()
+			fake.SetNextVersionID(versionID)
+			c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+			defer c.Close()
+			if _, err := c.CreateSchema(name, client.DataFormatAvro, definition, client.CompatibilityNone); err != nil {
+				t.Fatalf("Failed to create schema: %v", err)
+			}
+
+			var record map[string]interface{}
+			if err := serializer.NewAvroSerializer().DeserializeWithHeader(c, name, data, &record); err != nil {
+				t.Fatalf("Failed to deserialize: %v", err)
+			}
+
+			codec, err := goavro.NewCodec(definition)
+			if err != nil {
+				t.Fatalf("Failed to compile schema: %v", err)
+			}
+			expected, _, err := codec.NativeFromTextual(expectedJSON)
+			if err != nil {
+				t.Fatalf("Failed to parse expected record: %v", err)
+			}
+			if !reflect.DeepEqual(record, expected) {
+				t.Errorf("Record mismatch: expected %v, got %v", expected, record)
+			}
+		})
+	}
+}
+
+func readGolden(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return data
+}
+
+// headerVersionID returns the schema version ID named by the wire-format header of data
+func headerVersionID(data []byte) (string, error) {
+	if len(data) < serializer.HeaderSize {
+		return "", fmt.Errorf("payload is %d bytes, shorter than the header", len(data))
+	}
+	id := hex.EncodeToString(data[2:serializer.HeaderSize])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", id[0:8], id[8:12], id[12:16], id[16:20], id[20:32]), nil
+}
@@ -1,65 +1,191 @@
 package serializer
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/aws-glue-schema-registry/golang/client"
-	"github.com/aws-glue-schema-registry/golang/model"
 )
 
-// JsonSerializer provides JSON serialization/deserialization
-type JsonSerializer struct{}
+// JsonSerializer provides JSON serialization/deserialization.
+// The zero value is ready to use; NewJsonSerializer applies options.
+//
+// A JsonSerializer caches the compiled JSON Schemas it validates against, so it should be
+// reused rather than created per message. It is safe for concurrent use and must not be
+// copied after first use.
+type JsonSerializer struct {
+	options
+	schemas jsonSchemaCache
+}
 
-// Serialize serializes a SalesforceAudit object to JSON format
-func (s *JsonSerializer) Serialize(c *client.GlueSchemaRegistryClient, schemaName string, auditEvent *model.SalesforceAudit) ([]byte, error) {
-	// Get schema definition from Glue Schema Registry
-	schemaResponse, err := c.GetSchema(schemaName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema: %w", err)
-	}
+// NewJsonSerializer creates a JsonSerializer configured with the given options
+func NewJsonSerializer(opts ...Option) *JsonSerializer {
+	return &JsonSerializer{options: newOptions(opts)}
+}
+
+// Serialize serializes v to JSON format with encoding/json, so struct fields are named by
+// their json tags. With WithStrictJSONValidation the encoded payload is validated against
+// the registered JSON Schema, and a *JSONValidationError is returned if it does not conform.
+// Without it the definition is not needed, and the registry is only asked whether the
+// schema exists, which *client.GlueSchemaRegistryClient answers from its cache after the
+// first call.
+func (s *JsonSerializer) Serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	start := time.Now()
+	jsonBytes, err := s.serialize(c, schemaName, v)
+	s.recordSerde(opSerialize, schemaName, DataFormatJSON, start, err)
+	return jsonBytes, err
+}
 
-	latestVersion := *schemaResponse.LatestSchemaVersion
-	_, err = c.GetSchemaVersion(schemaName, latestVersion)
+func (s *JsonSerializer) serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	if err := s.ensureRegistered(c, schemaName, DataFormatJSON); err != nil {
+		return nil, err
+	}
+	schemaDefinition, err := s.definitionIfValidating(c, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema version: %w", err)
+		return nil, err
 	}
 
-	// Note: In production, you might want to validate the JSON
-	// against the schema definition before serialization using a JSON Schema validator
-
 	// Serialize to JSON bytes
-	jsonBytes, err := json.Marshal(auditEvent)
+	jsonBytes, err := json.Marshal(v)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
 	}
+	if s.strictJSONValidation {
+		if err := s.schemas.validate(schemaDefinition, jsonBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.verifyRoundTrip {
+		if err := verifyJSONRoundTrip(jsonBytes, v); err != nil {
+			return nil, err
+		}
+	}
 
 	return jsonBytes, nil
 }
 
-// Deserialize deserializes JSON data to a SalesforceAudit object
-func (s *JsonSerializer) Deserialize(c *client.GlueSchemaRegistryClient, schemaName string, data []byte) (*model.SalesforceAudit, error) {
-	// Get schema definition from Glue Schema Registry
-	schemaResponse, err := c.GetSchema(schemaName)
+// verifyJSONRoundTrip decodes jsonBytes into a new value of v's type and checks that it
+// encodes back to jsonBytes. Comparing encodings rather than values ignores what JSON does
+// not carry, such as fields tagged json:"-" and the location of a time.Time.
+func verifyJSONRoundTrip(jsonBytes []byte, v interface{}) error {
+	if record, ok := v.(map[string]interface{}); ok {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+			return fmt.Errorf("%w: %v", ErrRoundTripMismatch, err)
+		}
+		if !nativeEqual(record, decoded) {
+			return fmt.Errorf("%w: decoded %v, expected %v", ErrRoundTripMismatch, decoded, record)
+		}
+		return nil
+	}
+
+	expected := reflect.ValueOf(v)
+	for expected.Kind() == reflect.Pointer && !expected.IsNil() {
+		expected = expected.Elem()
+	}
+	if !expected.IsValid() || expected.Kind() == reflect.Pointer {
+		return nil
+	}
+	decoded := reflect.New(expected.Type())
+	if err := json.Unmarshal(jsonBytes, decoded.Interface()); err != nil {
+		return fmt.Errorf("%w: %v", ErrRoundTripMismatch, err)
+	}
+	reencoded, err := json.Marshal(decoded.Interface())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRoundTripMismatch, err)
+	}
+	if !bytes.Equal(reencoded, jsonBytes) {
+		return fmt.Errorf("%w: decoded %s, expected %s", ErrRoundTripMismatch, reencoded, jsonBytes)
+	}
+	return nil
+}
+
+// SerializeMap serializes a record to JSON without going through a model type. The
+// encoded record is validated against the registered JSON Schema, and a
+// *JSONValidationError is returned if it does not conform.
+func (s *JsonSerializer) SerializeMap(c client.Registry, schemaName string, record map[string]interface{}) ([]byte, error) {
+	start := time.Now()
+	jsonBytes, err := s.serializeMap(c, schemaName, record)
+	s.recordSerde(opSerialize, schemaName, DataFormatJSON, start, err)
+	return jsonBytes, err
+}
+
+func (s *JsonSerializer) serializeMap(c client.Registry, schemaName string, record map[string]interface{}) ([]byte, error) {
+	if err := s.ensureRegistered(c, schemaName, DataFormatJSON); err != nil {
+		return nil, err
+	}
+	schemaDefinition, err := s.latestDefinition(c, schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema: %w", err)
+		return nil, err
 	}
 
-	latestVersion := *schemaResponse.LatestSchemaVersion
-	_, err = c.GetSchemaVersion(schemaName, latestVersion)
+	jsonBytes, err := json.Marshal(record)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema version: %w", err)
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := s.schemas.validate(schemaDefinition, jsonBytes); err != nil {
+		return nil, err
 	}
 
-	// Note: In production, you might want to validate the JSON
-	// against the schema definition after deserialization using a JSON Schema validator
+	if s.verifyRoundTrip {
+		if err := verifyJSONRoundTrip(jsonBytes, record); err != nil {
+			return nil, err
+		}
+	}
+
+	return jsonBytes, nil
+}
+
+// Deserialize deserializes JSON data into out, which must be a non-nil pointer, with
+// encoding/json. With WithStrictJSONValidation the payload is first validated against the
+// registered JSON Schema, and a *JSONValidationError is returned if it does not conform.
+// Registry lookups are as for Serialize.
+func (s *JsonSerializer) Deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	start := time.Now()
+	err := s.deserialize(c, schemaName, data, out)
+	s.recordSerde(opDeserialize, schemaName, DataFormatJSON, start, err)
+	return err
+}
+
+func (s *JsonSerializer) deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	if err := checkOut(out); err != nil {
+		return err
+	}
+	if err := s.checkPayloadSize(data); err != nil {
+		return err
+	}
+
+	schemaDefinition, err := s.definitionIfValidating(c, schemaName)
+	if err != nil {
+		return err
+	}
+	if s.strictJSONValidation {
+		if err := s.schemas.validate(schemaDefinition, data); err != nil {
+			return err
+		}
+	}
 
 	// Deserialize from JSON bytes
-	var auditEvent model.SalesforceAudit
-	if err := json.Unmarshal(data, &auditEvent); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
 
-	return &auditEvent, nil
+	return nil
 }
 
+// definitionIfValidating returns the latest definition of the schema with
+// WithStrictJSONValidation. Otherwise it only checks that the schema exists and returns
+// an empty definition, since encoding/json does not need one.
+func (s *JsonSerializer) definitionIfValidating(c client.Registry, schemaName string) (string, error) {
+	if s.strictJSONValidation {
+		return s.latestDefinition(c, schemaName)
+	}
+	if _, err := s.dataFormat(c, schemaName); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -1,15 +1,20 @@
 package serializer_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
 	"github.com/aws-glue-schema-registry/golang/model"
 	"github.com/aws-glue-schema-registry/golang/serializer"
 	"github.com/aws-glue-schema-registry/golang/testconfig"
 )
 
 func TestJsonSerialization(t *testing.T) {
+	testconfig.RequireAWSCredentials(t)
 	cfg := testconfig.LoadConfig()
 	c, err := client.NewGlueSchemaRegistryClient(cfg.AWSRegion, cfg.RegistryName)
 	if err != nil {
@@ -39,7 +44,8 @@ func TestJsonSerialization(t *testing.T) {
 	}
 
 	// Deserialize
-	deserializedEvent, err := jsonSerializer.Deserialize(c, schemaName, serializedData)
+	deserializedEvent := &model.SalesforceAudit{}
+	err = jsonSerializer.Deserialize(c, schemaName, serializedData, deserializedEvent)
 	if err != nil {
 		t.Fatalf("Failed to deserialize: %v", err)
 	}
@@ -58,3 +64,148 @@ func TestJsonSerialization(t *testing.T) {
 		t.Errorf("EventDetails mismatch: expected %s, got %s", originalEvent.EventDetails, deserializedEvent.EventDetails)
 	}
 }
+
+func TestJsonDeserializeRejectsOversizedPayload(t *testing.T) {
+	c, err := client.NewGlueSchemaRegistryClient("us-east-1", "test-registry")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	jsonSerializer := serializer.NewJsonSerializer(serializer.WithMaxPayloadSize(16))
+
+	err = jsonSerializer.Deserialize(c, "SalesAuditJSON", []byte(`{"eventId": "event-12345", "eventName": "UserLogin"}`), &model.SalesforceAudit{})
+	if !errors.Is(err, serializer.ErrPayloadTooLarge) {
+		t.Fatalf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestJsonStrictValidation(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAuditJson", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	type auditWithSource struct {
+		model.SalesforceAudit
+		Source string `json:"source"`
+	}
+	withSource := auditWithSource{
+		SalesforceAudit: model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in successfully"},
+		Source:          "api",
+	}
+	invalid := []byte(`{"eventId": "event-12345", "eventName": "UserLogin", "timestamp": "yesterday"}`)
+
+	lenient := serializer.NewJsonSerializer()
+	if _, err := lenient.Serialize(c, "SalesforceAuditJson", withSource); err != nil {
+		t.Errorf("Expected lenient serialize to succeed, got %v", err)
+	}
+	if err := lenient.Deserialize(c, "SalesforceAuditJson", invalid, &map[string]interface{}{}); err != nil {
+		t.Errorf("Expected lenient deserialize to succeed, got %v", err)
+	}
+
+	strict := serializer.NewJsonSerializer(serializer.WithStrictJSONValidation())
+	var violations *serializer.JSONValidationError
+	if _, err := strict.Serialize(c, "SalesforceAuditJson", withSource); !errors.As(err, &violations) {
+		t.Fatalf("Expected JSONValidationError, got %v", err)
+	}
+	if _, err := strict.Serialize(c, "SalesforceAuditJson", withSource.SalesforceAudit); err != nil {
+		t.Errorf("Failed to serialize a conforming record: %v", err)
+	}
+
+	err := strict.Deserialize(c, "SalesforceAuditJson", invalid, &model.SalesforceAudit{})
+	if !errors.As(err, &violations) {
+		t.Fatalf("Expected JSONValidationError, got %v", err)
+	}
+	paths := make([]string, 0, len(violations.Violations))
+	for _, v := range violations.Violations {
+		paths = append(paths, v.Path)
+	}
+	// The missing eventDetails is reported at the root, the mistyped timestamp at its own path
+	if got := strings.Join(paths, ","); got != ",/timestamp" {
+		t.Errorf("Violation paths mismatch: expected [(root) /timestamp], got %v", violations.Violations)
+	}
+}
+
+func TestJsonSerializerFetchesLatestDefinitionInOneCall(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "details"}
+	if _, err := serializer.NewJsonSerializer(serializer.WithStrictJSONValidation()).Serialize(c, "SalesforceAudit", event); err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if calls := fake.Calls("GetSchema"); calls != 0 {
+		t.Errorf("Expected no GetSchema calls, got %d", calls)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 1 {
+		t.Errorf("GetSchemaVersion call count mismatch: expected 1, got %d", calls)
+	}
+}
+
+func TestJsonSerializerSkipsVersionWithoutValidation(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	s := serializer.NewJsonSerializer()
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "details"}
+	for i := 0; i < 3; i++ {
+		data, err := s.Serialize(c, "SalesforceAudit", event)
+		if err != nil {
+			t.Fatalf("Failed to serialize: %v", err)
+		}
+		if err := s.Deserialize(c, "SalesforceAudit", data, &model.SalesforceAudit{}); err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+	}
+	if calls := fake.Calls("GetSchema"); calls != 1 {
+		t.Errorf("GetSchema call count mismatch: expected 1, got %d", calls)
+	}
+	if calls := fake.Calls("GetSchemaVersion"); calls != 0 {
+		t.Errorf("Expected no GetSchemaVersion calls, got %d", calls)
+	}
+
+	if _, err := s.Serialize(c, "Missing", event); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+// placedOrder adds a timestamp to Order, whose Shipping field is not encoded to JSON
+type placedOrder struct {
+	Order
+	PlacedAt time.Time `json:"placedAt"`
+}
+
+func TestJsonVerifyRoundTripIgnoresWhatJSONDropsOrNormalizes(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("OrderJSON", "JSON", orderJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// time.Now carries a monotonic reading and the local location, neither of which
+	// survives JSON, and Shipping is tagged json:"-"
+	order := placedOrder{
+		Order: Order{
+			OrderID:    "order-1",
+			Quantity:   3,
+			Tags:       []string{"priority"},
+			Attributes: map[string]int64{"weight": 12},
+			Shipping:   &Address{City: "Seattle"},
+		},
+		PlacedAt: time.Now(),
+	}
+	s := serializer.NewJsonSerializer(serializer.WithVerifyRoundTrip())
+	if _, err := s.Serialize(c, "OrderJSON", &order); err != nil {
+		t.Errorf("Expected the round trip to verify, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// JSONViolation describes one way in which a JSON payload fails its schema
+type JSONViolation struct {
+	// Path is the JSON pointer of the offending value; it is empty for the document root
+	Path string
+	// Message describes the failure, for example "additionalProperties 'extra' not allowed"
+	Message string
+}
+
+// JSONValidationError lists every violation found when validating a JSON payload against a JSON Schema
+type JSONValidationError struct {
+	Violations []JSONViolation
+}
+
+func (e *JSONValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = "(root)"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", path, v.Message))
+	}
+	return "JSON payload does not match schema: " + strings.Join(parts, "; ")
+}
+
+// ValidateJSON validates payload against the JSON Schema definition, enforcing every keyword
+// the schema declares, including "additionalProperties": false. It returns a
+// *JSONValidationError listing each violation when the payload does not conform.
+func ValidateJSON(definition string, payload []byte) error {
+	schema, err := compileJSONSchema(definition)
+	if err != nil {
+		return err
+	}
+	return validateJSONPayload(schema, payload)
+}
+
+func compileJSONSchema(definition string) (*jsonschema.Schema, error) {
+	schema, err := jsonschema.CompileString("schema.json", definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile JSON schema: %w", err)
+	}
+	return schema, nil
+}
+
+func validateJSONPayload(schema *jsonschema.Schema, payload []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("failed to parse JSON payload: %w", err)
+	}
+
+	return validateJSONDocument(schema, document)
+}
+
+func validateJSONDocument(schema *jsonschema.Schema, document interface{}) error {
+	err := schema.Validate(document)
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	result := &JSONValidationError{}
+	collectViolations(validationErr, result)
+	return result
+}
+
+// collectViolations flattens the library's error tree into its leaf failures
+func collectViolations(ve *jsonschema.ValidationError, result *JSONValidationError) {
+	if len(ve.Causes) == 0 {
+		result.Violations = append(result.Violations, JSONViolation{Path: ve.InstanceLocation, Message: ve.Message})
+		return
+	}
+	for _, cause := range ve.Causes {
+		collectViolations(cause, result)
+	}
+}
+
+// jsonSchemaCache holds compiled JSON Schemas keyed by their definition.
+// The zero value is an empty cache ready to use.
+type jsonSchemaCache struct {
+	mu      sync.RWMutex
+	schemas map[string]*jsonschema.Schema
+}
+
+// validate validates payload against definition, compiling the definition on first use
+func (jc *jsonSchemaCache) validate(definition string, payload []byte) error {
+	jc.mu.RLock()
+	schema, ok := jc.schemas[definition]
+	jc.mu.RUnlock()
+	if !ok {
+		var err error
+		if schema, err = compileJSONSchema(definition); err != nil {
+			return err
+		}
+		jc.mu.Lock()
+		if jc.schemas == nil {
+			jc.schemas = make(map[string]*jsonschema.Schema)
+		}
+		jc.schemas[definition] = schema
+		jc.mu.Unlock()
+	}
+	return validateJSONPayload(schema, payload)
+}
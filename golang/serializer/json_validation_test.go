@@ -0,0 +1,40 @@
+package serializer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestValidateJSONRejectsAdditionalProperties(t *testing.T) {
+	payload := []byte(`{
+		"eventId": "event-12345",
+		"eventName": "UserLogin",
+		"timestamp": 1704067200000,
+		"eventDetails": "User logged in successfully",
+		"ipAddress": "10.0.0.1"
+	}`)
+
+	err := serializer.ValidateJSON(gluetest.SalesforceAuditJSONSchema, payload)
+
+	var validationErr *serializer.JSONValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected JSONValidationError, got %v", err)
+	}
+	if len(validationErr.Violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %v", validationErr.Violations)
+	}
+	if !strings.Contains(validationErr.Violations[0].Message, "ipAddress") {
+		t.Errorf("Expected violation to name the extra property, got %q", validationErr.Violations[0].Message)
+	}
+}
+
+func TestValidateJSONAcceptsConformingPayload(t *testing.T) {
+	payload := []byte(`{"eventId": "event-12345", "eventName": "UserLogin", "timestamp": 1704067200000, "eventDetails": "User logged in successfully"}`)
+	if err := serializer.ValidateJSON(gluetest.SalesforceAuditJSONSchema, payload); err != nil {
+		t.Fatalf("Expected payload to be valid, got %v", err)
+	}
+}
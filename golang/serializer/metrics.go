@@ -0,0 +1,63 @@
+package serializer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+)
+
+// Operation names reported to client.MetricsRecorder
+const (
+	opSerialize   = "serialize"
+	opDeserialize = "deserialize"
+)
+
+// Error categories reported to client.MetricsRecorder
+const (
+	ErrorCategoryRegistry        = "registry"
+	ErrorCategoryPayloadTooLarge = "payload_too_large"
+	ErrorCategoryValidation      = "validation"
+	ErrorCategoryRoundTrip       = "round_trip"
+	ErrorCategoryEncode          = "encode"
+	ErrorCategoryDecode          = "decode"
+)
+
+// recordSerde reports a serialize or deserialize call to the configured MetricsRecorder, if any
+func (o *options) recordSerde(op, schemaName string, dataFormat client.DataFormat, start time.Time, err error) {
+	if o.metrics == nil {
+		return
+	}
+	o.metrics.RecordSerde(op, schemaName, string(dataFormat), time.Since(start), errorCategory(op, err))
+}
+
+// recordCacheHit reports a lookup in a compiled schema cache to the configured MetricsRecorder, if any
+func (o *options) recordCacheHit(schemaName string, hit bool) {
+	if o.metrics != nil {
+		o.metrics.RecordCacheHit(schemaName, hit)
+	}
+}
+
+// errorCategory classifies err for metrics; it returns an empty string for a nil error
+func errorCategory(op string, err error) string {
+	var registryErr *client.SchemaRegistryException
+	var mismatch *RecordKeyMismatchError
+	var violations *JSONValidationError
+	var protoMismatch *ProtobufSchemaMismatchError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &registryErr):
+		return ErrorCategoryRegistry
+	case errors.Is(err, ErrPayloadTooLarge):
+		return ErrorCategoryPayloadTooLarge
+	case errors.As(err, &mismatch), errors.As(err, &violations), errors.As(err, &protoMismatch):
+		return ErrorCategoryValidation
+	case errors.Is(err, ErrRoundTripMismatch):
+		return ErrorCategoryRoundTrip
+	case op == opSerialize:
+		return ErrorCategoryEncode
+	default:
+		return ErrorCategoryDecode
+	}
+}
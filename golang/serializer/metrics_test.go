@@ -0,0 +1,106 @@
+package serializer_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+type serdeEvent struct {
+	op, schemaName, dataFormat, errCategory string
+}
+
+// recordingMetrics keeps every serde event it receives
+type recordingMetrics struct {
+	client.NopMetricsRecorder
+
+	mu     sync.Mutex
+	events []serdeEvent
+}
+
+func (m *recordingMetrics) RecordSerde(op, schemaName, dataFormat string, latency time.Duration, errCategory string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, serdeEvent{op, schemaName, dataFormat, errCategory})
+}
+
+func TestSerializerRecordsSerdeMetrics(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("AuditAvro", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditJSON", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create JSON schema: %v", err)
+	}
+
+	metrics := &recordingMetrics{}
+	opts := []serializer.Option{serializer.WithMetrics(metrics), serializer.WithMaxPayloadSize(64)}
+	avroSerializer := serializer.NewAvroSerializer(opts...)
+	jsonSerializer := serializer.NewJsonSerializer(opts...)
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+
+	if _, err := avroSerializer.Serialize(c, "AuditAvro", event); err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if err := avroSerializer.Deserialize(c, "AuditAvro", []byte{0xff}, &model.SalesforceAudit{}); err == nil {
+		t.Fatal("Expected decode error")
+	}
+	if _, err := avroSerializer.Serialize(c, "Missing", event); err == nil {
+		t.Fatal("Expected registry error")
+	}
+	if err := jsonSerializer.Deserialize(c, "AuditJSON", make([]byte, 65), &model.SalesforceAudit{}); err == nil {
+		t.Fatal("Expected payload size error")
+	}
+
+	expected := []serdeEvent{
+		{"serialize", "AuditAvro", "AVRO", ""},
+		{"deserialize", "AuditAvro", "AVRO", serializer.ErrorCategoryDecode},
+		{"serialize", "Missing", "AVRO", serializer.ErrorCategoryRegistry},
+		{"deserialize", "AuditJSON", "JSON", serializer.ErrorCategoryPayloadTooLarge},
+	}
+	if !reflect.DeepEqual(metrics.events, expected) {
+		t.Errorf("Metrics mismatch:\nexpected %v\ngot      %v", expected, metrics.events)
+	}
+}
+
+// cacheMetrics keeps every compiled schema cache lookup it receives
+type cacheMetrics struct {
+	client.NopMetricsRecorder
+
+	mu   sync.Mutex
+	hits []bool
+}
+
+func (m *cacheMetrics) RecordCacheHit(schemaName string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hits = append(m.hits, hit)
+}
+
+func TestSerializerRecordsCacheHits(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	metrics := &cacheMetrics{}
+	s := serializer.NewAvroSerializer(serializer.WithMetrics(metrics))
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Serialize(c, "SalesforceAudit", event); err != nil {
+			t.Fatalf("Failed to serialize: %v", err)
+		}
+	}
+
+	if expected := []bool{false, true}; !reflect.DeepEqual(metrics.hits, expected) {
+		t.Errorf("Cache hits mismatch: expected %v, got %v", expected, metrics.hits)
+	}
+}
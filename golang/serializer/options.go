@@ -0,0 +1,135 @@
+package serializer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+)
+
+var (
+	// ErrPayloadTooLarge is returned when a payload exceeds the configured maximum size
+	ErrPayloadTooLarge = errors.New("payload exceeds maximum size")
+
+	// ErrRoundTripMismatch is returned when WithVerifyRoundTrip finds that the encoded
+	// bytes do not decode back to the input record
+	ErrRoundTripMismatch = errors.New("round-trip verification failed")
+)
+
+// Option configures an AvroSerializer, JsonSerializer or ProtobufSerializer
+type Option func(*options)
+
+// options holds the settings shared by the serializers. The zero value applies no limits.
+type options struct {
+	maxPayloadSize  int
+	verifyRoundTrip bool
+	sortMapKeys     bool
+	metrics         client.MetricsRecorder
+
+	strictJSONValidation bool
+	zlibCompression      bool
+	registrar            *registrar
+
+	decodeFallbackVersions []int64
+	schemaFetchTimeout     time.Duration
+}
+
+// WithMaxPayloadSize rejects payloads larger than n bytes before they are decoded.
+// A value of zero or less disables the check.
+//
+// The limit applies to the encoded input only. The number of items decoded into Avro
+// arrays and maps is bounded separately by goavro.MaxBlockCount and goavro.MaxBlockSize,
+// which are process-wide settings.
+func WithMaxPayloadSize(n int) Option {
+	return func(o *options) {
+		o.maxPayloadSize = n
+	}
+}
+
+// WithVerifyRoundTrip decodes every serialized payload and compares it to the input
+// record, returning ErrRoundTripMismatch if they differ. It roughly doubles the cost of
+// Serialize and is off by default.
+func WithVerifyRoundTrip() Option {
+	return func(o *options) {
+		o.verifyRoundTrip = true
+	}
+}
+
+// WithSortedMapKeys writes the entries of Avro maps in ascending key order, so that equal
+// records always serialize to identical bytes. By default map entries follow Go's
+// randomized map iteration order. Sorting adds encoding overhead proportional to the
+// number of map entries.
+func WithSortedMapKeys() Option {
+	return func(o *options) {
+		o.sortMapKeys = true
+	}
+}
+
+// WithStrictJSONValidation makes JsonSerializer validate every payload against the
+// registered JSON Schema: after encoding in Serialize and before decoding in Deserialize.
+// SerializeMap always validates. The Avro and Protobuf serializers ignore this.
+func WithStrictJSONValidation() Option {
+	return func(o *options) {
+		o.strictJSONValidation = true
+	}
+}
+
+// WithZlibCompression compresses the Avro body of payloads written by SerializeWithHeader
+// and SerializeParts with zlib, and sets the compression byte of the wire-format header
+// to that of the AWS Glue Schema Registry serde for Java, whose consumers decompress it.
+// Compressed payloads are decompressed by DeserializeWithHeader and DeserializeParts
+// whether or not this option is set. Serialize, whose payloads have no header, and the
+// JSON and Protobuf serializers ignore it.
+func WithZlibCompression() Option {
+	return func(o *options) {
+		o.zlibCompression = true
+	}
+}
+
+// WithMetrics records the latency and outcome of every serialize and deserialize call,
+// labelled with the schema name and data format, and every lookup in the Avro and
+// Protobuf serializers' caches of compiled schemas
+func WithMetrics(m client.MetricsRecorder) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithDecodeFallbackVersions makes the AvroSerializer retry a failed decode against each of
+// the given schema versions, in order, before giving up. A fallback is tried whether the
+// primary version could not be fetched or the payload did not decode against it, and the
+// first version that decodes the payload wins. If none does, the original error is
+// returned. JSON payloads are decoded without a schema, so JsonSerializer ignores this.
+func WithDecodeFallbackVersions(versions []int64) Option {
+	return func(o *options) {
+		o.decodeFallbackVersions = append([]int64(nil), versions...)
+	}
+}
+
+// WithSchemaFetchTimeout bounds each schema lookup made while serializing or
+// deserializing to d, separately from any deadline on the operation as a whole, so that a
+// slow registry fails fast instead of consuming the caller's budget. It applies to
+// registries with context-aware lookups, such as *client.GlueSchemaRegistryClient; lookups
+// on other registries are not bounded. A value of zero or less disables the timeout.
+func WithSchemaFetchTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.schemaFetchTimeout = d
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// checkPayloadSize returns ErrPayloadTooLarge if data exceeds the configured maximum
+func (o *options) checkPayloadSize(data []byte) error {
+	if o.maxPayloadSize > 0 && len(data) > o.maxPayloadSize {
+		return fmt.Errorf("%w: %d bytes (limit %d)", ErrPayloadTooLarge, len(data), o.maxPayloadSize)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package serializer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ProtobufSchemaMismatchError reports a message type that does not match the registered
+// Protobuf schema
+type ProtobufSchemaMismatchError struct {
+	// SchemaName is the registry schema the message was checked against
+	SchemaName string
+	// MessageName is the full name of the message type that was passed in
+	MessageName string
+	// Reason describes the first difference found
+	Reason string
+}
+
+func (e *ProtobufSchemaMismatchError) Error() string {
+	return fmt.Sprintf("message %s does not match schema %s: %s", e.MessageName, e.SchemaName, e.Reason)
+}
+
+// ProtobufSerializer provides Protobuf serialization/deserialization of proto.Message values.
+// The zero value is ready to use; NewProtobufSerializer applies options.
+//
+// The registered definition is compiled as a .proto file, which may import the well-known
+// types under google/protobuf but no other files. Before encoding or decoding, the message
+// type is checked against the message of the same full name in the registered file. A
+// ProtobufSerializer caches compiled definitions per schema version, is safe for concurrent
+// use and must not be copied after first use.
+type ProtobufSerializer struct {
+	options
+
+	mu          sync.RWMutex
+	descriptors map[codecKey]protoreflect.FileDescriptor
+}
+
+// NewProtobufSerializer creates a ProtobufSerializer configured with the given options
+func NewProtobufSerializer(opts ...Option) *ProtobufSerializer {
+	return &ProtobufSerializer{options: newOptions(opts)}
+}
+
+// Serialize serializes v, which must be a proto.Message, to Protobuf binary format
+func (s *ProtobufSerializer) Serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	start := time.Now()
+	binary, err := s.serialize(c, schemaName, v)
+	s.recordSerde(opSerialize, schemaName, DataFormatProtobuf, start, err)
+	return binary, err
+}
+
+func (s *ProtobufSerializer) serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cannot serialize %T: expected a proto.Message", v)
+	}
+	if err := s.ensureRegistered(c, schemaName, DataFormatProtobuf); err != nil {
+		return nil, err
+	}
+	if err := s.checkMessage(c, schemaName, msg); err != nil {
+		return nil, err
+	}
+
+	binary, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Protobuf message: %w", err)
+	}
+
+	if s.verifyRoundTrip {
+		decoded := msg.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal(binary, decoded); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRoundTripMismatch, err)
+		}
+		if !proto.Equal(msg, decoded) {
+			return nil, fmt.Errorf("%w: decoded %v, expected %v", ErrRoundTripMismatch, decoded, msg)
+		}
+	}
+
+	return binary, nil
+}
+
+// Deserialize deserializes Protobuf binary data into out, which must be a non-nil proto.Message
+func (s *ProtobufSerializer) Deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	start := time.Now()
+	err := s.deserialize(c, schemaName, data, out)
+	s.recordSerde(opDeserialize, schemaName, DataFormatProtobuf, start, err)
+	return err
+}
+
+func (s *ProtobufSerializer) deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	if err := checkOut(out); err != nil {
+		return err
+	}
+	msg, ok := out.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cannot deserialize into %T: expected a proto.Message", out)
+	}
+	if err := s.checkPayloadSize(data); err != nil {
+		return err
+	}
+	if err := s.checkMessage(c, schemaName, msg); err != nil {
+		return err
+	}
+
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("failed to unmarshal Protobuf message: %w", err)
+	}
+	return nil
+}
+
+// checkMessage compares msg's type with the latest registered version of the schema
+func (s *ProtobufSerializer) checkMessage(c client.Registry, schemaName string, msg proto.Message) error {
+	file, err := s.latestDescriptor(c, schemaName)
+	if err != nil {
+		return err
+	}
+	got := msg.ProtoReflect().Descriptor()
+	if reason := compareMessage(file.Messages(), got); reason != "" {
+		return &ProtobufSchemaMismatchError{SchemaName: schemaName, MessageName: string(got.FullName()), Reason: reason}
+	}
+	return nil
+}
+
+// latestDescriptor returns the compiled latest version of a schema. As for Avro, only the
+// version fetch and compilation are cached.
+func (s *ProtobufSerializer) latestDescriptor(c client.Registry, schemaName string) (protoreflect.FileDescriptor, error) {
+	ctx, cancel := s.fetchContext()
+	defer cancel()
+
+	schemaResponse, err := s.getSchema(ctx, c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := latestVersionNumber(schemaName, schemaResponse)
+	if err != nil {
+		return nil, err
+	}
+	key := codecKey{
+		registryName: aws.StringValue(schemaResponse.RegistryName),
+		schemaName:   schemaName,
+		version:      latest,
+	}
+	s.mu.RLock()
+	file, ok := s.descriptors[key]
+	s.mu.RUnlock()
+	s.recordCacheHit(schemaName, ok)
+	if ok {
+		return file, nil
+	}
+
+	version, err := s.fetchVersion(ctx, c, schemaName, key.version)
+	if err != nil {
+		return nil, err
+	}
+	file, err = compileProto(ctx, aws.StringValue(version.SchemaDefinition))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.descriptors == nil {
+		s.descriptors = make(map[codecKey]protoreflect.FileDescriptor)
+	}
+	s.descriptors[key] = file
+	return file, nil
+}
+
+// compileProto compiles a .proto definition that imports nothing but the well-known types
+func compileProto(ctx context.Context, definition string) (protoreflect.FileDescriptor, error) {
+	const path = "schema.proto"
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{path: definition}),
+		}),
+	}
+	files, err := compiler.Compile(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile Protobuf schema definition: %w", err)
+	}
+	return files[0], nil
+}
+
+// compareMessage finds the registered message with got's full name and returns a
+// description of the first field that differs, or an empty string if they match. Fields
+// registered but missing from got are allowed, so older generated code keeps working.
+func compareMessage(registered protoreflect.MessageDescriptors, got protoreflect.MessageDescriptor) string {
+	want := findMessage(registered, got.FullName())
+	if want == nil {
+		return "the registered schema does not define this message"
+	}
+
+	fields := got.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		wantField := want.Fields().ByNumber(field.Number())
+		if wantField == nil {
+			return fmt.Sprintf("field %s (%d) is not in the registered schema", field.Name(), field.Number())
+		}
+		if describeField(wantField) != describeField(field) {
+			return fmt.Sprintf("field %d is %s in the registered schema, got %s",
+				field.Number(), describeField(wantField), describeField(field))
+		}
+	}
+	return ""
+}
+
+// findMessage searches messages and their nested messages for name
+func findMessage(messages protoreflect.MessageDescriptors, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if md.FullName() == name {
+			return md
+		}
+		if nested := findMessage(md.Messages(), name); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
+
+// describeField renders the parts of a field that must agree for the encodings to be compatible
+func describeField(fd protoreflect.FieldDescriptor) string {
+	return fmt.Sprintf("%s %s", fieldType(fd), fd.Name())
+}
+
+func fieldType(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.IsMap():
+		return fmt.Sprintf("map<%s, %s>", fieldType(fd.MapKey()), fieldType(fd.MapValue()))
+	case fd.IsList():
+		return "repeated " + elementType(fd)
+	default:
+		return elementType(fd)
+	}
+}
+
+func elementType(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.Message() != nil:
+		return string(fd.Message().FullName())
+	case fd.Enum() != nil:
+		return string(fd.Enum().FullName())
+	default:
+		return fd.Kind().String()
+	}
+}
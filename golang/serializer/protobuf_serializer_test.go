@@ -0,0 +1,120 @@
+package serializer_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const auditProtoSchema = `syntax = "proto3";
+package com.example;
+
+import "google/protobuf/timestamp.proto";
+
+message AuditEvent {
+  string event_id = 1;
+  string event_name = 2;
+  google.protobuf.Timestamp occurred_at = 3;
+  repeated string tags = 4;
+}
+`
+
+// newProtoMessage compiles definition and returns an empty dynamic message of the named type
+func newProtoMessage(t *testing.T, definition string, name protoreflect.Name) *dynamicpb.Message {
+	t.Helper()
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"audit.proto": definition}),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), "audit.proto")
+	if err != nil {
+		t.Fatalf("Failed to compile proto definition: %v", err)
+	}
+	return dynamicpb.NewMessage(files[0].Messages().ByName(name))
+}
+
+func TestProtobufSerializer(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("AuditEvent", serializer.DataFormatProtobuf, auditProtoSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	event := newProtoMessage(t, auditProtoSchema, "AuditEvent")
+	fields := event.Descriptor().Fields()
+	event.Set(fields.ByName("event_id"), protoreflect.ValueOfString("event-12345"))
+	event.Set(fields.ByName("event_name"), protoreflect.ValueOfString("UserLogin"))
+	tags := event.NewField(fields.ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("security"))
+	event.Set(fields.ByName("tags"), protoreflect.ValueOfList(tags))
+
+	var s serializer.Serializer = serializer.NewProtobufSerializer(serializer.WithVerifyRoundTrip())
+	data, err := s.Serialize(c, "AuditEvent", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	decoded := dynamicpb.NewMessage(event.Descriptor())
+	if err := s.Deserialize(c, "AuditEvent", data, decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if !proto.Equal(event, decoded) {
+		t.Errorf("Message mismatch: expected %v, got %v", event, decoded)
+	}
+
+	// The SmartSerializer routes PROTOBUF schemas to the Protobuf serializer
+	if _, err := serializer.NewSmartSerializer().Serialize(c, "AuditEvent", event); err != nil {
+		t.Errorf("Failed to serialize through SmartSerializer: %v", err)
+	}
+
+	if _, err := s.Serialize(c, "AuditEvent", map[string]interface{}{"event_id": "event-12345"}); err == nil {
+		t.Error("Expected error serializing a value that is not a proto.Message")
+	}
+}
+
+func TestProtobufSchemaMismatch(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("AuditEvent", serializer.DataFormatProtobuf, auditProtoSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	protobufSerializer := serializer.NewProtobufSerializer()
+
+	changedType := strings.Replace(auditProtoSchema, "repeated string tags = 4;", "int64 tags = 4;", 1)
+	withExtra := strings.Replace(auditProtoSchema, "repeated string tags = 4;", "repeated string tags = 4;\n  string source = 5;", 1)
+	tests := []struct {
+		name   string
+		msg    proto.Message
+		reason string
+	}{
+		{name: "OtherMessage", msg: wrapperspb.String("event-12345"), reason: "does not define this message"},
+		{name: "ChangedFieldType", msg: newProtoMessage(t, changedType, "AuditEvent"), reason: "field 4 is repeated string tags"},
+		{name: "UnknownField", msg: newProtoMessage(t, withExtra, "AuditEvent"), reason: "field source (5)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := protobufSerializer.Serialize(c, "AuditEvent", tt.msg)
+			var mismatch *serializer.ProtobufSchemaMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("Expected ProtobufSchemaMismatchError, got %v", err)
+			}
+			if !strings.Contains(mismatch.Reason, tt.reason) {
+				t.Errorf("Reason mismatch: expected it to contain %q, got %q", tt.reason, mismatch.Reason)
+			}
+
+			if err := protobufSerializer.Deserialize(c, "AuditEvent", nil, tt.msg); !errors.As(err, &mismatch) {
+				t.Errorf("Expected ProtobufSchemaMismatchError on deserialize, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RecordKeyMismatchError reports record keys that do not line up with the fields of an Avro record schema
+type RecordKeyMismatchError struct {
+	// Missing lists required schema fields that are absent from the record
+	Missing []string
+	// Unexpected lists record keys that the schema does not define
+	Unexpected []string
+}
+
+func (e *RecordKeyMismatchError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing fields [%s]", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Unexpected) > 0 {
+		parts = append(parts, fmt.Sprintf("unexpected fields [%s]", strings.Join(e.Unexpected, ", ")))
+	}
+	return "record does not match schema: " + strings.Join(parts, "; ")
+}
+
+// ValidateRecordKeys checks that the keys of record match the fields of the Avro record schema.
+// Fields that declare a default may be omitted. It returns a *RecordKeyMismatchError listing
+// the missing and unexpected keys when they differ.
+func ValidateRecordKeys(definition string, record map[string]interface{}) error {
+	var schemaJSON map[string]interface{}
+	if err := json.Unmarshal([]byte(definition), &schemaJSON); err != nil {
+		return fmt.Errorf("failed to parse schema definition: %w", err)
+	}
+	return validateRecordKeys(schemaJSON, record)
+}
+
+func validateRecordKeys(schemaJSON map[string]interface{}, record map[string]interface{}) error {
+	fields, ok := schemaJSON["fields"].([]interface{})
+	if !ok {
+		return fmt.Errorf("schema definition is not an Avro record")
+	}
+
+	mismatch := &RecordKeyMismatchError{}
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		known[name] = true
+
+		_, hasDefault := field["default"]
+		if _, present := record[name]; !present && !hasDefault {
+			mismatch.Missing = append(mismatch.Missing, name)
+		}
+	}
+	for key := range record {
+		if !known[key] {
+			mismatch.Unexpected = append(mismatch.Unexpected, key)
+		}
+	}
+
+	if len(mismatch.Missing) == 0 && len(mismatch.Unexpected) == 0 {
+		return nil
+	}
+	sort.Strings(mismatch.Missing)
+	sort.Strings(mismatch.Unexpected)
+	return mismatch
+}
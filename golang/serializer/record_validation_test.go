@@ -0,0 +1,47 @@
+package serializer_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestValidateRecordKeysMisspelledKey(t *testing.T) {
+	record := map[string]interface{}{
+		"eventID":      "event-12345",
+		"eventName":    "UserLogin",
+		"timestamp":    int64(1704067200000),
+		"eventDetails": "User logged in successfully",
+	}
+
+	err := serializer.ValidateRecordKeys(gluetest.SalesforceAuditAvroSchema, record)
+
+	var mismatch *serializer.RecordKeyMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected RecordKeyMismatchError, got %v", err)
+	}
+	if !reflect.DeepEqual(mismatch.Missing, []string{"eventId"}) {
+		t.Errorf("Missing mismatch: expected [eventId], got %v", mismatch.Missing)
+	}
+	if !reflect.DeepEqual(mismatch.Unexpected, []string{"eventID"}) {
+		t.Errorf("Unexpected mismatch: expected [eventID], got %v", mismatch.Unexpected)
+	}
+}
+
+func TestValidateRecordKeysAllowsOmittedDefaults(t *testing.T) {
+	definition := `{
+		"type": "record",
+		"name": "SalesforceAudit",
+		"fields": [
+			{"name": "eventId", "type": "string"},
+			{"name": "eventDetails", "type": ["null", "string"], "default": null}
+		]
+	}`
+
+	if err := serializer.ValidateRecordKeys(definition, map[string]interface{}{"eventId": "event-12345"}); err != nil {
+		t.Fatalf("Expected record to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package serializer_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// stubRegistry serves fixed schema definitions, one version per schema
+type stubRegistry struct {
+	dataFormats map[string]string
+	definitions map[string]string
+}
+
+var _ client.Registry = (*stubRegistry)(nil)
+
+func (r *stubRegistry) CreateSchema(schemaName string, dataFormat client.DataFormat, schemaDefinition string, compatibility client.Compatibility) (*glue.CreateSchemaOutput, error) {
+	return nil, fmt.Errorf("CreateSchema not supported")
+}
+
+func (r *stubRegistry) GetSchema(schemaName string) (*glue.GetSchemaOutput, error) {
+	if _, ok := r.definitions[schemaName]; !ok {
+		return nil, fmt.Errorf("schema not found: %s", schemaName)
+	}
+	return &glue.GetSchemaOutput{
+		SchemaName:          aws.String(schemaName),
+		DataFormat:          aws.String(r.dataFormats[schemaName]),
+		LatestSchemaVersion: aws.Int64(1),
+	}, nil
+}
+
+func (r *stubRegistry) GetDataFormat(schemaName string) (string, error) {
+	return r.dataFormats[schemaName], nil
+}
+
+func (r *stubRegistry) GetSchemaVersion(schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error) {
+	definition, ok := r.definitions[schemaName]
+	if !ok || versionNumber != 1 {
+		return nil, fmt.Errorf("schema version not found: %s (version %d)", schemaName, versionNumber)
+	}
+	return &glue.GetSchemaVersionOutput{
+		SchemaDefinition: aws.String(definition),
+		VersionNumber:    aws.Int64(versionNumber),
+	}, nil
+}
+
+func (r *stubRegistry) RegisterSchemaVersion(schemaName, schemaDefinition string) (*glue.RegisterSchemaVersionOutput, error) {
+	return nil, fmt.Errorf("RegisterSchemaVersion not supported")
+}
+
+func TestAvroSerializationWithStubRegistry(t *testing.T) {
+	registry := &stubRegistry{
+		dataFormats: map[string]string{"SalesforceAudit": "AVRO"},
+		definitions: map[string]string{"SalesforceAudit": gluetest.SalesforceAuditAvroSchema},
+	}
+
+	avroSerializer := &serializer.AvroSerializer{}
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	serializedData, err := avroSerializer.Serialize(registry, "SalesforceAudit", originalEvent)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	deserializedEvent := &model.SalesforceAudit{}
+	err = avroSerializer.Deserialize(registry, "SalesforceAudit", serializedData, deserializedEvent)
+	if err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if *deserializedEvent != *originalEvent {
+		t.Errorf("Event mismatch: expected %+v, got %+v", originalEvent, deserializedEvent)
+	}
+}
+
+// noLatestRegistry reports its schemas without a latest version, as Glue does when the
+// only version of a schema failed validation
+type noLatestRegistry struct {
+	stubRegistry
+}
+
+func (r *noLatestRegistry) GetSchema(schemaName string) (*glue.GetSchemaOutput, error) {
+	schema, err := r.stubRegistry.GetSchema(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	schema.LatestSchemaVersion = nil
+	return schema, nil
+}
+
+func TestSerializersWithoutLatestVersion(t *testing.T) {
+	registry := &noLatestRegistry{stubRegistry{
+		dataFormats: map[string]string{"AuditAvro": "AVRO", "AuditJSON": "JSON", "AuditEvent": "PROTOBUF"},
+		definitions: map[string]string{
+			"AuditAvro":  gluetest.SalesforceAuditAvroSchema,
+			"AuditJSON":  gluetest.SalesforceAuditJSONSchema,
+			"AuditEvent": auditProtoSchema,
+		},
+	}}
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+
+	if _, err := serializer.NewAvroSerializer().Serialize(registry, "AuditAvro", event); !errors.Is(err, client.ErrNoLatestVersion) {
+		t.Errorf("Expected ErrNoLatestVersion from the Avro serializer, got %v", err)
+	}
+	if _, err := serializer.NewJsonSerializer(serializer.WithStrictJSONValidation()).Serialize(registry, "AuditJSON", event); !errors.Is(err, client.ErrNoLatestVersion) {
+		t.Errorf("Expected ErrNoLatestVersion from the JSON serializer, got %v", err)
+	}
+	message := newProtoMessage(t, auditProtoSchema, "AuditEvent")
+	if _, err := serializer.NewProtobufSerializer().Serialize(registry, "AuditEvent", message); !errors.Is(err, client.ErrNoLatestVersion) {
+		t.Errorf("Expected ErrNoLatestVersion from the Protobuf serializer, got %v", err)
+	}
+}
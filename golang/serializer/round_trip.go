@@ -0,0 +1,160 @@
+package serializer
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// verifyAvroRoundTrip decodes binary with codec and checks that the result matches record
+func verifyAvroRoundTrip(codec *goavro.Codec, binary []byte, record map[string]interface{}) error {
+	datum, _, err := codec.NativeFromBinary(binary)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRoundTripMismatch, err)
+	}
+	if !nativeEqual(record, datum) {
+		return fmt.Errorf("%w: decoded %v, expected %v", ErrRoundTripMismatch, datum, record)
+	}
+	return nil
+}
+
+// nativeEqual reports whether decoded is the goavro native form of expected. Numbers
+// are compared by value so that, for example, an int input matches a decoded int64,
+// typed slices and maps such as []string are compared element by element with the
+// []interface{} and map[string]interface{} they decode to, and keys absent from an
+// expected map are ignored because the decoder fills them with schema defaults.
+func nativeEqual(expected, decoded interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		d, ok := decoded.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, ev := range e {
+			dv, ok := d[key]
+			if !ok || !nativeEqual(ev, dv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		d, ok := decoded.([]interface{})
+		if !ok || len(e) != len(d) {
+			return false
+		}
+		for i := range e {
+			if !nativeEqual(e[i], d[i]) {
+				return false
+			}
+		}
+		return true
+	case []byte:
+		d, ok := decoded.([]byte)
+		return ok && bytes.Equal(e, d)
+	}
+
+	ev, dv := reflect.ValueOf(expected), reflect.ValueOf(decoded)
+	switch {
+	case ev.Kind() == reflect.Map && ev.Type().Key().Kind() == reflect.String:
+		d, ok := decoded.(map[string]interface{})
+		if !ok {
+			// A nil map encodes as JSON null
+			return ev.IsNil() && decoded == nil
+		}
+		for iter := ev.MapRange(); iter.Next(); {
+			dv, ok := d[iter.Key().String()]
+			if !ok || !nativeEqual(iter.Value().Interface(), dv) {
+				return false
+			}
+		}
+		return true
+	case (ev.Kind() == reflect.Slice || ev.Kind() == reflect.Array) && ev.Type().Elem().Kind() != reflect.Uint8:
+		d, ok := decoded.([]interface{})
+		if !ok {
+			// A nil slice encodes as JSON null
+			return ev.Len() == 0 && decoded == nil
+		}
+		if ev.Len() != len(d) {
+			return false
+		}
+		for i := range d {
+			if !nativeEqual(ev.Index(i).Interface(), d[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if isNumeric(ev) && isNumeric(dv) {
+		return numericEqual(ev, dv)
+	}
+	if d, ok := decoded.(time.Time); ok {
+		// Timestamp logical types accept epoch numbers and decode as time.Time in UTC
+		if e, ok := expected.(time.Time); ok {
+			return e.Equal(d)
+		}
+		if isNumeric(ev) {
+			return numericEqual(ev, reflect.ValueOf(d.UnixMilli())) || numericEqual(ev, reflect.ValueOf(d.UnixMicro()))
+		}
+	}
+	return reflect.DeepEqual(expected, decoded)
+}
+
+func isNumeric(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// numericEqual compares two numeric values. Integers are compared exactly, since longs
+// beyond 2^53 do not survive conversion to float64, and NaN equals NaN so that a NaN double
+// verifies.
+func numericEqual(e, d reflect.Value) bool {
+	if isSigned(e) && isSigned(d) {
+		return e.Int() == d.Int()
+	}
+	if isUnsigned(e) && isUnsigned(d) {
+		return e.Uint() == d.Uint()
+	}
+	if isSigned(e) && isUnsigned(d) {
+		return e.Int() >= 0 && uint64(e.Int()) == d.Uint()
+	}
+	if isUnsigned(e) && isSigned(d) {
+		return d.Int() >= 0 && e.Uint() == uint64(d.Int())
+	}
+	ef, df := toFloat(e), toFloat(d)
+	return ef == df || (math.IsNaN(ef) && math.IsNaN(df))
+}
+
+func isSigned(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUnsigned(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
@@ -0,0 +1,163 @@
+package serializer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// contextRegistry is implemented by registries whose lookups accept a context, which is
+// needed to apply WithSchemaFetchTimeout
+type contextRegistry interface {
+	GetSchemaWithContext(ctx context.Context, schemaName string) (*glue.GetSchemaOutput, error)
+	GetSchemaVersionWithContext(ctx context.Context, schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error)
+}
+
+// fetchContext returns the context for one schema lookup
+func (o *options) fetchContext() (context.Context, context.CancelFunc) {
+	if o.schemaFetchTimeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), o.schemaFetchTimeout)
+}
+
+// latestDefinitionRegistry is implemented by registries that can fetch the latest
+// definition of a schema in one lookup, as GlueSchemaRegistryClient does
+type latestDefinitionRegistry interface {
+	GetLatestSchemaDefinitionWithContext(ctx context.Context, schemaName string) (string, int64, string, error)
+}
+
+// latestDefinition fetches the definition of the latest version of a schema
+func (o *options) latestDefinition(c client.Registry, schemaName string) (string, error) {
+	lr, ok := c.(latestDefinitionRegistry)
+	if !ok {
+		version, err := o.latestVersion(c, schemaName)
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(version.SchemaDefinition), nil
+	}
+
+	ctx, cancel := o.fetchContext()
+	defer cancel()
+	definition, _, _, err := lr.GetLatestSchemaDefinitionWithContext(ctx, schemaName)
+	if err != nil {
+		return "", err
+	}
+	return definition, nil
+}
+
+// dataFormatRegistry is implemented by registries whose data format lookups accept a
+// context, as GlueSchemaRegistryClient's do
+type dataFormatRegistry interface {
+	GetDataFormatWithContext(ctx context.Context, schemaName string) (string, error)
+}
+
+// dataFormat fetches the data format of a schema, which fails if the schema does not exist
+func (o *options) dataFormat(c client.Registry, schemaName string) (string, error) {
+	var dataFormat string
+	var err error
+	if dr, ok := c.(dataFormatRegistry); ok {
+		ctx, cancel := o.fetchContext()
+		defer cancel()
+		dataFormat, err = dr.GetDataFormatWithContext(ctx, schemaName)
+	} else {
+		dataFormat, err = c.GetDataFormat(schemaName)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get data format: %w", err)
+	}
+	return dataFormat, nil
+}
+
+// versionByIDRegistry is implemented by registries that can fetch a schema version by its
+// ID, as GlueSchemaRegistryClient does
+type versionByIDRegistry interface {
+	GetSchemaVersionByIdWithContext(ctx context.Context, schemaVersionID string) (*glue.GetSchemaVersionOutput, error)
+}
+
+// versionByID fetches the schema version with the given ID, reporting false if the
+// registry cannot look versions up by ID
+func (o *options) versionByID(c client.Registry, schemaVersionID string) (*glue.GetSchemaVersionOutput, bool, error) {
+	vr, ok := c.(versionByIDRegistry)
+	if !ok {
+		return nil, false, nil
+	}
+
+	ctx, cancel := o.fetchContext()
+	defer cancel()
+	version, err := vr.GetSchemaVersionByIdWithContext(ctx, schemaVersionID)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, true, nil
+}
+
+// latestVersion fetches the latest version of a schema
+func (o *options) latestVersion(c client.Registry, schemaName string) (*glue.GetSchemaVersionOutput, error) {
+	ctx, cancel := o.fetchContext()
+	defer cancel()
+
+	schemaResponse, err := o.getSchema(ctx, c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := latestVersionNumber(schemaName, schemaResponse)
+	if err != nil {
+		return nil, err
+	}
+	return o.fetchVersion(ctx, c, schemaName, latest)
+}
+
+// latestVersionNumber returns the latest version number of a schema, or an error matching
+// client.ErrNoLatestVersion if Glue reports none, as for a schema whose only version
+// failed validation
+func latestVersionNumber(schemaName string, schemaResponse *glue.GetSchemaOutput) (int64, error) {
+	if schemaResponse.LatestSchemaVersion == nil {
+		return 0, fmt.Errorf("failed to get schema version: %s: %w", schemaName, client.ErrNoLatestVersion)
+	}
+	return *schemaResponse.LatestSchemaVersion, nil
+}
+
+func (o *options) getSchema(ctx context.Context, c client.Registry, schemaName string) (*glue.GetSchemaOutput, error) {
+	// Get schema definition from Glue Schema Registry
+	var schemaResponse *glue.GetSchemaOutput
+	var err error
+	if cr, ok := c.(contextRegistry); ok {
+		schemaResponse, err = cr.GetSchemaWithContext(ctx, schemaName)
+	} else {
+		schemaResponse, err = c.GetSchema(schemaName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+
+	return schemaResponse, nil
+}
+
+// version fetches a specific version of a schema
+func (o *options) version(c client.Registry, schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error) {
+	ctx, cancel := o.fetchContext()
+	defer cancel()
+
+	return o.fetchVersion(ctx, c, schemaName, versionNumber)
+}
+
+func (o *options) fetchVersion(ctx context.Context, c client.Registry, schemaName string, versionNumber int64) (*glue.GetSchemaVersionOutput, error) {
+	var schemaVersionResponse *glue.GetSchemaVersionOutput
+	var err error
+	if cr, ok := c.(contextRegistry); ok {
+		schemaVersionResponse, err = cr.GetSchemaVersionWithContext(ctx, schemaName, versionNumber)
+	} else {
+		schemaVersionResponse, err = c.GetSchemaVersion(schemaName, versionNumber)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	return schemaVersionResponse, nil
+}
@@ -0,0 +1,40 @@
+package serializer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestSchemaFetchTimeout(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	fake.SetLatency("GetSchemaVersion", 100*time.Millisecond)
+
+	auditEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	// A slow registry is tolerated when the fetch budget allows for it
+	if _, err := serializer.NewAvroSerializer(serializer.WithSchemaFetchTimeout(time.Second)).Serialize(c, "SalesforceAudit", auditEvent); err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	_, err := serializer.NewAvroSerializer(serializer.WithSchemaFetchTimeout(10*time.Millisecond)).Serialize(c, "SalesforceAudit", auditEvent)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
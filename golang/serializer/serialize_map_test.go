@@ -0,0 +1,98 @@
+package serializer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestSerializeMap(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := c.CreateSchema("SalesforceAuditJson", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	record := map[string]interface{}{
+		"eventId":      "event-12345",
+		"eventName":    "UserLogin",
+		"timestamp":    int64(1704067200000),
+		"eventDetails": "User logged in successfully",
+	}
+	invalid := map[string]interface{}{
+		"eventId":      "event-12345",
+		"eventNmae":    "UserLogin",
+		"timestamp":    int64(1704067200000),
+		"eventDetails": "User logged in successfully",
+	}
+
+	t.Run("avro", func(t *testing.T) {
+		avroSerializer := serializer.NewAvroSerializer(serializer.WithVerifyRoundTrip())
+		serializedData, err := avroSerializer.SerializeMap(c, "SalesforceAudit", record)
+		if err != nil {
+			t.Fatalf("Failed to serialize map: %v", err)
+		}
+		deserializedEvent := &model.SalesforceAudit{}
+		err = avroSerializer.Deserialize(c, "SalesforceAudit", serializedData, deserializedEvent)
+		if err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+		if deserializedEvent.EventName != "UserLogin" {
+			t.Errorf("EventName mismatch: expected UserLogin, got %s", deserializedEvent.EventName)
+		}
+
+		var mismatch *serializer.RecordKeyMismatchError
+		if _, err := avroSerializer.SerializeMap(c, "SalesforceAudit", invalid); !errors.As(err, &mismatch) {
+			t.Errorf("Expected RecordKeyMismatchError, got %v", err)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		jsonSerializer := serializer.NewJsonSerializer(serializer.WithVerifyRoundTrip())
+		serializedData, err := jsonSerializer.SerializeMap(c, "SalesforceAuditJson", record)
+		if err != nil {
+			t.Fatalf("Failed to serialize map: %v", err)
+		}
+		deserializedEvent := &model.SalesforceAudit{}
+		err = jsonSerializer.Deserialize(c, "SalesforceAuditJson", serializedData, deserializedEvent)
+		if err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+		if deserializedEvent.EventName != "UserLogin" {
+			t.Errorf("EventName mismatch: expected UserLogin, got %s", deserializedEvent.EventName)
+		}
+
+		var violations *serializer.JSONValidationError
+		if _, err := jsonSerializer.SerializeMap(c, "SalesforceAuditJson", invalid); !errors.As(err, &violations) {
+			t.Errorf("Expected JSONValidationError, got %v", err)
+		}
+	})
+}
+
+func TestJsonSerializeMapVerifiesTypedValues(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("OrderJSON", "JSON", orderJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// Typed slices and maps decode as []interface{} and map[string]interface{}
+	record := map[string]interface{}{
+		"orderId":    "order-1",
+		"quantity":   3,
+		"tags":       []string{"priority", "gift"},
+		"attributes": map[string]int64{"weight": 12},
+		"notes":      []string(nil),
+	}
+	jsonSerializer := serializer.NewJsonSerializer(serializer.WithVerifyRoundTrip())
+	if _, err := jsonSerializer.SerializeMap(c, "OrderJSON", record); err != nil {
+		t.Errorf("Expected the round trip to verify, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package serializer
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+)
+
+// Serializer converts Go values to and from the encoding of a registered schema.
+// AvroSerializer, JsonSerializer, ProtobufSerializer and SmartSerializer implement it.
+type Serializer interface {
+	// Serialize encodes v using the latest version of the schema
+	Serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error)
+	// Deserialize decodes data into out, which must be a non-nil pointer
+	Deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error
+}
+
+var (
+	_ Serializer = (*AvroSerializer)(nil)
+	_ Serializer = (*JsonSerializer)(nil)
+	_ Serializer = (*ProtobufSerializer)(nil)
+	_ Serializer = (*SmartSerializer)(nil)
+)
+
+// checkOut returns an error unless out is a non-nil pointer that a decoded value can be stored in
+func checkOut(out interface{}) error {
+	if v := reflect.ValueOf(out); v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("cannot deserialize into %T: expected a non-nil pointer", out)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package serializer
+
+import (
+	"fmt"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Data formats reported by Glue Schema Registry, the client.DataFormat constants under the
+// names the serializers have always used
+const (
+	DataFormatAvro     = client.DataFormatAvro
+	DataFormatJSON     = client.DataFormatJSON
+	DataFormatProtobuf = client.DataFormatProtobuf
+)
+
+// SmartSerializer dispatches to the Avro, JSON or Protobuf serializer based on the data format
+// registered for the schema, so callers do not need to know each schema's format.
+// The zero value uses default-configured serializers, created per call and so without
+// caching. A SmartSerializer is safe for concurrent use as long as its fields are not
+// changed after first use.
+type SmartSerializer struct {
+	Avro     *AvroSerializer
+	Json     *JsonSerializer
+	Protobuf *ProtobufSerializer
+}
+
+// NewSmartSerializer creates a SmartSerializer whose serializers share the given options
+func NewSmartSerializer(opts ...Option) *SmartSerializer {
+	return &SmartSerializer{
+		Avro:     NewAvroSerializer(opts...),
+		Json:     NewJsonSerializer(opts...),
+		Protobuf: NewProtobufSerializer(opts...),
+	}
+}
+
+// Serialize serializes v using the schema's registered data format
+func (s *SmartSerializer) Serialize(c client.Registry, schemaName string, v interface{}) ([]byte, error) {
+	delegate, err := s.serializerFor(c, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	return delegate.Serialize(c, schemaName, v)
+}
+
+// Deserialize deserializes data into out using the schema's registered data format
+func (s *SmartSerializer) Deserialize(c client.Registry, schemaName string, data []byte, out interface{}) error {
+	delegate, err := s.serializerFor(c, schemaName)
+	if err != nil {
+		return err
+	}
+	return delegate.Deserialize(c, schemaName, data, out)
+}
+
+func (s *SmartSerializer) serializerFor(c client.Registry, schemaName string) (Serializer, error) {
+	dataFormat, err := c.GetDataFormat(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data format: %w", err)
+	}
+
+	switch client.DataFormat(dataFormat) {
+	case DataFormatAvro:
+		if s.Avro == nil {
+			return &AvroSerializer{}, nil
+		}
+		return s.Avro, nil
+	case DataFormatJSON:
+		if s.Json == nil {
+			return &JsonSerializer{}, nil
+		}
+		return s.Json, nil
+	case DataFormatProtobuf:
+		if s.Protobuf == nil {
+			return &ProtobufSerializer{}, nil
+		}
+		return s.Protobuf, nil
+	default:
+		return nil, fmt.Errorf("unsupported data format %q for schema %s", dataFormat, schemaName)
+	}
+}
+
+// NewSerializerForSchema looks up the schema's data format with GetSchema and returns a new
+// serializer for that format with the given options. Unlike SmartSerializer, which looks the
+// format up on every call, the returned serializer is tied to the format the schema had
+// when it was created.
+func NewSerializerForSchema(c client.Registry, schemaName string, opts ...Option) (Serializer, error) {
+	schema, err := c.GetSchema(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+
+	switch dataFormat := client.DataFormat(aws.StringValue(schema.DataFormat)); dataFormat {
+	case DataFormatAvro:
+		return NewAvroSerializer(opts...), nil
+	case DataFormatJSON:
+		return NewJsonSerializer(opts...), nil
+	case DataFormatProtobuf:
+		return NewProtobufSerializer(opts...), nil
+	default:
+		return nil, fmt.Errorf("unsupported data format %q for schema %s", dataFormat, schemaName)
+	}
+}
@@ -0,0 +1,107 @@
+package serializer_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestSmartSerializerDispatchesByDataFormat(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("AuditAvro", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditJSON", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create JSON schema: %v", err)
+	}
+
+	smartSerializer := serializer.NewSmartSerializer()
+	originalEvent := &model.SalesforceAudit{
+		EventID:      "event-12345",
+		EventName:    "UserLogin",
+		Timestamp:    1704067200000,
+		EventDetails: "User logged in successfully",
+	}
+
+	for _, schemaName := range []string{"AuditAvro", "AuditJSON"} {
+		t.Run(schemaName, func(t *testing.T) {
+			serializedData, err := smartSerializer.Serialize(c, schemaName, originalEvent)
+			if err != nil {
+				t.Fatalf("Failed to serialize: %v", err)
+			}
+
+			isJSON := bytes.HasPrefix(serializedData, []byte("{"))
+			if isJSON != (schemaName == "AuditJSON") {
+				t.Errorf("Unexpected encoding for %s: %q", schemaName, serializedData)
+			}
+
+			deserializedEvent := &model.SalesforceAudit{}
+			err = smartSerializer.Deserialize(c, schemaName, serializedData, deserializedEvent)
+			if err != nil {
+				t.Fatalf("Failed to deserialize: %v", err)
+			}
+			if *deserializedEvent != *originalEvent {
+				t.Errorf("Event mismatch: expected %+v, got %+v", originalEvent, deserializedEvent)
+			}
+		})
+	}
+}
+
+func TestNewSerializerForSchema(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+
+	if _, err := c.CreateSchema("AuditAvro", "AVRO", gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditJSON", "JSON", gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create JSON schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditEvent", serializer.DataFormatProtobuf, auditProtoSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Protobuf schema: %v", err)
+	}
+
+	for schemaName, expected := range map[string]serializer.Serializer{
+		"AuditAvro":  &serializer.AvroSerializer{},
+		"AuditJSON":  &serializer.JsonSerializer{},
+		"AuditEvent": &serializer.ProtobufSerializer{},
+	} {
+		s, err := serializer.NewSerializerForSchema(c, schemaName)
+		if err != nil {
+			t.Fatalf("Failed to create serializer for %s: %v", schemaName, err)
+		}
+		if reflect.TypeOf(s) != reflect.TypeOf(expected) {
+			t.Errorf("Serializer mismatch for %s: expected %T, got %T", schemaName, expected, s)
+		}
+	}
+
+	s, err := serializer.NewSerializerForSchema(c, "AuditJSON")
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000}
+	data, err := s.Serialize(c, "AuditJSON", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	var decoded model.SalesforceAudit
+	if err := s.Deserialize(c, "AuditJSON", data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if decoded != *event {
+		t.Errorf("Event mismatch: expected %+v, got %+v", *event, decoded)
+	}
+
+	if _, err := serializer.NewSerializerForSchema(c, "Missing"); !client.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
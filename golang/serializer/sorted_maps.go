@@ -0,0 +1,234 @@
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// sortedMapEncoder produces Avro binary whose map entries are written in ascending key
+// order, so that equal records always encode to identical bytes. goavro writes map
+// entries in Go's randomized map iteration order, so this encoder walks records, arrays,
+// maps and unions itself and delegates every other type to a goavro codec for that type.
+type sortedMapEncoder struct {
+	schema interface{}
+	named  avroNames
+
+	mu     sync.RWMutex
+	codecs map[string]*goavro.Codec
+}
+
+// newSortedMapEncoder creates an encoder for the Avro schema definition
+func newSortedMapEncoder(definition string) (*sortedMapEncoder, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(definition), &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema definition: %w", err)
+	}
+	return newSortedMapEncoderForSchema(schema), nil
+}
+
+// newSortedMapEncoderForSchema creates an encoder for a parsed Avro schema, which must not
+// be modified afterwards
+func newSortedMapEncoderForSchema(schema interface{}) *sortedMapEncoder {
+	e := &sortedMapEncoder{
+		schema: schema,
+		named:  make(avroNames),
+		codecs: make(map[string]*goavro.Codec),
+	}
+	e.named.collect(schema, "")
+	return e
+}
+
+// BinaryFromNative appends the encoding of datum to buf
+func (e *sortedMapEncoder) BinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	return e.encode(buf, e.schema, "", datum)
+}
+
+func (e *sortedMapEncoder) encode(buf []byte, schema interface{}, namespace string, datum interface{}) ([]byte, error) {
+	switch s := schema.(type) {
+	case string:
+		if resolved, ok := e.named.resolve(s, namespace); ok {
+			return e.encode(buf, resolved, namespaceOf(fullName(resolved.(map[string]interface{}), namespace)), datum)
+		}
+		return e.encodeLeaf(buf, s, datum)
+	case []interface{}:
+		return e.encodeUnion(buf, s, namespace, datum)
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record", "error":
+			return e.encodeRecord(buf, s, namespace, datum)
+		case "array":
+			return e.encodeArray(buf, s, namespace, datum)
+		case "map":
+			return e.encodeMap(buf, s, namespace, datum)
+		}
+		if nested, ok := s["type"].(map[string]interface{}); ok {
+			return e.encode(buf, nested, namespace, datum)
+		}
+		if nested, ok := s["type"].([]interface{}); ok {
+			return e.encode(buf, nested, namespace, datum)
+		}
+		return e.encodeLeaf(buf, s, datum)
+	}
+	return nil, fmt.Errorf("unsupported Avro schema: %v", schema)
+}
+
+func (e *sortedMapEncoder) encodeRecord(buf []byte, schema map[string]interface{}, namespace string, datum interface{}) ([]byte, error) {
+	name := fullName(schema, namespace)
+	record, ok := datum.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot encode record %s: expected map[string]interface{}, received %T", name, datum)
+	}
+
+	fields, _ := schema["fields"].([]interface{})
+	var err error
+	for _, f := range fields {
+		field := f.(map[string]interface{})
+		fieldName, _ := field["name"].(string)
+		value, present := record[fieldName]
+		if !present {
+			if value, present = field["default"]; !present {
+				return nil, fmt.Errorf("cannot encode record %s: missing required field %q", name, fieldName)
+			}
+			value = defaultToNative(field["type"], value)
+		}
+		if buf, err = e.encode(buf, field["type"], namespaceOf(name), value); err != nil {
+			return nil, fmt.Errorf("cannot encode record %s field %q: %w", name, fieldName, err)
+		}
+	}
+	return buf, nil
+}
+
+func (e *sortedMapEncoder) encodeArray(buf []byte, schema map[string]interface{}, namespace string, datum interface{}) ([]byte, error) {
+	v := reflect.ValueOf(datum)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot encode array: expected slice, received %T", datum)
+	}
+	var err error
+	if v.Len() > 0 {
+		buf = appendLong(buf, int64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if buf, err = e.encode(buf, schema["items"], namespace, v.Index(i).Interface()); err != nil {
+				return nil, fmt.Errorf("cannot encode array item %d: %w", i, err)
+			}
+		}
+	}
+	return appendLong(buf, 0), nil
+}
+
+func (e *sortedMapEncoder) encodeMap(buf []byte, schema map[string]interface{}, namespace string, datum interface{}) ([]byte, error) {
+	v := reflect.ValueOf(datum)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("cannot encode map: expected map with string keys, received %T", datum)
+	}
+
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+
+	var err error
+	if len(keys) > 0 {
+		buf = appendLong(buf, int64(len(keys)))
+		for _, k := range keys {
+			buf = appendLong(buf, int64(len(k)))
+			buf = append(buf, k...)
+			value := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())).Interface()
+			if buf, err = e.encode(buf, schema["values"], namespace, value); err != nil {
+				return nil, fmt.Errorf("cannot encode map value for key %q: %w", k, err)
+			}
+		}
+	}
+	return appendLong(buf, 0), nil
+}
+
+func (e *sortedMapEncoder) encodeUnion(buf []byte, branches []interface{}, namespace string, datum interface{}) ([]byte, error) {
+	if datum == nil {
+		for i, branch := range branches {
+			if branch == "null" {
+				return appendLong(buf, int64(i)), nil
+			}
+		}
+		return nil, fmt.Errorf("cannot encode union: null is not a member of %v", branches)
+	}
+
+	wrapped, ok := datum.(map[string]interface{})
+	if !ok || len(wrapped) != 1 {
+		return nil, fmt.Errorf("cannot encode union: non-nil values must be wrapped with goavro.Union, received %T", datum)
+	}
+	for key, value := range wrapped {
+		for i, branch := range branches {
+			if e.named.branchMatches(branch, namespace, key) {
+				buf = appendLong(buf, int64(i))
+				return e.encode(buf, branch, namespace, value)
+			}
+		}
+		return nil, fmt.Errorf("cannot encode union: %q is not a member of %v", key, branches)
+	}
+	return buf, nil
+}
+
+// encodeLeaf encodes primitive, enum and fixed values with a goavro codec for that type
+func (e *sortedMapEncoder) encodeLeaf(buf []byte, schema interface{}, datum interface{}) ([]byte, error) {
+	// Schema maps are never modified after parsing, so their identity is a stable cache key
+	key := fmt.Sprint(schema)
+	if m, ok := schema.(map[string]interface{}); ok {
+		key = fmt.Sprintf("%p", m)
+	}
+
+	// The lock only guards the cache; codecs are safe for concurrent use, so encoding and
+	// the occasional duplicate compilation happen outside it
+	e.mu.RLock()
+	codec, ok := e.codecs[key]
+	e.mu.RUnlock()
+	if !ok {
+		spec, err := json.Marshal(schema)
+		if err != nil {
+			return nil, err
+		}
+		if codec, err = goavro.NewCodec(string(spec)); err != nil {
+			return nil, fmt.Errorf("failed to create Avro codec for %s: %w", spec, err)
+		}
+		e.mu.Lock()
+		if cached, ok := e.codecs[key]; ok {
+			codec = cached
+		} else {
+			e.codecs[key] = codec
+		}
+		e.mu.Unlock()
+	}
+	return codec.BinaryFromNative(buf, datum)
+}
+
+// defaultToNative converts a field default from its JSON form to the native form expected
+// by the encoder; union defaults apply to the first branch
+func defaultToNative(schema interface{}, value interface{}) interface{} {
+	if branches, ok := schema.([]interface{}); ok && len(branches) > 0 && value != nil {
+		name := branches[0]
+		if m, ok := name.(map[string]interface{}); ok {
+			name = m["type"]
+			if n, ok := m["name"]; ok {
+				name = n
+			}
+		}
+		if s, ok := name.(string); ok {
+			return goavro.Union(s, value)
+		}
+	}
+	return value
+}
+
+// appendLong appends the zig-zag varint encoding of n used for Avro longs and counts
+func appendLong(buf []byte, n int64) []byte {
+	u := uint64((n << 1) ^ (n >> 63))
+	for u >= 0x80 {
+		buf = append(buf, byte(u)|0x80)
+		u >>= 7
+	}
+	return append(buf, byte(u))
+}
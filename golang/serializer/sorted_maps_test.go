@@ -0,0 +1,100 @@
+package serializer_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+	"github.com/linkedin/goavro/v2"
+)
+
+const inventorySchema = `{
+	"type": "record",
+	"name": "Inventory",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "warehouse", "type": "string"},
+		{"name": "counts", "type": {"type": "map", "values": "long"}},
+		{"name": "bins", "type": {"type": "array", "items": {
+			"type": "record", "name": "Bin", "fields": [
+				{"name": "labels", "type": {"type": "map", "values": "string"}},
+				{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["SHELF", "FLOOR"]}}
+			]
+		}}},
+		{"name": "primary", "type": ["null", "Bin"], "default": null},
+		{"name": "updated", "type": {"type": "long", "logicalType": "timestamp-millis"}, "default": 0}
+	]
+}`
+
+func inventoryRecord() map[string]interface{} {
+	counts := make(map[string]interface{})
+	labels := make(map[string]interface{})
+	for i := 0; i < 32; i++ {
+		counts[fmt.Sprintf("sku-%02d", i)] = int64(i)
+		labels[fmt.Sprintf("label-%02d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	bin := map[string]interface{}{"labels": labels, "kind": "SHELF"}
+	return map[string]interface{}{
+		"warehouse": "north",
+		"counts":    counts,
+		"bins":      []interface{}{bin},
+		"primary":   goavro.Union("com.example.Bin", bin),
+	}
+}
+
+func TestSortedMapKeysEncodingIsStable(t *testing.T) {
+	first, err := serializer.EncodeWithSortedMapKeys(inventorySchema, inventoryRecord())
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		again, err := serializer.EncodeWithSortedMapKeys(inventorySchema, inventoryRecord())
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatalf("Encoding %d differs from the first encoding", i)
+		}
+	}
+
+	codec, err := goavro.NewCodec(inventorySchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	decoded, _, err := codec.NativeFromBinary(first)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	record := decoded.(map[string]interface{})
+	if !reflect.DeepEqual(record["counts"], inventoryRecord()["counts"]) {
+		t.Errorf("Map mismatch after decode: got %v", record["counts"])
+	}
+}
+
+func TestSortedMapKeysMatchesGoavroWithoutMaps(t *testing.T) {
+	codec, err := goavro.NewCodec(gluetest.SalesforceAuditAvroSchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	record := map[string]interface{}{
+		"eventId":      "event-12345",
+		"eventName":    "UserLogin",
+		"timestamp":    int64(1704067200000),
+		"eventDetails": "User logged in successfully",
+	}
+
+	expected, err := codec.BinaryFromNative(nil, record)
+	if err != nil {
+		t.Fatalf("Failed to encode with goavro: %v", err)
+	}
+	got, err := serializer.EncodeWithSortedMapKeys(gluetest.SalesforceAuditAvroSchema, record)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if !bytes.Equal(expected, got) {
+		t.Errorf("Encoding mismatch: expected %x, got %x", expected, got)
+	}
+}
@@ -0,0 +1,81 @@
+package serializer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+)
+
+// ErrUnregisteredType is returned by DeserializeTyped for a schema with no type registered
+var ErrUnregisteredType = errors.New("no type registered for schema")
+
+// TypeFactory returns a new value to deserialize a record into: a non-nil pointer, such as
+// &model.SalesforceAudit{}, as Serializer.Deserialize expects
+type TypeFactory func() interface{}
+
+// TypeRegistry maps schema names to the Go types their records are deserialized into, so
+// that consumers routing messages of several schemas get the right concrete type back
+// without knowing it at the call site. The zero value has no types registered and
+// deserializes with a SmartSerializer created on first use. A TypeRegistry is safe for
+// concurrent use.
+type TypeRegistry struct {
+	serializer     Serializer
+	serializerOnce sync.Once
+
+	mu        sync.RWMutex
+	factories map[string]TypeFactory
+}
+
+// NewTypeRegistry creates a TypeRegistry that deserializes with s, or with a new
+// SmartSerializer if s is nil
+func NewTypeRegistry(s Serializer) *TypeRegistry {
+	if s == nil {
+		s = NewSmartSerializer()
+	}
+	return &TypeRegistry{serializer: s}
+}
+
+// Register makes factory create the values records of schemaName are deserialized into,
+// replacing any factory registered for it before
+func (r *TypeRegistry) Register(schemaName string, factory TypeFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.factories == nil {
+		r.factories = make(map[string]TypeFactory)
+	}
+	r.factories[schemaName] = factory
+}
+
+// DeserializeTyped deserializes data into a new value from the factory registered for
+// schemaName and returns it. It returns an error wrapping ErrUnregisteredType if no factory
+// is registered for the schema.
+func (r *TypeRegistry) DeserializeTyped(c client.Registry, schemaName string, data []byte) (interface{}, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[schemaName]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredType, schemaName)
+	}
+
+	out := factory()
+	if err := checkOut(out); err != nil {
+		return nil, err
+	}
+	if err := r.defaultSerializer().Deserialize(c, schemaName, data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// defaultSerializer returns the serializer of r, setting it to a new SmartSerializer the
+// first time if r is the zero value, so that its schema caches are kept between calls
+func (r *TypeRegistry) defaultSerializer() Serializer {
+	r.serializerOnce.Do(func() {
+		if r.serializer == nil {
+			r.serializer = NewSmartSerializer()
+		}
+	})
+	return r.serializer
+}
@@ -0,0 +1,103 @@
+package serializer_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws-glue-schema-registry/golang/client"
+	"github.com/aws-glue-schema-registry/golang/internal/gluetest"
+	"github.com/aws-glue-schema-registry/golang/model"
+	"github.com/aws-glue-schema-registry/golang/serializer"
+)
+
+func TestTypeRegistryDeserializeTyped(t *testing.T) {
+	c := client.NewGlueSchemaRegistryClientWithAPI(gluetest.New(), "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("Order", client.DataFormatAvro, orderAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create Avro schema: %v", err)
+	}
+	if _, err := c.CreateSchema("AuditJSON", client.DataFormatJSON, gluetest.SalesforceAuditJSONSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create JSON schema: %v", err)
+	}
+
+	smart := serializer.NewSmartSerializer()
+	types := serializer.NewTypeRegistry(smart)
+	types.Register("SalesforceAudit", func() interface{} { return &model.SalesforceAudit{} })
+	types.Register("Order", func() interface{} { return &Order{} })
+	types.Register("AuditJSON", func() interface{} { return &model.SalesforceAudit{} })
+
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in"}
+	order := &Order{Origin: Origin{Source: "web"}, OrderID: "order-1", Quantity: 3, Tags: []string{"gift"}, Attributes: map[string]int64{}}
+	messages := []struct {
+		schemaName string
+		value      interface{}
+	}{
+		{"SalesforceAudit", event},
+		{"Order", order},
+		{"AuditJSON", event},
+	}
+	for _, m := range messages {
+		data, err := smart.Serialize(c, m.schemaName, m.value)
+		if err != nil {
+			t.Fatalf("Failed to serialize %s: %v", m.schemaName, err)
+		}
+		decoded, err := types.DeserializeTyped(c, m.schemaName, data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize %s: %v", m.schemaName, err)
+		}
+		if !reflect.DeepEqual(decoded, m.value) {
+			t.Errorf("Value mismatch for %s: expected %+v (%T), got %+v (%T)", m.schemaName, m.value, m.value, decoded, decoded)
+		}
+	}
+
+	if _, err := types.DeserializeTyped(c, "Unknown", nil); !errors.Is(err, serializer.ErrUnregisteredType) {
+		t.Errorf("Expected ErrUnregisteredType, got %v", err)
+	}
+	types.Register("Invalid", func() interface{} { return model.SalesforceAudit{} })
+	if _, err := types.DeserializeTyped(c, "Invalid", nil); err == nil {
+		t.Error("Expected an error for a factory that does not return a pointer")
+	}
+
+	// The zero value deserializes with a SmartSerializer of its own
+	var zero serializer.TypeRegistry
+	zero.Register("SalesforceAudit", func() interface{} { return &model.SalesforceAudit{} })
+	data, err := smart.Serialize(c, "SalesforceAudit", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if decoded, err := zero.DeserializeTyped(c, "SalesforceAudit", data); err != nil || !reflect.DeepEqual(decoded, event) {
+		t.Errorf("Expected the zero TypeRegistry to decode %+v, got %+v (error %v)", event, decoded, err)
+	}
+}
+
+func TestTypeRegistryDefaultSerializerKeepsItsCache(t *testing.T) {
+	fake := gluetest.New()
+	c := client.NewGlueSchemaRegistryClientWithAPI(fake, "test-registry")
+	defer c.Close()
+	if _, err := c.CreateSchema("SalesforceAudit", client.DataFormatAvro, gluetest.SalesforceAuditAvroSchema, client.CompatibilityBackward); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	event := &model.SalesforceAudit{EventID: "event-12345", EventName: "UserLogin", Timestamp: 1704067200000, EventDetails: "User logged in"}
+	data, err := serializer.NewSmartSerializer().Serialize(c, "SalesforceAudit", event)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+
+	for _, types := range []*serializer.TypeRegistry{serializer.NewTypeRegistry(nil), {}} {
+		types.Register("SalesforceAudit", func() interface{} { return &model.SalesforceAudit{} })
+		if _, err := types.DeserializeTyped(c, "SalesforceAudit", data); err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+		calls := fake.Calls("GetSchemaVersion")
+		if _, err := types.DeserializeTyped(c, "SalesforceAudit", data); err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+		if got := fake.Calls("GetSchemaVersion"); got != calls {
+			t.Errorf("GetSchemaVersion calls mismatch: expected %d, got %d", calls, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package testconfig
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// RequireAWSCredentials skips the test unless AWS credentials can be resolved from the
+// default provider chain, so that integration tests against a live registry do not fail
+// in CI environments without AWS access. Everything else runs offline against the
+// in-memory Glue fake.
+func RequireAWSCredentials(tb testing.TB) {
+	tb.Helper()
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(LoadConfig().AWSRegion)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err == nil {
+		_, err = sess.Config.Credentials.Get()
+	}
+	if err != nil {
+		tb.Skipf("Skipping integration test without AWS credentials: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // TestConfig holds test configuration values
@@ -15,15 +16,34 @@ type TestConfig struct {
 	JsonSchemaName string
 }
 
-var config *TestConfig
+var (
+	mu     sync.Mutex
+	config *TestConfig
+)
 
-// LoadConfig loads configuration from file and environment variables
+// LoadConfig loads configuration from file and environment variables. The result is
+// cached, and LoadConfig is safe to call from parallel tests.
 func LoadConfig() *TestConfig {
-	if config != nil {
-		return config
+	mu.Lock()
+	defer mu.Unlock()
+	if config == nil {
+		config = load()
 	}
+	return config
+}
 
-	config = &TestConfig{
+// Reload discards the cached configuration and loads it again from file and environment
+// variables, for tests that change them. Configurations returned earlier are not modified.
+func Reload() *TestConfig {
+	mu.Lock()
+	defer mu.Unlock()
+	config = load()
+	return config
+}
+
+// load reads the configuration from file and environment variables
+func load() *TestConfig {
+	cfg := &TestConfig{
 		RegistryName:    "glue-schema-registry-ansumanroy-6219",
 		AWSRegion:       "us-east-1",
 		AvroSchemaName:  "SalesforceAudit",
@@ -31,23 +51,23 @@ func LoadConfig() *TestConfig {
 	}
 
 	// Load from file
-	loadFromFile(config)
+	loadFromFile(cfg)
 
 	// Override with environment variables
 	if envValue := os.Getenv("GLUE_REGISTRY_NAME"); envValue != "" {
-		config.RegistryName = envValue
+		cfg.RegistryName = envValue
 	}
 	if envValue := os.Getenv("AWS_REGION"); envValue != "" {
-		config.AWSRegion = envValue
+		cfg.AWSRegion = envValue
 	}
 	if envValue := os.Getenv("SCHEMA_NAME_AVRO"); envValue != "" {
-		config.AvroSchemaName = envValue
+		cfg.AvroSchemaName = envValue
 	}
 	if envValue := os.Getenv("SCHEMA_NAME_JSON"); envValue != "" {
-		config.JsonSchemaName = envValue
+		cfg.JsonSchemaName = envValue
 	}
 
-	return config
+	return cfg
 }
 
 // loadFromFile loads configuration from test-config.properties file